@@ -6,6 +6,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/rs/zerolog/log"
@@ -41,6 +42,42 @@ type Specification struct {
 	// before the steadybit tooling, which stays alive to report and roll back.
 	// STEADYBIT_EXTENSION_FILL_MEMORY_OOM_SCORE_ADJ
 	FillMemoryOomScoreAdj int `json:"fillMemoryOomScoreAdj" split_words:"true" required:"false" default:"-996"`
+	// JournalDir is where the revert information of running attacks is kept. Entries left behind by a
+	// crashed extension are reverted on the next start, before the extension reports ready. The default
+	// lives in /tmp, which the helm chart backs with an emptyDir that survives container restarts.
+	// An empty value disables the journal.
+	// STEADYBIT_EXTENSION_JOURNAL_DIR
+	JournalDir string `json:"journalDir" split_words:"true" required:"false" default:"/tmp/steadybit-journal"`
+	// RevertAllToken protects the emergency /revert-all endpoint, which stops all active attacks when
+	// the agent can't. Requests must send it as bearer token. An empty value disables the endpoint.
+	// STEADYBIT_EXTENSION_REVERT_ALL_TOKEN
+	RevertAllToken string `json:"-" split_words:"true" required:"false"`
+	// CloudMetadataUrl is the base URL of the cloud instance metadata service (AWS, GCP and Azure all use
+	// the same link-local address). The host discovery reads zone, region, instance and account from it.
+	// An empty value disables it.
+	// STEADYBIT_EXTENSION_CLOUD_METADATA_URL
+	CloudMetadataUrl string `json:"cloudMetadataUrl" split_words:"true" required:"false" default:"http://169.254.169.254"`
+	// DiscoveryAttributesExcludesSystemd is the list of attributes excluded from discovered systemd services.
+	// STEADYBIT_EXTENSION_DISCOVERY_ATTRIBUTES_EXCLUDES_SYSTEMD
+	DiscoveryAttributesExcludesSystemd []string `json:"discoveryAttributesExcludesSystemd" split_words:"true" required:"false"`
+	// DiscoveryAttributesExcludesProcess is the list of attributes excluded from discovered processes.
+	// STEADYBIT_EXTENSION_DISCOVERY_ATTRIBUTES_EXCLUDES_PROCESS
+	DiscoveryAttributesExcludesProcess []string `json:"discoveryAttributesExcludesProcess" split_words:"true" required:"false"`
+	// DiscoveryProcessMinAge is the minimum age of processes to be discovered, to leave out short-lived ones.
+	// STEADYBIT_EXTENSION_DISCOVERY_PROCESS_MIN_AGE
+	DiscoveryProcessMinAge time.Duration `json:"discoveryProcessMinAge" split_words:"true" required:"false" default:"1m"`
+	// DiscoveryProcessCmdline adds the command line to discovered processes. Command lines may contain
+	// secrets, so it's off by default, and arguments looking like secrets are redacted.
+	// STEADYBIT_EXTENSION_DISCOVERY_PROCESS_CMDLINE
+	DiscoveryProcessCmdline bool `json:"discoveryProcessCmdline" split_words:"true" required:"false" default:"false"`
+	// NetworkPulse offers to pulse the network attacks, applying and reverting the fault repeatedly. The
+	// pulse is driven by status calls every second, so it's off by default.
+	// STEADYBIT_EXTENSION_NETWORK_PULSE
+	NetworkPulse bool `json:"networkPulse" split_words:"true" required:"false" default:"false"`
+	// FillPidsReserve is the number of PIDs the "exhaust process IDs" attack always leaves available, so
+	// e.g. the kubelet and sshd can still fork.
+	// STEADYBIT_EXTENSION_FILL_PIDS_RESERVE
+	FillPidsReserve int `json:"fillPidsReserve" split_words:"true" required:"false" default:"500"`
 }
 
 var (
@@ -66,6 +103,9 @@ func (s Specification) validate() error {
 	if s.FillMemoryOomScoreAdj < -1000 || s.FillMemoryOomScoreAdj > 1000 {
 		return fmt.Errorf("STEADYBIT_EXTENSION_FILL_MEMORY_OOM_SCORE_ADJ must be between -1000 and 1000, got %d", s.FillMemoryOomScoreAdj)
 	}
+	if s.FillPidsReserve < 0 {
+		return fmt.Errorf("STEADYBIT_EXTENSION_FILL_PIDS_RESERVE must not be negative, got %d", s.FillPidsReserve)
+	}
 	// FillMemoryReserve's format (bytes/K/M/G or %) is validated by the memfill binary; replicating
 	// its parser here would risk drifting from it.
 	return nil
@@ -19,3 +19,8 @@ func TestValidateFillMemoryOomScoreAdj(t *testing.T) {
 		assert.Contains(t, err.Error(), "FILL_MEMORY_OOM_SCORE_ADJ")
 	}
 }
+
+func TestValidateFillPidsReserve(t *testing.T) {
+	require.NoError(t, Specification{FillPidsReserve: 0}.validate())
+	require.EqualError(t, Specification{FillPidsReserve: -1}.validate(), "STEADYBIT_EXTENSION_FILL_PIDS_RESERVE must not be negative, got -1")
+}
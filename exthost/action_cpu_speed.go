@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_sdk"
@@ -17,9 +18,12 @@ import (
 	"github.com/steadybit/extension-kit/extutil"
 )
 
+var cpuSpeedActionID = fmt.Sprintf("%s.cpu-speed", BaseActionID)
+
 type cpuSpeedAction struct{}
 
 type CpuSpeedActionState struct {
+	ExecutionId     uuid.UUID
 	OriginalMinFreq uint64
 	OriginalMaxFreq uint64
 	NewMinFreq      uint64
@@ -35,7 +39,7 @@ var (
 )
 
 func NewCpuSpeedAction() action_kit_sdk.Action[CpuSpeedActionState] {
-	return &cpuSpeedAction{}
+	return journaled[CpuSpeedActionState](&cpuSpeedAction{})
 }
 
 func (a *cpuSpeedAction) NewEmptyState() CpuSpeedActionState {
@@ -44,13 +48,13 @@ func (a *cpuSpeedAction) NewEmptyState() CpuSpeedActionState {
 
 func (a *cpuSpeedAction) Describe() action_kit_api.ActionDescription {
 	return action_kit_api.ActionDescription{
-		Id:          fmt.Sprintf("%s.cpu-speed", BaseActionID),
+		Id:          cpuSpeedActionID,
 		Label:       "Change CPU Frequency",
 		Description: "Changes the CPU frequency limits for all cores for the given duration.",
 		Version:     extbuild.GetSemverVersionStringOrUnknown(),
 		Icon:        new(changeCPUSpeed),
 		TargetSelection: &action_kit_api.TargetSelection{
-			TargetType:         targetID + "(host.cpu.min_freq)",
+			TargetType:         requireCapability(capabilityCpufreq),
 			SelectionTemplates: &targetSelectionTemplates,
 		},
 		Technology:  new("Linux Host"),
@@ -159,6 +163,7 @@ func (a *cpuSpeedAction) Prepare(_ context.Context, state *CpuSpeedActionState,
 		}, nil
 	}
 
+	state.ExecutionId = request.ExecutionId
 	state.OriginalMinFreq = minFreq
 	state.OriginalMaxFreq = maxFreq
 
@@ -211,6 +216,10 @@ func (a *cpuSpeedAction) Start(_ context.Context, state *CpuSpeedActionState) (*
 		Uint64("max_freq", state.NewMaxFreq).
 		Msg("Setting CPU frequency limits")
 
+	// Journal before applying, so even partially applied limits are restored after a crash.
+	state.FreqsApplied = true
+	recordExecution(state.ExecutionId, cpuSpeedActionID, state)
+
 	if err := cpufreq.SetCPUFrequencyLimits(state.NewMinFreq, state.NewMaxFreq); err != nil {
 		log.Error().Err(err).Msg("Failed to set CPU frequency limits")
 		return nil, err
@@ -222,7 +231,6 @@ func (a *cpuSpeedAction) Start(_ context.Context, state *CpuSpeedActionState) (*
 		return nil, err
 	}
 
-	state.FreqsApplied = true
 	now := time.Now()
 	return &action_kit_api.StartResult{
 		Metrics: new([]action_kit_api.Metric{
@@ -277,6 +285,7 @@ func (a *cpuSpeedAction) Stop(_ context.Context, state *CpuSpeedActionState) (*a
 	}
 
 	state.FreqsApplied = false
+	forgetExecution(state.ExecutionId)
 	return &action_kit_api.StopResult{
 		Messages: new([]action_kit_api.Message{
 			{
@@ -4,7 +4,6 @@ package exthost
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -39,9 +38,9 @@ var _ action_kit_sdk.ActionWithStop[FillDiskActionState] = (*fillDiskAction)(nil
 var _ action_kit_sdk.ActionWithStatus[FillDiskActionState] = (*fillDiskAction)(nil)
 
 func NewFillDiskHostAction(r ociruntime.OciRuntime) action_kit_sdk.Action[FillDiskActionState] {
-	return &fillDiskAction{
+	return journaled[FillDiskActionState](&fillDiskAction{
 		ociRuntime: r,
-	}
+	})
 }
 
 func (a *fillDiskAction) NewEmptyState() FillDiskActionState {
@@ -230,6 +229,7 @@ func (a *fillDiskAction) Start(ctx context.Context, state *FillDiskActionState)
 	}
 
 	a.diskfills.Store(state.ExecutionId, diskFill)
+	recordExecution(state.ExecutionId, ID, state)
 
 	if err := diskFill.Start(); err != nil {
 		return nil, extension_kit.ToError("Failed to fill disk on host", err)
@@ -268,10 +268,11 @@ func (a *fillDiskAction) Status(_ context.Context, state *FillDiskActionState) (
 	}
 }
 
-func (a *fillDiskAction) Stop(_ context.Context, state *FillDiskActionState) (*action_kit_api.StopResult, error) {
-	if err := a.stopFillDiskHost(state.ExecutionId); err != nil {
+func (a *fillDiskAction) Stop(ctx context.Context, state *FillDiskActionState) (*action_kit_api.StopResult, error) {
+	if err := a.stopFillDiskHost(ctx, state); err != nil {
 		return nil, extension_kit.ToError("Failed to stop fill disk on host", err)
 	}
+	forgetExecution(state.ExecutionId)
 
 	return &action_kit_api.StopResult{
 		Messages: &[]action_kit_api.Message{
@@ -283,10 +284,16 @@ func (a *fillDiskAction) Stop(_ context.Context, state *FillDiskActionState) (*a
 	}, nil
 }
 
-func (a *fillDiskAction) stopFillDiskHost(executionId uuid.UUID) error {
-	s, ok := a.diskfills.LoadAndDelete(executionId)
+// stopFillDiskHost stops the disk fill. If it was started by a previous run, the diskfill handle died
+// together with that run and is recreated from the state.
+func (a *fillDiskAction) stopFillDiskHost(ctx context.Context, state *FillDiskActionState) error {
+	s, ok := a.diskfills.LoadAndDelete(state.ExecutionId)
 	if !ok {
-		return errors.New("no diskfill host found")
+		diskFill, err := a.diskfill(ctx, state.Sidecar, state.FillDiskOpts)
+		if err != nil {
+			return err
+		}
+		return diskFill.Stop()
 	}
 
 	return s.(diskfill.Diskfill).Stop()
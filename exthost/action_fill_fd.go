@@ -0,0 +1,314 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/fdfill"
+	"github.com/steadybit/extension-host/exthost/holder"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+	"golang.org/x/sync/syncmap"
+)
+
+type fillFdAction struct {
+	ociRuntime ociruntime.OciRuntime
+	fdfills    syncmap.Map
+}
+
+type FillFdActionState struct {
+	ExecutionId uuid.UUID
+	Sidecar     holder.SidecarOpts
+	FillFdOpts  fdfill.Opts
+	// FillReported is set once the holder stopped opening handles and this was reported
+	FillReported bool
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[FillFdActionState]           = (*fillFdAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[FillFdActionState] = (*fillFdAction)(nil)
+	_ action_kit_sdk.ActionWithStop[FillFdActionState]   = (*fillFdAction)(nil)
+)
+
+var fillFdActionID = fmt.Sprintf("%s.fill_fd", BaseActionID)
+
+func NewFillFdAction(r ociruntime.OciRuntime) action_kit_sdk.Action[FillFdActionState] {
+	return journaled[FillFdActionState](&fillFdAction{
+		ociRuntime: r,
+	})
+}
+
+func (a *fillFdAction) NewEmptyState() FillFdActionState {
+	return FillFdActionState{}
+}
+
+func (a *fillFdAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          fillFdActionID,
+		Label:       "Exhaust File Descriptors",
+		Description: "Opens and holds file handles on the host for the given duration.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(fillDiskIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         requireOciRuntime(),
+			SelectionTemplates: new(targetSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("Resource"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Widgets: new([]action_kit_api.Widget{
+			action_kit_api.LineChartWidget{
+				Type:  action_kit_api.ComSteadybitWidgetLineChart,
+				Title: "File Handles",
+				Identity: action_kit_api.LineChartWidgetIdentityConfig{
+					MetricName: "file_handles",
+					From:       "file_handles",
+					Mode:       action_kit_api.ComSteadybitWidgetLineChartIdentityModeWidgetPerValue,
+				},
+				Tooltip: new(action_kit_api.LineChartWidgetTooltipConfig{
+					MetricValueTitle: new("Handles"),
+				}),
+			},
+		}),
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long should the file handles be held?"),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(1),
+			},
+			{
+				Name:         "count",
+				Label:        "Number of file handles",
+				Description:  new("How many file handles should be opened? 0 opens handles until the maximum usage is reached. A single holder is limited by fs.nr_open."),
+				Type:         action_kit_api.ActionParameterTypeInteger,
+				DefaultValue: new("0"),
+				Required:     new(true),
+				Order:        new(2),
+			},
+			{
+				Name:         "maxUsage",
+				Label:        "Maximum usage of fs.file-max",
+				Description:  new("Stops opening handles once this share of the system-wide fs.file-max is allocated. 0 for no limit."),
+				Type:         action_kit_api.ActionParameterTypePercentage,
+				DefaultValue: new("90"),
+				MinValue:     new(0),
+				MaxValue:     new(100),
+				Required:     new(true),
+				Order:        new(3),
+			},
+		},
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("1s"),
+		}),
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (a *fillFdAction) Prepare(ctx context.Context, state *FillFdActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	opts := fdfill.Opts{
+		Count:    extutil.ToInt(request.Config["count"]),
+		MaxUsage: extutil.ToInt(request.Config["maxUsage"]),
+	}
+	if opts.Count <= 0 && opts.MaxUsage <= 0 {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  "Either the number of file handles or the maximum usage is required",
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	initProcess, err := ociruntime.ReadLinuxProcessInfo(ctx, 1, specs.PIDNamespace)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to prepare fill file descriptors settings.", err)
+	}
+
+	state.Sidecar = holder.SidecarOpts{
+		TargetProcess: initProcess,
+		Id:            fmt.Sprintf("%s-host", request.ExecutionId.String()[24:]),
+	}
+	state.FillFdOpts = opts
+	state.ExecutionId = request.ExecutionId
+	return nil, nil
+}
+
+func (a *fillFdAction) fdfill(ctx context.Context, sidecar holder.SidecarOpts, opts fdfill.Opts) (holder.Holder, error) {
+	if config.Config.DisableRunc {
+		return fdfill.NewFdfillProcess(opts)
+	}
+
+	return fdfill.NewFdfillRunc(ctx, a.ociRuntime, sidecar, opts)
+}
+
+func (a *fillFdAction) Start(ctx context.Context, state *FillFdActionState) (*action_kit_api.StartResult, error) {
+	fdFill, err := a.fdfill(ctx, state.Sidecar, state.FillFdOpts)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to prepare fill file descriptors on host", err)
+	}
+
+	a.fdfills.Store(state.ExecutionId, fdFill)
+	recordExecution(state.ExecutionId, fillFdActionID, state)
+
+	if err := fdFill.Start(); err != nil {
+		return nil, extension_kit.ToError("Failed to fill file descriptors on host", err)
+	}
+
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Starting fill file descriptors on host with args %s", strings.Join(fdFill.Args(), " ")),
+			},
+		}),
+	}, nil
+}
+
+func (a *fillFdAction) Status(_ context.Context, state *FillFdActionState) (*action_kit_api.StatusResult, error) {
+	metrics := fileNrMetrics()
+
+	exited, err := a.fillFdExited(state.ExecutionId)
+	if !exited {
+		return &action_kit_api.StatusResult{Completed: false, Metrics: &metrics, Messages: a.fillResult(state)}, nil
+	}
+
+	if err == nil {
+		return &action_kit_api.StatusResult{
+			Completed: true,
+			Metrics:   &metrics,
+			Messages: &[]action_kit_api.Message{
+				{
+					Level:   extutil.Ptr(action_kit_api.Info),
+					Message: "fill file descriptors on host stopped",
+				},
+			},
+		}, nil
+	}
+
+	errMessage := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		errMessage = fmt.Sprintf("%s\n%s", exitErr.Error(), string(exitErr.Stderr))
+	}
+	return &action_kit_api.StatusResult{
+		Completed: true,
+		Metrics:   &metrics,
+		Error: &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Failed),
+			Title:  fmt.Sprintf("Failed to fill file descriptors on host: %s", errMessage),
+		},
+	}, nil
+}
+
+func (a *fillFdAction) Stop(ctx context.Context, state *FillFdActionState) (*action_kit_api.StopResult, error) {
+	messages := make([]action_kit_api.Message, 0)
+
+	stopped, err := a.stopFillFdHost(ctx, state)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to stop fill file descriptors on host", err)
+	}
+	forgetExecution(state.ExecutionId)
+	if stopped {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Info),
+			Message: "Canceled fill file descriptors on host",
+		})
+	}
+
+	return &action_kit_api.StopResult{
+		Messages: &messages,
+	}, nil
+}
+
+// fillResult reports once how many handles the holder opened, as a warning if it hit the per-process or
+// system-wide limit before reaching the target.
+func (a *fillFdAction) fillResult(state *FillFdActionState) *[]action_kit_api.Message {
+	if state.FillReported {
+		return nil
+	}
+	s, ok := a.fdfills.Load(state.ExecutionId)
+	if !ok {
+		return nil
+	}
+	line, done, reached := fdfill.ParseOutput(s.(holder.Holder).Output())
+	if !done {
+		return nil
+	}
+	state.FillReported = true
+	if reached {
+		return &[]action_kit_api.Message{{Level: extutil.Ptr(action_kit_api.Info), Message: line}}
+	}
+	return &[]action_kit_api.Message{{
+		Level:   extutil.Ptr(action_kit_api.Warn),
+		Message: fmt.Sprintf("The file handle target wasn't reached, a single holder is limited by fs.nr_open: %s", line),
+	}}
+}
+
+func (a *fillFdAction) fillFdExited(executionId uuid.UUID) (bool, error) {
+	s, ok := a.fdfills.Load(executionId)
+	if !ok {
+		return true, nil
+	}
+	return s.(holder.Holder).Exited()
+}
+
+func (a *fillFdAction) stopFillFdHost(ctx context.Context, state *FillFdActionState) (bool, error) {
+	s, ok := a.fdfills.LoadAndDelete(state.ExecutionId)
+	if !ok {
+		// the handle is lost if the extension was restarted meanwhile, but the sidecar still holds on
+		if config.Config.DisableRunc {
+			return false, nil
+		}
+		return false, holder.DeleteSidecar(ctx, a.ociRuntime, state.Sidecar.Id)
+	}
+	return true, s.(holder.Holder).Stop()
+}
+
+func fileNrMetrics() []action_kit_api.Metric {
+	nr, err := readFileNr()
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to read fs.file-nr")
+		return []action_kit_api.Metric{}
+	}
+	now := time.Now()
+	return []action_kit_api.Metric{
+		{
+			Name:      new("file_handles"),
+			Metric:    map[string]string{"file_handles": "Allocated"},
+			Value:     float64(nr.Allocated),
+			Timestamp: now,
+		},
+		{
+			Name:      new("file_handles"),
+			Metric:    map[string]string{"file_handles": "Maximum"},
+			Value:     float64(nr.Max),
+			Timestamp: now,
+		},
+	}
+}
+
+var readFileNr = fdfill.ReadFileNr
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/extension-host/exthost/fdfill"
+	"github.com/steadybit/extension-host/exthost/holder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fillFdRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestActionFillFd_Prepare(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	action := &fillFdAction{}
+
+	state := action.NewEmptyState()
+	request := fillFdRequest(map[string]any{"duration": "10000", "count": 5000, "maxUsage": 80})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	assert.Equal(t, fdfill.Opts{Count: 5000, MaxUsage: 80}, state.FillFdOpts)
+	assert.Equal(t, request.ExecutionId.String()[24:]+"-host", state.Sidecar.Id)
+
+	request = fillFdRequest(map[string]any{"duration": "10000", "count": 0, "maxUsage": 0})
+	result, err = action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "Either the number of file handles or the maximum usage is required", result.Error.Title)
+}
+
+func TestActionFillFd_StatusReportsFileNr(t *testing.T) {
+	readFileNr = func() (fdfill.FileNr, error) { return fdfill.FileNr{Allocated: 8000, Max: 10000}, nil }
+	defer func() { readFileNr = fdfill.ReadFileNr }()
+
+	action := &fillFdAction{}
+	state := FillFdActionState{ExecutionId: uuid.New()}
+	status, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.True(t, status.Completed)
+	require.Len(t, *status.Metrics, 2)
+	assert.Equal(t, action_kit_api.Metric{Name: new("file_handles"), Metric: map[string]string{"file_handles": "Allocated"}, Value: 8000, Timestamp: (*status.Metrics)[0].Timestamp}, (*status.Metrics)[0])
+	assert.Equal(t, float64(10000), (*status.Metrics)[1].Value)
+}
+
+type fakeSidecarRuntime struct {
+	ociruntime.OciRuntime
+	deleted []string
+}
+
+func (r *fakeSidecarRuntime) Delete(_ context.Context, id string, _ bool) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func TestActionFillFd_StopDeletesSidecarOfLostHolder(t *testing.T) {
+	r := &fakeSidecarRuntime{}
+	action := &fillFdAction{ociRuntime: r}
+
+	state := FillFdActionState{ExecutionId: uuid.New(), Sidecar: holder.SidecarOpts{Id: "a1b2-host"}}
+	_, err := action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1b2-host"}, r.deleted)
+}
+
+type fakeHolder struct {
+	holder.Holder
+	output string
+}
+
+func (h *fakeHolder) Exited() (bool, error) { return false, nil }
+func (h *fakeHolder) Output() string        { return h.output }
+
+func TestActionFillFd_StatusWarnsIfTargetNotReached(t *testing.T) {
+	readFileNr = func() (fdfill.FileNr, error) { return fdfill.FileNr{Allocated: 1048600, Max: 9223372036854775807}, nil }
+	defer func() { readFileNr = fdfill.ReadFileNr }()
+
+	action := &fillFdAction{}
+	state := FillFdActionState{ExecutionId: uuid.New()}
+	h := &fakeHolder{}
+	action.fdfills.Store(state.ExecutionId, h)
+
+	status, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.False(t, status.Completed)
+	assert.Nil(t, status.Messages)
+
+	h.output = "holding 1048560 file handles, target not reached: too many open files\n"
+	status, err = action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	require.NotNil(t, status.Messages)
+	require.Len(t, *status.Messages, 1)
+	assert.Equal(t, action_kit_api.Warn, *(*status.Messages)[0].Level)
+	assert.Contains(t, (*status.Messages)[0].Message, "holding 1048560 file handles")
+	assert.True(t, state.FillReported)
+
+	status, err = action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Nil(t, status.Messages)
+}
@@ -40,9 +40,9 @@ var _ action_kit_sdk.ActionWithStop[FillMemoryActionState] = (*fillMemoryAction)
 var _ action_kit_sdk.ActionWithStatus[FillMemoryActionState] = (*fillMemoryAction)(nil)
 
 func NewFillMemoryHostAction(r ociruntime.OciRuntime) action_kit_sdk.Action[FillMemoryActionState] {
-	return &fillMemoryAction{
+	return tracked[FillMemoryActionState](&fillMemoryAction{
 		ociRuntime: r,
-	}
+	})
 }
 
 func (a *fillMemoryAction) NewEmptyState() FillMemoryActionState {
@@ -193,6 +193,7 @@ func (a *fillMemoryAction) Start(_ context.Context, state *FillMemoryActionState
 	}
 
 	a.memfills.Store(state.ExecutionId, memFill)
+	trackExecution(state.ExecutionId, a.Describe().Id, state)
 
 	if err := memFill.Start(); err != nil {
 		return nil, extension_kit.ToError("Failed to fill memory on host", err)
@@ -259,7 +260,12 @@ func (a *fillMemoryAction) Status(_ context.Context, state *FillMemoryActionStat
 func (a *fillMemoryAction) Stop(_ context.Context, state *FillMemoryActionState) (*action_kit_api.StopResult, error) {
 	messages := make([]action_kit_api.Message, 0)
 
-	if a.stopFillMemoryHost(state.ExecutionId) {
+	stopped, err := a.stopFillMemoryHost(state.ExecutionId)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to stop fill memory on host", err)
+	}
+	forgetExecution(state.ExecutionId)
+	if stopped {
 		messages = append(messages, action_kit_api.Message{
 			Level:   extutil.Ptr(action_kit_api.Info),
 			Message: "Canceled fill memory on host",
@@ -279,11 +285,15 @@ func (a *fillMemoryAction) fillMemoryExited(executionId uuid.UUID) (bool, error)
 	return s.(memfill.Memfill).Exited()
 }
 
-func (a *fillMemoryAction) stopFillMemoryHost(executionId uuid.UUID) bool {
+func (a *fillMemoryAction) stopFillMemoryHost(executionId uuid.UUID) (bool, error) {
 	s, ok := a.memfills.LoadAndDelete(executionId)
 	if !ok {
-		return false
+		return false, nil
+	}
+	if err := s.(memfill.Memfill).Stop(); err != nil {
+		// kept, so stopping it can be retried
+		a.memfills.Store(executionId, s)
+		return false, err
 	}
-	err := s.(memfill.Memfill).Stop()
-	return err == nil
+	return true, nil
 }
@@ -0,0 +1,296 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/holder"
+	"github.com/steadybit/extension-host/exthost/pidfill"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+	"golang.org/x/sync/syncmap"
+)
+
+type fillPidsAction struct {
+	ociRuntime ociruntime.OciRuntime
+	pidfills   syncmap.Map
+}
+
+type FillPidsActionState struct {
+	ExecutionId  uuid.UUID
+	Sidecar      holder.SidecarOpts
+	FillPidsOpts pidfill.Opts
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[FillPidsActionState]           = (*fillPidsAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[FillPidsActionState] = (*fillPidsAction)(nil)
+	_ action_kit_sdk.ActionWithStop[FillPidsActionState]   = (*fillPidsAction)(nil)
+)
+
+var fillPidsActionID = fmt.Sprintf("%s.fill_pids", BaseActionID)
+
+func NewFillPidsAction(r ociruntime.OciRuntime) action_kit_sdk.Action[FillPidsActionState] {
+	return journaled[FillPidsActionState](&fillPidsAction{
+		ociRuntime: r,
+	})
+}
+
+func (a *fillPidsAction) NewEmptyState() FillPidsActionState {
+	return FillPidsActionState{}
+}
+
+func (a *fillPidsAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          fillPidsActionID,
+		Label:       "Exhaust Process IDs",
+		Description: "Spawns idle processes or threads on the host until the PID limit is almost reached and holds them for the given duration.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(stressCPUIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         requireOciRuntime(),
+			SelectionTemplates: new(targetSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("Resource"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Widgets: new([]action_kit_api.Widget{
+			action_kit_api.LineChartWidget{
+				Type:  action_kit_api.ComSteadybitWidgetLineChart,
+				Title: "Process IDs",
+				Identity: action_kit_api.LineChartWidgetIdentityConfig{
+					MetricName: "pids",
+					From:       "pids",
+					Mode:       action_kit_api.ComSteadybitWidgetLineChartIdentityModeWidgetPerValue,
+				},
+				Tooltip: new(action_kit_api.LineChartWidgetTooltipConfig{
+					MetricValueTitle: new("PIDs"),
+				}),
+			},
+		}),
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long should the process IDs be held?"),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(1),
+			},
+			{
+				Name:         "mode",
+				Label:        "Mode",
+				Description:  new("Spawn idle processes or threads. Both count against kernel.pid_max and pids.max, threads are cheaper."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(string(pidfill.ModeProcesses)),
+				Required:     new(true),
+				Order:        new(2),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "Processes", Value: string(pidfill.ModeProcesses)},
+					action_kit_api.ExplicitParameterOption{Label: "Threads", Value: string(pidfill.ModeThreads)},
+				}),
+			},
+			{
+				Name:         "maxUsage",
+				Label:        "Usage",
+				Description:  new("Spawns until this share of the PID limit is used, the lower of kernel.pid_max, kernel.threads-max and pids.max. The configured reserve is left available in any case."),
+				Type:         action_kit_api.ActionParameterTypePercentage,
+				DefaultValue: new("90"),
+				MinValue:     new(1),
+				MaxValue:     new(100),
+				Required:     new(true),
+				Order:        new(3),
+			},
+		},
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("1s"),
+		}),
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (a *fillPidsAction) Prepare(ctx context.Context, state *FillPidsActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	opts := pidfill.Opts{
+		Mode:     pidfill.Mode(extutil.ToString(request.Config["mode"])),
+		MaxUsage: extutil.ToInt(request.Config["maxUsage"]),
+		// leave room for the kubelet and sshd, and let the OOM killer pick the spawned processes before
+		// the steadybit tooling, like fill memory does
+		Reserve:     config.Config.FillPidsReserve,
+		OomScoreAdj: config.Config.FillMemoryOomScoreAdj,
+	}
+	if opts.Mode != pidfill.ModeProcesses && opts.Mode != pidfill.ModeThreads {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid mode '%s'", opts.Mode),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	initProcess, err := ociruntime.ReadLinuxProcessInfo(ctx, 1, specs.PIDNamespace)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to prepare exhaust process IDs settings.", err)
+	}
+
+	state.Sidecar = holder.SidecarOpts{
+		TargetProcess: initProcess,
+		Id:            fmt.Sprintf("%s-host", request.ExecutionId.String()[24:]),
+	}
+	state.FillPidsOpts = opts
+	state.ExecutionId = request.ExecutionId
+	return nil, nil
+}
+
+func (a *fillPidsAction) pidfill(ctx context.Context, sidecar holder.SidecarOpts, opts pidfill.Opts) (holder.Holder, error) {
+	if config.Config.DisableRunc {
+		return pidfill.NewPidfillProcess(opts)
+	}
+
+	return pidfill.NewPidfillRunc(ctx, a.ociRuntime, sidecar, opts)
+}
+
+func (a *fillPidsAction) Start(ctx context.Context, state *FillPidsActionState) (*action_kit_api.StartResult, error) {
+	pidFill, err := a.pidfill(ctx, state.Sidecar, state.FillPidsOpts)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to prepare exhaust process IDs on host", err)
+	}
+
+	a.pidfills.Store(state.ExecutionId, pidFill)
+	recordExecution(state.ExecutionId, fillPidsActionID, state)
+
+	if err := pidFill.Start(); err != nil {
+		return nil, extension_kit.ToError("Failed to exhaust process IDs on host", err)
+	}
+
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Starting exhaust process IDs on host with args %s", strings.Join(pidFill.Args(), " ")),
+			},
+		}),
+	}, nil
+}
+
+func (a *fillPidsAction) Status(_ context.Context, state *FillPidsActionState) (*action_kit_api.StatusResult, error) {
+	metrics := pidMetrics()
+
+	exited, err := a.fillPidsExited(state.ExecutionId)
+	if !exited {
+		return &action_kit_api.StatusResult{Completed: false, Metrics: &metrics}, nil
+	}
+
+	if err == nil {
+		return &action_kit_api.StatusResult{
+			Completed: true,
+			Metrics:   &metrics,
+			Messages: &[]action_kit_api.Message{
+				{
+					Level:   extutil.Ptr(action_kit_api.Info),
+					Message: "exhaust process IDs on host stopped",
+				},
+			},
+		}, nil
+	}
+
+	errMessage := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		errMessage = fmt.Sprintf("%s\n%s", exitErr.Error(), string(exitErr.Stderr))
+	}
+	return &action_kit_api.StatusResult{
+		Completed: true,
+		Metrics:   &metrics,
+		Error: &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Failed),
+			Title:  fmt.Sprintf("Failed to exhaust process IDs on host: %s", errMessage),
+		},
+	}, nil
+}
+
+func (a *fillPidsAction) Stop(ctx context.Context, state *FillPidsActionState) (*action_kit_api.StopResult, error) {
+	messages := make([]action_kit_api.Message, 0)
+
+	stopped, err := a.stopFillPidsHost(ctx, state)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to stop exhaust process IDs on host", err)
+	}
+	forgetExecution(state.ExecutionId)
+	if stopped {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Info),
+			Message: "Canceled exhaust process IDs on host",
+		})
+	}
+
+	return &action_kit_api.StopResult{
+		Messages: &messages,
+	}, nil
+}
+
+func (a *fillPidsAction) fillPidsExited(executionId uuid.UUID) (bool, error) {
+	s, ok := a.pidfills.Load(executionId)
+	if !ok {
+		return true, nil
+	}
+	return s.(holder.Holder).Exited()
+}
+
+func (a *fillPidsAction) stopFillPidsHost(ctx context.Context, state *FillPidsActionState) (bool, error) {
+	s, ok := a.pidfills.LoadAndDelete(state.ExecutionId)
+	if !ok {
+		// the handle is lost if the extension was restarted meanwhile, but the sidecar still holds on
+		if config.Config.DisableRunc {
+			return false, nil
+		}
+		return false, holder.DeleteSidecar(ctx, a.ociRuntime, state.Sidecar.Id)
+	}
+	return true, s.(holder.Holder).Stop()
+}
+
+func pidMetrics() []action_kit_api.Metric {
+	usage, err := readPidUsage()
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to read the PID usage")
+		return []action_kit_api.Metric{}
+	}
+	now := time.Now()
+	return []action_kit_api.Metric{
+		{
+			Name:      new("pids"),
+			Metric:    map[string]string{"pids": "Used"},
+			Value:     float64(usage.Used),
+			Timestamp: now,
+		},
+		{
+			Name:      new("pids"),
+			Metric:    map[string]string{"pids": usage.Source},
+			Value:     float64(usage.Limit),
+			Timestamp: now,
+		},
+	}
+}
+
+var readPidUsage = pidfill.ReadKernelUsage
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/pidfill"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fillPidsRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestActionFillPids_Prepare(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.FillPidsReserve = 500
+	config.Config.FillMemoryOomScoreAdj = -996
+	action := &fillPidsAction{}
+
+	state := action.NewEmptyState()
+	request := fillPidsRequest(map[string]any{"duration": "10000", "mode": "threads", "maxUsage": 80})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	assert.Equal(t, pidfill.Opts{Mode: pidfill.ModeThreads, MaxUsage: 80, Reserve: 500, OomScoreAdj: -996}, state.FillPidsOpts)
+
+	request = fillPidsRequest(map[string]any{"duration": "10000", "mode": "forks", "maxUsage": 80})
+	result, err = action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "Invalid mode 'forks'", result.Error.Title)
+}
+
+func TestActionFillPids_StatusReportsUsage(t *testing.T) {
+	readPidUsage = func() (pidfill.Usage, error) {
+		return pidfill.Usage{Used: 30000, Limit: 32768, Source: "kernel.pid_max"}, nil
+	}
+	defer func() { readPidUsage = pidfill.ReadKernelUsage }()
+
+	action := &fillPidsAction{}
+	state := FillPidsActionState{ExecutionId: uuid.New()}
+	status, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	require.Len(t, *status.Metrics, 2)
+	assert.Equal(t, map[string]string{"pids": "Used"}, (*status.Metrics)[0].Metric)
+	assert.Equal(t, float64(30000), (*status.Metrics)[0].Value)
+	assert.Equal(t, map[string]string{"pids": "kernel.pid_max"}, (*status.Metrics)[1].Metric)
+	assert.Equal(t, float64(32768), (*status.Metrics)[1].Value)
+}
@@ -0,0 +1,363 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-host/exthost/hostinfo"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+const (
+	freezeMethodAuto   = "auto"
+	freezeMethodSignal = "signal"
+	freezeMethodCgroup = "cgroup"
+)
+
+type freezeProcessAction struct {
+}
+
+type FreezeProcessActionState struct {
+	ExecutionId   uuid.UUID
+	ProcessFilter string
+	MatchMode     string
+	// Method is either signal (SIGSTOP/SIGCONT) or cgroup (cgroup.freeze)
+	Method    string
+	Cgroups   []string
+	Processes []FrozenProcess
+	Applied   bool
+}
+
+type FrozenProcess struct {
+	Pid  int
+	Name string
+	// StartedAt guards against thawing a process which reused the PID of a frozen one
+	StartedAt time.Time
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[FreezeProcessActionState]         = (*freezeProcessAction)(nil)
+	_ action_kit_sdk.ActionWithStop[FreezeProcessActionState] = (*freezeProcessAction)(nil)
+)
+
+func NewFreezeProcessAction() action_kit_sdk.Action[FreezeProcessActionState] {
+	return journaled[FreezeProcessActionState](&freezeProcessAction{})
+}
+
+func (a *freezeProcessAction) NewEmptyState() FreezeProcessActionState {
+	return FreezeProcessActionState{}
+}
+
+func (a *freezeProcessAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          freezeProcessActionID,
+		Label:       "Freeze Processes",
+		Description: "Suspends the targeted processes for the given duration without killing them.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(stopProcessIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         targetID,
+			SelectionTemplates: new(targetSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("State"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long should the processes be frozen?"),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(0),
+			},
+			{
+				Name:        "process",
+				Label:       "Process",
+				Description: new("PID or string to match the process name or command. How it is matched is defined by 'Match by'."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(true),
+				Order:       new(1),
+			},
+			processMatchModeParameter(new(2)),
+			{
+				Name:         "method",
+				Label:        "Method",
+				Description:  new("Suspend the processes with SIGSTOP, or freeze their whole cgroup (cgroup v2 only, requires matching by a systemd service or scope, slices are refused). Auto uses the cgroup freezer when possible."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(freezeMethodAuto),
+				Required:     new(true),
+				Advanced:     new(true),
+				Order:        new(3),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "Auto", Value: freezeMethodAuto},
+					action_kit_api.ExplicitParameterOption{Label: "SIGSTOP / SIGCONT", Value: freezeMethodSignal},
+					action_kit_api.ExplicitParameterOption{Label: "cgroup freezer", Value: freezeMethodCgroup},
+				}),
+			},
+		},
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (a *freezeProcessAction) Prepare(_ context.Context, state *FreezeProcessActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	selector, err := stopprocess.NewSelector(extutil.ToString(request.Config["matchMode"]), extutil.ToString(request.Config["process"]))
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid process selection: %s", err),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	cgroupV2 := false
+	if version, err := cgroupVersion(); err == nil {
+		cgroupV2 = version == "v2"
+	}
+	method := extutil.ToString(request.Config["method"])
+	switch method {
+	case "", freezeMethodAuto:
+		method = freezeMethodSignal
+		if selector.Mode == stopprocess.MatchCgroup && cgroupV2 && stopprocess.IsLeafCgroup(selector.Value) {
+			method = freezeMethodCgroup
+		}
+	case freezeMethodSignal:
+	case freezeMethodCgroup:
+		if selector.Mode != stopprocess.MatchCgroup || !cgroupV2 {
+			return &action_kit_api.PrepareResult{
+				Error: new(action_kit_api.ActionKitError{
+					Title:  "The cgroup freezer requires cgroup v2 and matching by systemd unit or cgroup",
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+		// a slice holds whole groups of services, e.g. all pods of a node
+		if !stopprocess.IsLeafCgroup(selector.Value) {
+			return &action_kit_api.PrepareResult{
+				Error: new(action_kit_api.ActionKitError{
+					Title:  fmt.Sprintf("The cgroup freezer requires a systemd service or scope, %s is none", selector.Value),
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+		if strings.HasPrefix(selector.Value, "/") {
+			if err := stopprocess.CheckCgroup(selector.Value); err != nil {
+				return &action_kit_api.PrepareResult{
+					Error: new(action_kit_api.ActionKitError{
+						Title:  fmt.Sprintf("Invalid cgroup: %s", err),
+						Status: extutil.Ptr(action_kit_api.Errored),
+					}),
+				}, nil
+			}
+		}
+	default:
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid method '%s'", method),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	if method == freezeMethodCgroup {
+		// freezing a cgroup suspends all of its processes, so it must not contain any protected one
+		protected, err := findProtectedProcesses(selector)
+		if err != nil {
+			return nil, err
+		}
+		if len(protected) > 0 {
+			return &action_kit_api.PrepareResult{
+				Error: new(action_kit_api.ActionKitError{
+					Title:  fmt.Sprintf("The cgroup contains the protected process %d (%s)", protected[0].Pid, protected[0].Name),
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+	}
+
+	state.ExecutionId = request.ExecutionId
+	state.ProcessFilter = selector.Value
+	state.MatchMode = selector.Mode
+	state.Method = method
+	return nil, nil
+}
+
+func (a *freezeProcessAction) Start(_ context.Context, state *FreezeProcessActionState) (*action_kit_api.StartResult, error) {
+	selector, err := stopprocess.NewSelector(state.MatchMode, state.ProcessFilter)
+	if err != nil {
+		return nil, err
+	}
+	processes, err := findProcesses(selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(processes) == 0 {
+		return &action_kit_api.StartResult{
+			Messages: new([]action_kit_api.Message{
+				{
+					Level:   extutil.Ptr(action_kit_api.Warn),
+					Message: fmt.Sprintf("No processes matching %s (%s) found", state.ProcessFilter, state.MatchMode),
+				},
+			}),
+		}, nil
+	}
+
+	state.Processes = make([]FrozenProcess, 0, len(processes))
+	for _, p := range processes {
+		state.Processes = append(state.Processes, FrozenProcess{Pid: p.Pid, Name: p.Name, StartedAt: p.StartedAt})
+	}
+	if state.Method == freezeMethodCgroup {
+		state.Cgroups = cgroupsToFreeze(selector, processes)
+		if len(state.Cgroups) == 0 {
+			return nil, fmt.Errorf("no cgroup of %s found within the extension's cgroup namespace", state.ProcessFilter)
+		}
+	}
+
+	// journal before freezing, so a restarted extension thaws them in any case
+	state.Applied = true
+	recordExecution(state.ExecutionId, freezeProcessActionID, state)
+
+	if err := a.freeze(state); err != nil {
+		if thawErr := a.thaw(state); thawErr != nil {
+			return nil, errors.Join(err, thawErr)
+		}
+		state.Applied = false
+		forgetExecution(state.ExecutionId)
+		return nil, err
+	}
+
+	names := make([]string, 0, len(state.Processes))
+	for _, p := range state.Processes {
+		names = append(names, fmt.Sprintf("%s (%d)", p.Name, p.Pid))
+	}
+	message := fmt.Sprintf("Suspended %d processes: %s", len(names), strings.Join(names, ", "))
+	if state.Method == freezeMethodCgroup {
+		message = fmt.Sprintf("Froze cgroups %s with %d processes: %s", strings.Join(state.Cgroups, ", "), len(names), strings.Join(names, ", "))
+	}
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: message,
+			},
+		}),
+	}, nil
+}
+
+func (a *freezeProcessAction) Stop(_ context.Context, state *FreezeProcessActionState) (*action_kit_api.StopResult, error) {
+	if !state.Applied {
+		return nil, nil
+	}
+
+	if err := a.thaw(state); err != nil {
+		return nil, err
+	}
+	state.Applied = false
+	forgetExecution(state.ExecutionId)
+
+	return &action_kit_api.StopResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Resumed %d processes", len(state.Processes)),
+			},
+		}),
+	}, nil
+}
+
+func (a *freezeProcessAction) freeze(state *FreezeProcessActionState) error {
+	if state.Method == freezeMethodCgroup {
+		for _, cgroup := range state.Cgroups {
+			if err := freezeCgroup(cgroup, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var errs error
+	for _, p := range state.Processes {
+		if err := sendSignal(p.Pid, syscall.SIGSTOP); err != nil && !errors.Is(err, syscall.ESRCH) {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// thaw resumes all processes, even if some of them fail, so a single vanished process doesn't leave the
+// others suspended.
+func (a *freezeProcessAction) thaw(state *FreezeProcessActionState) error {
+	var errs error
+	if state.Method == freezeMethodCgroup {
+		for _, cgroup := range state.Cgroups {
+			if err := freezeCgroup(cgroup, false); err != nil {
+				errs = errors.Join(errs, err)
+			}
+		}
+		return errs
+	}
+
+	for _, p := range state.Processes {
+		if !isSameProcess(p.Pid, p.StartedAt) {
+			continue
+		}
+		if err := sendSignal(p.Pid, syscall.SIGCONT); err != nil && !errors.Is(err, syscall.ESRCH) {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// cgroupsToFreeze returns the cgroup path given by the selector, or the cgroups of the systemd unit the
+// selector matches. Cgroups outside the extension's cgroup namespace can't be frozen and are left out.
+func cgroupsToFreeze(selector stopprocess.Selector, processes []stopprocess.Process) []string {
+	if strings.HasPrefix(selector.Value, "/") {
+		return []string{strings.TrimSuffix(selector.Value, "/")}
+	}
+	var cgroups []string
+	for _, p := range processes {
+		i := strings.Index(p.Cgroup+"/", "/"+selector.Value+"/")
+		if i < 0 {
+			continue
+		}
+		cgroup := p.Cgroup[:i+len(selector.Value)+1]
+		if stopprocess.CheckCgroup(cgroup) != nil {
+			log.Debug().Str("cgroup", cgroup).Msg("Skipping cgroup outside of the cgroup namespace")
+			continue
+		}
+		if !slices.Contains(cgroups, cgroup) {
+			cgroups = append(cgroups, cgroup)
+		}
+	}
+	return cgroups
+}
+
+var (
+	sendSignal             = stopprocess.SendSignal
+	freezeCgroup           = stopprocess.FreezeCgroup
+	isSameProcess          = stopprocess.IsSameProcess
+	findProtectedProcesses = stopprocess.FindProtectedProcesses
+	cgroupVersion          = hostinfo.CgroupVersion
+)
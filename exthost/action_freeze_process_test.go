@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-host/exthost/hostinfo"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFreezer struct {
+	calls []string
+}
+
+func (f *fakeFreezer) install(t *testing.T, processes []stopprocess.Process) {
+	findProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) { return processes, nil }
+	findProtectedProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) { return nil, nil }
+	sendSignal = func(pid int, signal syscall.Signal) error {
+		f.calls = append(f.calls, fmt.Sprintf("%s %d", signal, pid))
+		return nil
+	}
+	freezeCgroup = func(cgroup string, frozen bool) error {
+		f.calls = append(f.calls, fmt.Sprintf("freeze %s %t", cgroup, frozen))
+		return nil
+	}
+	isSameProcess = func(int, time.Time) bool { return true }
+	cgroupVersion = func() (string, error) { return "v2", nil }
+	t.Cleanup(func() {
+		findProcesses = stopprocess.FindProcesses
+		findProtectedProcesses = stopprocess.FindProtectedProcesses
+		sendSignal = stopprocess.SendSignal
+		freezeCgroup = stopprocess.FreezeCgroup
+		isSameProcess = stopprocess.IsSameProcess
+		cgroupVersion = hostinfo.CgroupVersion
+	})
+}
+
+func prepareFreezeRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target: new(action_kit_api.Target{
+			Attributes: map[string][]string{
+				"host.hostname": {"myhostname"},
+			},
+		}),
+	}
+}
+
+func TestActionFreezeProcess_Signal(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeFreezer{}
+	fake.install(t, []stopprocess.Process{{Pid: 10, Name: "worker"}, {Pid: 11, Name: "worker"}})
+
+	action := &freezeProcessAction{}
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, prepareFreezeRequest(map[string]any{"duration": "10000", "process": "worker", "matchMode": "name"}))
+	require.NoError(t, err)
+	require.Nil(t, result)
+	assert.Equal(t, freezeMethodSignal, state.Method)
+
+	start, err := action.Start(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, "Suspended 2 processes: worker (10), worker (11)", (*start.Messages)[0].Message)
+	assert.Equal(t, []string{"stopped (signal) 10", "stopped (signal) 11"}, fake.calls)
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stopped (signal) 10", "stopped (signal) 11", "continued 10", "continued 11"}, fake.calls)
+	assert.False(t, state.Applied)
+}
+
+func TestActionFreezeProcess_Cgroup(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeFreezer{}
+	fake.install(t, []stopprocess.Process{
+		{Pid: 10, Name: "orders", Cgroup: "/system.slice/orders.service", SystemdUnit: "orders.service"},
+		{Pid: 11, Name: "orders-sidecar", Cgroup: "/system.slice/orders.service/sidecar", SystemdUnit: "orders.service"},
+	})
+
+	action := &freezeProcessAction{}
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, prepareFreezeRequest(map[string]any{"duration": "10000", "process": "orders.service", "matchMode": "cgroup"}))
+	require.NoError(t, err)
+	require.Nil(t, result)
+	assert.Equal(t, freezeMethodCgroup, state.Method)
+
+	_, err = action.Start(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/system.slice/orders.service"}, state.Cgroups)
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"freeze /system.slice/orders.service true", "freeze /system.slice/orders.service false"}, fake.calls)
+}
+
+func TestActionFreezeProcess_RefusesProtectedCgroup(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeFreezer{}
+	fake.install(t, nil)
+	findProtectedProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) {
+		return []stopprocess.Process{{Pid: 1, Name: "systemd"}}, nil
+	}
+
+	action := &freezeProcessAction{}
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, prepareFreezeRequest(map[string]any{"duration": "10000", "process": "/init.scope", "matchMode": "cgroup", "method": "cgroup"}))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "The cgroup contains the protected process 1 (systemd)", result.Error.Title)
+}
+
+func TestActionFreezeProcess_RefusesSlices(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeFreezer{}
+	fake.install(t, nil)
+
+	action := &freezeProcessAction{}
+	for _, cgroup := range []string{"/system.slice", "kubepods.slice"} {
+		state := action.NewEmptyState()
+		result, err := action.Prepare(context.Background(), &state, prepareFreezeRequest(map[string]any{"duration": "10000", "process": cgroup, "matchMode": "cgroup", "method": "cgroup"}))
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, fmt.Sprintf("The cgroup freezer requires a systemd service or scope, %s is none", cgroup), result.Error.Title)
+	}
+
+	// auto falls back to signals for slices
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, prepareFreezeRequest(map[string]any{"duration": "10000", "process": "/system.slice", "matchMode": "cgroup"}))
+	require.NoError(t, err)
+	require.Nil(t, result)
+	assert.Equal(t, freezeMethodSignal, state.Method)
+}
+
+func TestActionFreezeProcess_RefusesCgroupsOutsideOfNamespace(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeFreezer{}
+	fake.install(t, []stopprocess.Process{
+		{Pid: 10, Name: "orders", Cgroup: "/../../system.slice/orders.service", SystemdUnit: "orders.service"},
+	})
+
+	action := &freezeProcessAction{}
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, prepareFreezeRequest(map[string]any{"duration": "10000", "process": "/../../system.slice/orders.service", "matchMode": "cgroup", "method": "cgroup"}))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "Invalid cgroup: cgroup /../../system.slice/orders.service is outside of the extension's cgroup namespace", result.Error.Title)
+
+	state = action.NewEmptyState()
+	result, err = action.Prepare(context.Background(), &state, prepareFreezeRequest(map[string]any{"duration": "10000", "process": "orders.service", "matchMode": "cgroup"}))
+	require.NoError(t, err)
+	require.Nil(t, result)
+	_, err = action.Start(context.Background(), &state)
+	assert.EqualError(t, err, "no cgroup of orders.service found within the extension's cgroup namespace")
+	assert.Empty(t, fake.calls)
+	assert.False(t, state.Applied)
+}
@@ -10,6 +10,8 @@ import (
 	"net"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/opencontainers/runtime-spec/specs-go"
@@ -20,36 +22,75 @@ import (
 	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
 	"github.com/steadybit/action-kit/go/action_kit_sdk"
 	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/ifb"
 	extension_kit "github.com/steadybit/extension-kit"
 	"github.com/steadybit/extension-kit/extutil"
+	"golang.org/x/sync/syncmap"
 )
 
 type networkOptsProvider func(ctx context.Context, sidecar netfault.SidecarOpts, request action_kit_api.PrepareActionRequestBody) (netfault.Opts, action_kit_api.Messages, error)
 
 type networkOptsDecoder func(data json.RawMessage) (netfault.Opts, error)
 
+// networkStepsProvider derives opts which replace the prepared ones while the attack is running, e.g. to
+// ramp up a delay. It may adjust the prepared opts to be the first step.
+type networkStepsProvider func(request action_kit_api.PrepareActionRequestBody, opts netfault.Opts) ([]NetworkStep, error)
+
+// networkStepChanger changes the applied fault to the opts of a step in place, so the fault doesn't drop
+// out between two steps.
+type networkStepChanger func(ctx context.Context, r netfault.CommandRunner, opts netfault.Opts) error
+
 type networkAction struct {
-	ociRuntime   ociruntime.OciRuntime
-	description  action_kit_api.ActionDescription
-	optsProvider networkOptsProvider
-	optsDecoder  networkOptsDecoder
+	ociRuntime    ociruntime.OciRuntime
+	description   action_kit_api.ActionDescription
+	optsProvider  networkOptsProvider
+	optsDecoder   networkOptsDecoder
+	stepsProvider networkStepsProvider
+	stepChanger   networkStepChanger
+	executions    syncmap.Map
 }
 
 type NetworkActionState struct {
 	ExecutionId uuid.UUID
 	NetworkOpts json.RawMessage
 	Sidecar     netfault.SidecarOpts
+	// Cgroups restrict the fault to the traffic of these cgroups, resolved from the process parameter
+	Cgroups []string `json:",omitempty"`
 	// QdiscSnapshot holds the pre-attack qdisc tree captured by netfault.Apply.
 	// It travels through the action_kit_sdk per-execution state so Stop can
 	// hand it back to netfault.Revert and restore the original (cloud-tuned)
 	// root after the attack tree is torn down. Empty when strict-mode is on,
 	// the attack doesn't touch a tc root, or the capture itself errored.
 	QdiscSnapshot netfault.QdiscSnapshot
+	// Steps replace NetworkOpts once their time has come, AppliedSteps counts the ones already applied.
+	Steps        []NetworkStep `json:",omitempty"`
+	AppliedSteps int           `json:",omitempty"`
+	StartedAt    time.Time
+	// Pulse reverts and reapplies the fault repeatedly, Paused tells whether it is reverted at the moment.
+	Pulse          *NetworkPulse `json:",omitempty"`
+	Paused         bool          `json:",omitempty"`
+	NextTransition time.Time
+	// Redirects redirect the incoming traffic, or the outgoing traffic of the cgroups, to the ifb devices the
+	// fault is applied to. Redirected tells whether they are set up and the traffic of the cgroups is marked.
+	Redirects  []ifb.Redirect `json:",omitempty"`
+	Redirected bool           `json:",omitempty"`
+}
+
+type NetworkStep struct {
+	// After is the time since the start of the attack
+	After       time.Duration
+	NetworkOpts json.RawMessage
 }
 
 // Make sure networkAction implements all required interfaces
 var _ action_kit_sdk.Action[NetworkActionState] = (*networkAction)(nil)
 var _ action_kit_sdk.ActionWithStop[NetworkActionState] = (*networkAction)(nil)
+var _ action_kit_sdk.ActionWithStatus[NetworkActionState] = (*networkAction)(nil)
+
+var (
+	applyNetworkFault  = netfault.Apply
+	revertNetworkFault = netfault.Revert
+)
 
 var commonNetworkParameters = []action_kit_api.ActionParameter{
 	{
@@ -106,6 +147,33 @@ var commonNetworkParameters = []action_kit_api.ActionParameter{
 		Advanced:    new(true),
 		Order:       new(105),
 	},
+	{
+		Name:        "protocol",
+		Label:       "Protocol",
+		Description: new("Restrict the affected traffic to this protocol. All protocols are affected if not set."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(108),
+		Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{Label: "TCP", Value: string(netfault.IpProtoTcp)},
+			action_kit_api.ExplicitParameterOption{Label: "UDP", Value: string(netfault.IpProtoUdp)},
+			action_kit_api.ExplicitParameterOption{Label: "ICMP", Value: string(netfault.IpProtoIcmp)},
+		}),
+	},
+	{
+		Name:        "direction",
+		Label:       "Direction",
+		Description: new("Restrict the affected traffic to outgoing (egress) or incoming (ingress) packets. Incoming traffic is redirected to an ifb device to apply delay, loss and other traffic shaping, which requires the ifb kernel module. If not set, delay, loss and other traffic shaping affects outgoing traffic, while blocking affects both directions."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(109),
+		Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{Label: "Egress", Value: string(netfault.DirectionEgress)},
+			action_kit_api.ExplicitParameterOption{Label: "Ingress", Value: string(netfault.DirectionIngress)},
+		}),
+	},
 }
 
 func (a *networkAction) NewEmptyState() NetworkActionState {
@@ -113,7 +181,7 @@ func (a *networkAction) NewEmptyState() NetworkActionState {
 }
 
 func (a *networkAction) Describe() action_kit_api.ActionDescription {
-	return a.description
+	return withNetworkPulse(a.description, a.stepsProvider != nil)
 }
 
 func (a *networkAction) Prepare(ctx context.Context, state *NetworkActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
@@ -133,11 +201,43 @@ func (a *networkAction) Prepare(ctx context.Context, state *NetworkActionState,
 	}
 	state.ExecutionId = request.ExecutionId
 
+	state.Cgroups, err = resolveNetworkCgroups(request)
+	if err != nil {
+		return nil, extension_kit.WrapError(err)
+	}
+
 	opts, messages, err := a.optsProvider(ctx, state.Sidecar, request)
 	if err != nil {
 		return nil, extension_kit.WrapError(err)
 	}
 
+	if err := redirectIngress(state, opts); err != nil {
+		return nil, extension_kit.WrapError(err)
+	}
+
+	if len(state.Cgroups) > 0 {
+		if err := restrictToCgroups(state, opts); err != nil {
+			return nil, &extension_kit.ExtensionError{Title: fmt.Sprintf("'%s' cannot be restricted to processes: %s", a.description.Label, err)}
+		}
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Info),
+			Message: fmt.Sprintf("Restricted to the outgoing traffic of the cgroups %s", strings.Join(state.Cgroups, ", ")),
+		})
+	}
+
+	state.Pulse, err = networkPulse(request)
+	if err != nil {
+		return nil, extension_kit.WrapError(err)
+	}
+
+	// may adjust opts to be the first step
+	if a.stepsProvider != nil {
+		state.Steps, err = a.stepsProvider(request, opts)
+		if err != nil {
+			return nil, extension_kit.WrapError(err)
+		}
+	}
+
 	if err := netfault.PreflightCheck(ctx, runner(a.ociRuntime, state.Sidecar), opts); err != nil {
 		return nil, extension_kit.ToError("Cannot start network attack.", err)
 	}
@@ -165,8 +265,26 @@ func (a *networkAction) Start(ctx context.Context, state *NetworkActionState) (*
 		},
 	}}
 
-	snap, err := netfault.Apply(ctx, runner(a.ociRuntime, state.Sidecar), opts)
+	// Journal before applying, so even a partially applied fault is reverted after a crash.
+	state.StartedAt = time.Now()
+	if state.Pulse != nil {
+		state.NextTransition = state.StartedAt.Add(state.Pulse.vary(state.Pulse.On))
+	}
+	state.Redirected = len(state.Redirects) > 0
+	recordExecution(state.ExecutionId, a.description.Id, state)
+	if state.Redirected {
+		if err := setupRedirects(ctx, runner(a.ociRuntime, state.Sidecar), state); err != nil {
+			// the redirects set up are torn down already and the fault wasn't applied, nothing is left to revert
+			state.Redirected = false
+			forgetExecution(state.ExecutionId)
+			a.executions.Store(state.ExecutionId, &networkExecution{state: *state, stopped: true, reverted: true})
+			return &result, extension_kit.ToError("Failed to redirect the traffic.", err)
+		}
+	}
+	snap, err := applyNetworkFault(ctx, runner(a.ociRuntime, state.Sidecar), opts)
 	state.QdiscSnapshot = snap
+	recordExecution(state.ExecutionId, a.description.Id, state)
+	a.executions.Store(state.ExecutionId, &networkExecution{state: *state})
 	if err != nil {
 		var toomany *netfault.ErrTooManyTcCommands
 		if errors.As(err, &toomany) {
@@ -183,16 +301,123 @@ func (a *networkAction) Start(ctx context.Context, state *NetworkActionState) (*
 }
 
 func (a *networkAction) Stop(ctx context.Context, state *NetworkActionState) (*action_kit_api.StopResult, error) {
-	opts, err := a.optsDecoder(state.NetworkOpts)
+	e := a.execution(state)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// no further steps or pulses, even if reverting fails
+	e.stopped = true
+	*state = e.state
+
+	// a paused pulse has already been reverted
+	if !state.Paused && !e.reverted {
+		opts, err := a.optsDecoder(state.NetworkOpts)
+		if err != nil {
+			return nil, extension_kit.ToError("Failed to deserialize network settings.", err)
+		}
+
+		if err := revertNetworkFault(ctx, runner(a.ociRuntime, state.Sidecar), opts, state.QdiscSnapshot); err != nil {
+			return nil, extension_kit.ToError("Failed to revert network settings.", err)
+		}
+	}
+	if state.Redirected && !e.reverted {
+		if err := teardownRedirects(ctx, runner(a.ociRuntime, state.Sidecar), state); err != nil {
+			return nil, extension_kit.ToError("Failed to remove the redirect of the traffic.", err)
+		}
+	}
+	forgetExecution(state.ExecutionId)
+	a.executions.Delete(state.ExecutionId)
+
+	return nil, nil
+}
+
+// Status applies the steps which are due and pulses the fault. Steps are changed in place, the pulse
+// reverts and reapplies the fault.
+func (a *networkAction) Status(ctx context.Context, state *NetworkActionState) (*action_kit_api.StatusResult, error) {
+	e := a.execution(state)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return &action_kit_api.StatusResult{Completed: false}, nil
+	}
+
+	var messages action_kit_api.Messages
+	message, err := a.applyDueStep(ctx, &e.state)
+	if message != nil {
+		messages = append(messages, *message)
+	}
+	if err == nil && e.state.Pulse != nil {
+		message, err = a.pulse(ctx, &e.state)
+		if message != nil {
+			messages = append(messages, *message)
+		}
+	}
+	*state = e.state
+	if err != nil {
+		return nil, err
+	}
+
+	result := action_kit_api.StatusResult{Completed: false}
+	if len(messages) > 0 {
+		result.Messages = &messages
+	}
+	if state.Pulse != nil {
+		result.Metrics = new(pulseMetrics(state))
+	}
+	return &result, nil
+}
+
+func (a *networkAction) applyDueStep(ctx context.Context, state *NetworkActionState) (*action_kit_api.Message, error) {
+	next := state.AppliedSteps
+	for next < len(state.Steps) && state.Steps[next].After <= time.Since(state.StartedAt) {
+		next++
+	}
+	if next == state.AppliedSteps {
+		return nil, nil
+	}
+	step := state.Steps[next-1]
+
+	opts, err := a.optsDecoder(step.NetworkOpts)
 	if err != nil {
 		return nil, extension_kit.ToError("Failed to deserialize network settings.", err)
 	}
+	message := &action_kit_api.Message{
+		Level:   extutil.Ptr(action_kit_api.Info),
+		Message: fmt.Sprintf("Step %d of %d: %s", next, len(state.Steps), opts.String()),
+	}
 
-	if err := netfault.Revert(ctx, runner(a.ociRuntime, state.Sidecar), opts, state.QdiscSnapshot); err != nil {
-		return nil, extension_kit.ToError("Failed to revert network settings.", err)
+	// a paused pulse applies the step when it's resumed
+	if state.Paused {
+		state.NetworkOpts = step.NetworkOpts
+		state.AppliedSteps = next
+		recordExecution(state.ExecutionId, a.description.Id, state)
+		return message, nil
 	}
 
-	return nil, nil
+	// the fault is reverted with the prior opts if changing it fails halfway
+	if err := a.stepChanger(ctx, runner(a.ociRuntime, state.Sidecar), opts); err != nil {
+		return nil, extension_kit.ToError("Failed to change network settings.", err)
+	}
+	state.NetworkOpts = step.NetworkOpts
+	state.AppliedSteps = next
+	recordExecution(state.ExecutionId, a.description.Id, state)
+	return message, nil
+}
+
+// networkExecution serializes Status and Stop of an execution and holds its latest state, which may be
+// ahead of the state passed in while Stop and Status are racing.
+type networkExecution struct {
+	mu      sync.Mutex
+	state   NetworkActionState
+	stopped bool
+	// reverted is set for executions of a previous run, which were reverted from the journal on startup, and
+	// for executions which failed to start without leaving anything to revert
+	reverted bool
+}
+
+func (a *networkAction) execution(state *NetworkActionState) *networkExecution {
+	reverted := revertedOnStartup(state.ExecutionId)
+	e, _ := a.executions.LoadOrStore(state.ExecutionId, &networkExecution{state: *state, stopped: reverted, reverted: reverted})
+	return e.(*networkExecution)
 }
 
 func runner(r ociruntime.OciRuntime, sidecar netfault.SidecarOpts) netfault.CommandRunner {
@@ -247,6 +472,11 @@ func mapToNetworkFilter(ctx context.Context, r ociruntime.OciRuntime, sidecar ne
 		includeCidrs = network.NetAny
 	}
 
+	protocol, direction, err := parseProtocolAndDirection(actionConfig)
+	if err != nil {
+		return netfault.Filter{}, nil, err
+	}
+
 	portRanges, err := parsePortRanges(extutil.ToStringArray(actionConfig["port"]))
 	if err != nil {
 		return netfault.Filter{}, nil, err
@@ -299,7 +529,28 @@ func mapToNetworkFilter(ctx context.Context, r ociruntime.OciRuntime, sidecar ne
 		})
 	}
 
-	return netfault.Filter{Include: includes, Exclude: excludes}, messages, nil
+	return netfault.Filter{Include: includes, Exclude: excludes, Protocol: protocol, Direction: direction}, messages, nil
+}
+
+func parseProtocolAndDirection(actionConfig map[string]any) (netfault.IpProto, netfault.Direction, error) {
+	protocol := netfault.IpProto(strings.ToLower(extutil.ToString(actionConfig["protocol"])))
+	switch protocol {
+	case "", netfault.IpProtoTcp, netfault.IpProtoUdp, netfault.IpProtoIcmp:
+	default:
+		return "", "", fmt.Errorf("unsupported protocol %q", protocol)
+	}
+
+	direction := netfault.Direction(strings.ToLower(extutil.ToString(actionConfig["direction"])))
+	switch direction {
+	case "", netfault.DirectionEgress, netfault.DirectionIngress:
+	default:
+		return "", "", fmt.Errorf("unsupported direction %q", direction)
+	}
+
+	if protocol == netfault.IpProtoIcmp && slices.ContainsFunc(extutil.ToStringArray(actionConfig["port"]), func(p string) bool { return p != "" }) {
+		return "", "", fmt.Errorf("ports cannot be used together with protocol %s", protocol)
+	}
+	return protocol, direction, nil
 }
 
 func condenseExcludes(excludes []network.NetWithPortRange) ([]network.NetWithPortRange, bool) {
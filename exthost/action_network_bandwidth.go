@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
@@ -17,12 +18,12 @@ import (
 )
 
 func NewNetworkLimitBandwidthContainerAction(r ociruntime.OciRuntime) action_kit_sdk.Action[NetworkActionState] {
-	return &networkAction{
+	return journaled[NetworkActionState](&networkAction{
 		ociRuntime:   r,
 		optsProvider: limitBandwidth(r),
 		optsDecoder:  limitBandwidthDecode,
 		description:  getNetworkLimitBandwidthDescription(),
-	}
+	})
 }
 
 func getNetworkLimitBandwidthDescription() action_kit_api.ActionDescription {
@@ -33,33 +34,36 @@ func getNetworkLimitBandwidthDescription() action_kit_api.ActionDescription {
 		Version:     extbuild.GetSemverVersionStringOrUnknown(),
 		Icon:        new(bandwidthIcon),
 		TargetSelection: &action_kit_api.TargetSelection{
-			TargetType:         targetID,
+			TargetType:         requireCapability(capabilityTc, capabilityDig),
 			SelectionTemplates: &targetSelectionTemplates,
 		},
 		Technology:  new("Linux Host"),
 		Category:    new("Network"),
 		Kind:        action_kit_api.Attack,
 		TimeControl: action_kit_api.TimeControlExternal,
-		Parameters: append(
+		Parameters: slices.Concat(
 			commonNetworkParameters,
-			action_kit_api.ActionParameter{
-				Name:         "bandwidth",
-				Label:        "Network Bandwidth",
-				Description:  new("How much traffic should be allowed per second?"),
-				Type:         action_kit_api.ActionParameterTypeBitrate,
-				DefaultValue: new("1024kbit"),
-				Required:     new(true),
-				Order:        new(1),
-			},
-			action_kit_api.ActionParameter{
-				Name:        "networkInterface",
-				Label:       "Network Interface",
-				Description: new("Target Network Interface which should be affected. All if none specified."),
-				Type:        action_kit_api.ActionParameterTypeStringArray,
-				Required:    new(false),
-				Advanced:    new(true),
-				Order:       new(106),
+			[]action_kit_api.ActionParameter{
+				{
+					Name:         "bandwidth",
+					Label:        "Network Bandwidth",
+					Description:  new("How much traffic should be allowed per second?"),
+					Type:         action_kit_api.ActionParameterTypeBitrate,
+					DefaultValue: new("1024kbit"),
+					Required:     new(true),
+					Order:        new(1),
+				},
+				{
+					Name:        "networkInterface",
+					Label:       "Network Interface",
+					Description: new("Target Network Interface which should be affected. All if none specified."),
+					Type:        action_kit_api.ActionParameterTypeStringArray,
+					Required:    new(false),
+					Advanced:    new(true),
+					Order:       new(106),
+				},
 			},
+			networkProcessParameters,
 		),
 	}
 }
@@ -17,12 +17,12 @@ import (
 )
 
 func NewNetworkBlackholeContainerAction(r ociruntime.OciRuntime) action_kit_sdk.Action[NetworkActionState] {
-	return &networkAction{
+	return journaled[NetworkActionState](&networkAction{
 		ociRuntime:   r,
 		optsProvider: blackhole(r),
 		optsDecoder:  blackholeDecode,
 		description:  getNetworkBlackholeDescription(),
-	}
+	})
 }
 
 func getNetworkBlackholeDescription() action_kit_api.ActionDescription {
@@ -33,14 +33,14 @@ func getNetworkBlackholeDescription() action_kit_api.ActionDescription {
 		Version:     extbuild.GetSemverVersionStringOrUnknown(),
 		Icon:        new(blackHoleIcon),
 		TargetSelection: &action_kit_api.TargetSelection{
-			TargetType:         targetID,
+			TargetType:         requireCapability(capabilityIptables, capabilityIp6tables, capabilityDig),
 			SelectionTemplates: &targetSelectionTemplates,
 		},
 		Technology:  new("Linux Host"),
 		Category:    new("Network"),
 		Kind:        action_kit_api.Attack,
 		TimeControl: action_kit_api.TimeControlExternal,
-		Parameters:  commonNetworkParameters,
+		Parameters:  append(commonNetworkParameters, networkProcessParameters...),
 	}
 }
 
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/extension-host/exthost/ifb"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// cgroupMark is the mark bit of the outgoing packets of the cgroups a traffic shaping fault is restricted to.
+// It is set with the cgroup match of iptables, as tc can't match the cgroup of a packet's socket itself.
+const cgroupMark = 0x40
+
+var (
+	markCgroups   = markCgroupTraffic
+	unmarkCgroups = unmarkCgroupTraffic
+)
+
+// networkProcessParameters restrict network faults to the traffic of processes, see restrictToCgroups.
+var networkProcessParameters = []action_kit_api.ActionParameter{
+	{
+		Name:        "process",
+		Label:       "Restrict to Process",
+		Description: new("Only affect the outgoing traffic of the cgroups of the matching processes, e.g. of a systemd unit. How the processes are matched is defined by 'Match by'. All processes are affected if not set. Delay, loss and other traffic shaping require the ifb kernel module for this."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(110),
+	},
+	networkMatchModeParameter(),
+}
+
+func networkMatchModeParameter() action_kit_api.ActionParameter {
+	p := processMatchModeParameter(new(111))
+	p.Required = new(false)
+	p.Advanced = new(true)
+	return p
+}
+
+// resolveNetworkCgroups returns the cgroups the network fault is restricted to. When matching by cgroup the
+// whole systemd unit or cgroup is used, otherwise the cgroups of the matching processes.
+func resolveNetworkCgroups(request action_kit_api.PrepareActionRequestBody) ([]string, error) {
+	value := extutil.ToString(request.Config["process"])
+	if value == "" {
+		return nil, nil
+	}
+
+	if version, err := cgroupVersion(); err != nil || version != "v2" {
+		return nil, fmt.Errorf("restricting network attacks to processes requires cgroup v2")
+	}
+
+	selector, err := stopprocess.NewSelector(extutil.ToString(request.Config["matchMode"]), value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid process selection: %w", err)
+	}
+	if selector.Mode == stopprocess.MatchCgroup && strings.HasPrefix(selector.Value, "/") {
+		if err := stopprocess.CheckCgroup(selector.Value); err != nil {
+			return nil, fmt.Errorf("invalid cgroup: %w", err)
+		}
+	}
+	processes, err := findProcesses(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var cgroups []string
+	if selector.Mode == stopprocess.MatchCgroup {
+		cgroups = cgroupsToFreeze(selector, processes)
+	} else {
+		for _, p := range processes {
+			if !slices.Contains(cgroups, p.Cgroup) {
+				cgroups = append(cgroups, p.Cgroup)
+			}
+		}
+	}
+
+	// the root cgroup would affect the whole host, which is what not restricting the attack does
+	cgroups = slices.DeleteFunc(cgroups, func(c string) bool {
+		return c == "" || c == "/" || stopprocess.CheckCgroup(c) != nil
+	})
+	if len(cgroups) == 0 {
+		return nil, fmt.Errorf("no cgroups of processes matching %s (%s) found", selector.Value, selector.Mode)
+	}
+	slices.Sort(cgroups)
+	return cgroups, nil
+}
+
+// networkFilterOf returns the filter of the opts to restrict it further, or nil if the opts have none.
+func networkFilterOf(opts netfault.Opts) *netfault.Filter {
+	switch o := opts.(type) {
+	case *netfault.BlackholeOpts:
+		return &o.Filter
+	case *netfault.DelayOpts:
+		return &o.Filter
+	case *netfault.PackageLossOpts:
+		return &o.Filter
+	case *netfault.CorruptPackagesOpts:
+		return &o.Filter
+	case *netfault.LimitBandwidthOpts:
+		return &o.Filter
+	case *netfault.TcpResetOpts:
+		return &o.Filter
+	case *netfault.DuplicatePackagesOpts:
+		return &o.Filter
+	case *netfault.ReorderPackagesOpts:
+		return &o.Filter
+	}
+	return nil
+}
+
+// restrictToCgroups restricts the fault to the traffic of the cgroups of the state. iptables matches packets
+// by the cgroup of their socket, but only outgoing ones, as the socket of incoming packets isn't known before
+// they are routed. Incoming traffic therefore can't be restricted, and faults affecting both directions are
+// restricted to outgoing traffic. Faults shaping the traffic with tc are applied to ifb devices instead, which
+// the outgoing packets of the cgroups are marked for and redirected to.
+func restrictToCgroups(state *NetworkActionState, opts netfault.Opts) error {
+	filter := networkFilterOf(opts)
+	if filter == nil {
+		return fmt.Errorf("the fault can't be restricted to processes")
+	}
+	if filter.Direction == netfault.DirectionIngress {
+		return fmt.Errorf("incoming traffic can't be restricted to processes")
+	}
+	if interfaces := trafficShapingInterfacesOf(opts); interfaces != nil {
+		if err := ifbAvailable(); err != nil {
+			return fmt.Errorf("restricting traffic shaping to processes requires ifb devices: %w", err)
+		}
+		state.Redirects = ifb.MarkedRedirects(state.ExecutionId, *interfaces, cgroupMark)
+		*interfaces = ifb.Devices(state.Redirects)
+		return nil
+	}
+	filter.Direction = netfault.DirectionEgress
+	filter.Cgroups = state.Cgroups
+	return nil
+}
+
+// markCgroupTraffic marks the outgoing packets of the cgroups with cgroupMark. The rules added already are
+// removed if one fails.
+func markCgroupTraffic(ctx context.Context, r netfault.CommandRunner, cgroups []string) error {
+	for i, cgroup := range cgroups {
+		for _, iptables := range []string{"iptables", "ip6tables"} {
+			if err := r.Run(ctx, cgroupMarkRule(iptables, "-A", cgroup)); err != nil {
+				return errors.Join(fmt.Errorf("failed to mark the traffic of cgroup %s: %w", cgroup, err), unmarkCgroupTraffic(ctx, r, cgroups[:i+1]))
+			}
+		}
+	}
+	return nil
+}
+
+// unmarkCgroupTraffic removes the marking of the outgoing packets of the cgroups. Rules which don't exist
+// (anymore) are no error.
+func unmarkCgroupTraffic(ctx context.Context, r netfault.CommandRunner, cgroups []string) error {
+	var errs []error
+	for _, cgroup := range cgroups {
+		for _, iptables := range []string{"iptables", "ip6tables"} {
+			if err := r.Run(ctx, cgroupMarkRule(iptables, "-D", cgroup)); err != nil && !strings.Contains(strings.ToLower(err.Error()), "does a matching rule exist") {
+				errs = append(errs, fmt.Errorf("failed to remove the marking of the traffic of cgroup %s: %w", cgroup, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func cgroupMarkRule(iptables, op, cgroup string) []string {
+	return []string{iptables, "-w", "-t", "mangle", op, "OUTPUT", "-m", "cgroup", "--path", cgroup, "-j", "MARK", "--set-xmark", fmt.Sprintf("%#x/%#x", cgroupMark, cgroupMark)}
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/extension-host/exthost/hostinfo"
+	"github.com/steadybit/extension-host/exthost/ifb"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveNetworkCgroups(t *testing.T) {
+	processes := []stopprocess.Process{
+		{Pid: 10, Name: "nginx", Cgroup: "/system.slice/nginx.service"},
+		{Pid: 11, Name: "nginx", Cgroup: "/system.slice/nginx.service/workers"},
+		{Pid: 12, Name: "init", Cgroup: "/"},
+		{Pid: 13, Name: "nginx", Cgroup: "/../nginx.service"},
+	}
+	findProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) { return processes, nil }
+	cgroupVersion = func() (string, error) { return "v2", nil }
+	t.Cleanup(func() {
+		findProcesses = stopprocess.FindProcesses
+		cgroupVersion = hostinfo.CgroupVersion
+	})
+
+	tests := []struct {
+		name    string
+		config  map[string]any
+		want    []string
+		wantErr bool
+	}{
+		{name: "not restricted", config: map[string]any{}},
+		{
+			name:   "cgroups of the matching processes",
+			config: map[string]any{"process": "nginx", "matchMode": stopprocess.MatchName},
+			want:   []string{"/system.slice/nginx.service", "/system.slice/nginx.service/workers"},
+		},
+		{
+			name:   "whole systemd unit",
+			config: map[string]any{"process": "nginx.service", "matchMode": stopprocess.MatchCgroup},
+			want:   []string{"/system.slice/nginx.service"},
+		},
+		{
+			name:    "cgroup outside of the cgroup namespace",
+			config:  map[string]any{"process": "/system.slice/../../other", "matchMode": stopprocess.MatchCgroup},
+			wantErr: true,
+		},
+		{
+			name:    "invalid selection",
+			config:  map[string]any{"process": "1", "matchMode": "unknown"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cgroups, err := resolveNetworkCgroups(action_kit_api.PrepareActionRequestBody{Config: tt.config})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cgroups)
+		})
+	}
+}
+
+func TestResolveNetworkCgroupsRequiresCgroupV2(t *testing.T) {
+	cgroupVersion = func() (string, error) { return "v1", nil }
+	t.Cleanup(func() { cgroupVersion = hostinfo.CgroupVersion })
+
+	_, err := resolveNetworkCgroups(action_kit_api.PrepareActionRequestBody{Config: map[string]any{"process": "nginx"}})
+	assert.ErrorContains(t, err, "cgroup v2")
+}
+
+func TestResolveNetworkCgroupsIgnoresRootCgroup(t *testing.T) {
+	findProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) {
+		return []stopprocess.Process{{Pid: 1, Cgroup: "/"}}, nil
+	}
+	cgroupVersion = func() (string, error) { return "v2", nil }
+	t.Cleanup(func() {
+		findProcesses = stopprocess.FindProcesses
+		cgroupVersion = hostinfo.CgroupVersion
+	})
+
+	_, err := resolveNetworkCgroups(action_kit_api.PrepareActionRequestBody{Config: map[string]any{"process": "init"}})
+	assert.ErrorContains(t, err, "no cgroups")
+}
+
+func TestNetworkFilterOf(t *testing.T) {
+	opts := &netfault.DelayOpts{}
+	networkFilterOf(opts).Cgroups = []string{"/system.slice/nginx.service"}
+	assert.Equal(t, []string{"/system.slice/nginx.service"}, opts.Filter.Cgroups)
+}
+
+func TestRestrictToCgroups(t *testing.T) {
+	ifbAvailable = func() error { return nil }
+	defer func() { ifbAvailable = ifb.Available }()
+	cgroups := []string{"/system.slice/nginx.service"}
+	state := NetworkActionState{ExecutionId: uuid.MustParse("1a2b3c4d-0000-0000-0000-000000000000"), Cgroups: cgroups}
+
+	blackhole := &netfault.BlackholeOpts{}
+	require.NoError(t, restrictToCgroups(&state, blackhole))
+	assert.Equal(t, cgroups, blackhole.Filter.Cgroups)
+	assert.Equal(t, netfault.DirectionEgress, blackhole.Filter.Direction)
+	assert.Empty(t, state.Redirects)
+
+	tcpReset := &netfault.TcpResetOpts{Filter: netfault.Filter{Direction: netfault.DirectionEgress}}
+	require.NoError(t, restrictToCgroups(&state, tcpReset))
+	assert.Equal(t, cgroups, tcpReset.Filter.Cgroups)
+
+	ingress := &netfault.BlackholeOpts{Filter: netfault.Filter{Direction: netfault.DirectionIngress}}
+	assert.ErrorContains(t, restrictToCgroups(&state, ingress), "incoming traffic")
+	assert.Empty(t, ingress.Filter.Cgroups)
+
+	// tc faults are applied to ifb devices the marked traffic of the cgroups is redirected to
+	delay := &netfault.DelayOpts{Interfaces: []string{"eth0"}}
+	require.NoError(t, restrictToCgroups(&state, delay))
+	assert.Equal(t, []ifb.Redirect{{Interface: "eth0", Device: "ifb1a2b3c4d0", Mark: cgroupMark}}, state.Redirects)
+	assert.Equal(t, []string{"ifb1a2b3c4d0"}, delay.Interfaces)
+	assert.Empty(t, delay.Filter.Cgroups)
+}
+
+func TestRestrictToCgroupsRequiresIfbForTrafficShaping(t *testing.T) {
+	ifbAvailable = func() error { return errors.New("the ifb kernel module is not loaded") }
+	defer func() { ifbAvailable = ifb.Available }()
+	state := NetworkActionState{ExecutionId: uuid.New(), Cgroups: []string{"/system.slice/nginx.service"}}
+
+	loss := &netfault.PackageLossOpts{Interfaces: []string{"eth0"}}
+	assert.ErrorContains(t, restrictToCgroups(&state, loss), "requires ifb devices")
+	assert.Equal(t, []string{"eth0"}, loss.Interfaces)
+}
+
+func TestMarkCgroupTraffic(t *testing.T) {
+	r := &fakeCommandRunner{}
+	cgroups := []string{"/system.slice/nginx.service", "/system.slice/redis.service"}
+
+	require.NoError(t, markCgroupTraffic(context.Background(), r, cgroups))
+	assert.Equal(t, []string{
+		"iptables -w -t mangle -A OUTPUT -m cgroup --path /system.slice/nginx.service -j MARK --set-xmark 0x40/0x40",
+		"ip6tables -w -t mangle -A OUTPUT -m cgroup --path /system.slice/nginx.service -j MARK --set-xmark 0x40/0x40",
+		"iptables -w -t mangle -A OUTPUT -m cgroup --path /system.slice/redis.service -j MARK --set-xmark 0x40/0x40",
+		"ip6tables -w -t mangle -A OUTPUT -m cgroup --path /system.slice/redis.service -j MARK --set-xmark 0x40/0x40",
+	}, r.commands)
+
+	r.commands = nil
+	require.NoError(t, unmarkCgroupTraffic(context.Background(), r, cgroups[:1]))
+	assert.Equal(t, []string{
+		"iptables -w -t mangle -D OUTPUT -m cgroup --path /system.slice/nginx.service -j MARK --set-xmark 0x40/0x40",
+		"ip6tables -w -t mangle -D OUTPUT -m cgroup --path /system.slice/nginx.service -j MARK --set-xmark 0x40/0x40",
+	}, r.commands)
+}
+
+func TestNetworkProcessParameters(t *testing.T) {
+	hasProcess := func(d action_kit_api.ActionDescription) bool {
+		return slices.ContainsFunc(d.Parameters, func(p action_kit_api.ActionParameter) bool { return p.Name == "process" })
+	}
+	assert.True(t, hasProcess(getNetworkBlackholeDescription()))
+	assert.True(t, hasProcess(getNetworkTcpResetDescription()))
+	assert.True(t, hasProcess(getNetworkDelayDescription()))
+	assert.True(t, hasProcess(getNetworkLimitBandwidthDescription()))
+}
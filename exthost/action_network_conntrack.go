@@ -0,0 +1,357 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/conntrack"
+	"github.com/steadybit/extension-host/exthost/holder"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+	"golang.org/x/sync/syncmap"
+)
+
+type conntrackAction struct {
+	ociRuntime ociruntime.OciRuntime
+	conntracks syncmap.Map
+}
+
+type ConntrackActionState struct {
+	ExecutionId   uuid.UUID
+	Sidecar       holder.SidecarOpts
+	ConntrackOpts conntrack.Opts
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[ConntrackActionState]           = (*conntrackAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[ConntrackActionState] = (*conntrackAction)(nil)
+	_ action_kit_sdk.ActionWithStop[ConntrackActionState]   = (*conntrackAction)(nil)
+)
+
+var conntrackActionID = fmt.Sprintf("%s.network_conntrack", BaseActionID)
+
+func NewNetworkConntrackAction(r ociruntime.OciRuntime) action_kit_sdk.Action[ConntrackActionState] {
+	return journaled[ConntrackActionState](&conntrackAction{
+		ociRuntime: r,
+	})
+}
+
+func (a *conntrackAction) NewEmptyState() ConntrackActionState {
+	return ConntrackActionState{}
+}
+
+func (a *conntrackAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          conntrackActionID,
+		Label:       "Conntrack Table",
+		Description: "Fills the connection tracking table of the host with synthetic entries, so new flows are dropped, or flushes the entries of selected connections.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(blackHoleIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         requireCapability(capabilityConntrack),
+			SelectionTemplates: new(targetSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("Network"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Widgets: new([]action_kit_api.Widget{
+			action_kit_api.LineChartWidget{
+				Type:  action_kit_api.ComSteadybitWidgetLineChart,
+				Title: "Conntrack Entries",
+				Identity: action_kit_api.LineChartWidgetIdentityConfig{
+					MetricName: "conntrack_entries",
+					From:       "conntrack",
+					Mode:       action_kit_api.ComSteadybitWidgetLineChartIdentityModeWidgetPerValue,
+				},
+				Tooltip: new(action_kit_api.LineChartWidgetTooltipConfig{
+					MetricValueTitle: new("Entries"),
+				}),
+			},
+		}),
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long should the table be filled? Flushed entries can't be restored, the duration only limits how long the entry count is reported."),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(0),
+			},
+			{
+				Name:         "mode",
+				Label:        "Mode",
+				Description:  new("*Fill:* Add synthetic entries until the usage of nf_conntrack_max is reached. They are removed when the attack ends.\n\n*Flush:* Delete the entries of the connections matching the hostnames, IPs and ports."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(string(conntrack.ModeFill)),
+				Required:     new(true),
+				Order:        new(1),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "Fill", Value: string(conntrack.ModeFill)},
+					action_kit_api.ExplicitParameterOption{Label: "Flush", Value: string(conntrack.ModeFlush)},
+				}),
+			},
+			{
+				Name:         "usage",
+				Label:        "Usage",
+				Description:  new("Fills the table up to this share of nf_conntrack_max. Only used for the fill mode."),
+				Type:         action_kit_api.ActionParameterTypePercentage,
+				DefaultValue: new("100"),
+				MinValue:     new(1),
+				MaxValue:     new(100),
+				Required:     new(true),
+				Order:        new(2),
+			},
+			{
+				Name:         "hostname",
+				Label:        "Include Hostnames",
+				Description:  new("Flush the connections to/from these hosts. Only used for the flush mode."),
+				Type:         action_kit_api.ActionParameterTypeStringArray,
+				DefaultValue: new(""),
+				Advanced:     new(true),
+				Order:        new(101),
+			},
+			{
+				Name:         "ip",
+				Label:        "Include IPs/CIDRs",
+				Description:  new("Flush the connections to/from these IP addresses or blocks. Only used for the flush mode."),
+				Type:         action_kit_api.ActionParameterTypeStringArray,
+				DefaultValue: new(""),
+				Advanced:     new(true),
+				Order:        new(102),
+			},
+			{
+				Name:         "port",
+				Label:        "Include Ports",
+				Description:  new("Flush the connections to/from these ports. Only used for the flush mode."),
+				Type:         action_kit_api.ActionParameterTypeStringArray,
+				DefaultValue: new(""),
+				Advanced:     new(true),
+				Order:        new(103),
+			},
+			{
+				Name:        "excludeHostname",
+				Label:       "Exclude Hostnames",
+				Description: new("Keep the connections to/from these hosts. Excludes always take precedence over the include restrictions above. Only used for the flush mode."),
+				Type:        action_kit_api.ActionParameterTypeStringArray,
+				Required:    new(false),
+				Advanced:    new(true),
+				Order:       new(104),
+			},
+			{
+				Name:        "excludeIp",
+				Label:       "Exclude IPs/CIDRs",
+				Description: new("Keep the connections to/from these IP addresses or CIDR blocks. Excludes always take precedence over the include restrictions above. Only used for the flush mode."),
+				Type:        action_kit_api.ActionParameterTypeStringArray,
+				Required:    new(false),
+				Advanced:    new(true),
+				Order:       new(105),
+			},
+		},
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("1s"),
+		}),
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (a *conntrackAction) Prepare(ctx context.Context, state *ConntrackActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	opts := conntrack.Opts{
+		Mode:  conntrack.Mode(extutil.ToString(request.Config["mode"])),
+		Usage: extutil.ToInt(request.Config["usage"]),
+	}
+	if opts.Mode != conntrack.ModeFill && opts.Mode != conntrack.ModeFlush {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid mode '%s'", opts.Mode),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	initProcess, err := ociruntime.ReadLinuxProcessInfo(ctx, 1, specs.NetworkNamespace)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to read root process infos.", err)
+	}
+
+	sidecar := netfault.SidecarOpts{
+		TargetProcess: initProcess,
+		Id:            fmt.Sprintf("%s-host", request.ExecutionId.String()[24:]),
+	}
+
+	var messages action_kit_api.Messages
+	if opts.Mode == conntrack.ModeFlush {
+		filter, filterMessages, err := mapToNetworkFilter(ctx, a.ociRuntime, sidecar, request.Config, getRestrictedEndpoints(request))
+		if err != nil {
+			return nil, extension_kit.WrapError(err)
+		}
+		opts.Include = filter.Include
+		opts.Exclude = filter.Exclude
+		messages = filterMessages
+	}
+
+	state.Sidecar = holder.SidecarOpts{
+		TargetProcess: sidecar.TargetProcess,
+		Id:            sidecar.Id,
+	}
+	state.ConntrackOpts = opts
+	state.ExecutionId = request.ExecutionId
+	return &action_kit_api.PrepareResult{Messages: &messages}, nil
+}
+
+func (a *conntrackAction) conntrack(ctx context.Context, sidecar holder.SidecarOpts, opts conntrack.Opts) (holder.Holder, error) {
+	if config.Config.DisableRunc {
+		return conntrack.NewConntrackProcess(opts)
+	}
+
+	return conntrack.NewConntrackRunc(ctx, a.ociRuntime, sidecar, opts)
+}
+
+func (a *conntrackAction) Start(ctx context.Context, state *ConntrackActionState) (*action_kit_api.StartResult, error) {
+	ct, err := a.conntrack(ctx, state.Sidecar, state.ConntrackOpts)
+	if err != nil {
+		return nil, extension_kit.ToError(fmt.Sprintf("Failed to prepare %s conntrack table on host", state.ConntrackOpts.Mode), err)
+	}
+
+	a.conntracks.Store(state.ExecutionId, ct)
+	recordExecution(state.ExecutionId, conntrackActionID, state)
+
+	if err := ct.Start(); err != nil {
+		return nil, extension_kit.ToError(fmt.Sprintf("Failed to %s conntrack table on host", state.ConntrackOpts.Mode), err)
+	}
+
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Starting %s conntrack table on host with args %s", state.ConntrackOpts.Mode, strings.Join(ct.Args(), " ")),
+			},
+		}),
+	}, nil
+}
+
+func (a *conntrackAction) Status(_ context.Context, state *ConntrackActionState) (*action_kit_api.StatusResult, error) {
+	metrics := conntrackMetrics()
+
+	exited, err := a.conntrackExited(state.ExecutionId)
+	if !exited {
+		return &action_kit_api.StatusResult{Completed: false, Metrics: &metrics}, nil
+	}
+
+	if err == nil {
+		return &action_kit_api.StatusResult{
+			Completed: true,
+			Metrics:   &metrics,
+			Messages: &[]action_kit_api.Message{
+				{
+					Level:   extutil.Ptr(action_kit_api.Info),
+					Message: fmt.Sprintf("%s conntrack table on host stopped", state.ConntrackOpts.Mode),
+				},
+			},
+		}, nil
+	}
+
+	errMessage := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		errMessage = fmt.Sprintf("%s\n%s", exitErr.Error(), string(exitErr.Stderr))
+	}
+	return &action_kit_api.StatusResult{
+		Completed: true,
+		Metrics:   &metrics,
+		Error: &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Failed),
+			Title:  fmt.Sprintf("Failed to %s conntrack table on host: %s", state.ConntrackOpts.Mode, errMessage),
+		},
+	}, nil
+}
+
+func (a *conntrackAction) Stop(ctx context.Context, state *ConntrackActionState) (*action_kit_api.StopResult, error) {
+	messages := make([]action_kit_api.Message, 0)
+
+	stopped, err := a.stopConntrackHost(ctx, state)
+	if err != nil {
+		return nil, extension_kit.ToError(fmt.Sprintf("Failed to stop %s conntrack table on host", state.ConntrackOpts.Mode), err)
+	}
+	forgetExecution(state.ExecutionId)
+	if stopped {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Info),
+			Message: fmt.Sprintf("Canceled %s conntrack table on host", state.ConntrackOpts.Mode),
+		})
+	}
+
+	return &action_kit_api.StopResult{
+		Messages: &messages,
+	}, nil
+}
+
+func (a *conntrackAction) conntrackExited(executionId uuid.UUID) (bool, error) {
+	s, ok := a.conntracks.Load(executionId)
+	if !ok {
+		return true, nil
+	}
+	return s.(holder.Holder).Exited()
+}
+
+func (a *conntrackAction) stopConntrackHost(ctx context.Context, state *ConntrackActionState) (bool, error) {
+	s, ok := a.conntracks.LoadAndDelete(state.ExecutionId)
+	if !ok {
+		// the handle is lost if the extension was restarted meanwhile, but the sidecar still holds on
+		if config.Config.DisableRunc {
+			return false, nil
+		}
+		return false, holder.DeleteSidecar(ctx, a.ociRuntime, state.Sidecar.Id)
+	}
+	return true, s.(holder.Holder).Stop()
+}
+
+func conntrackMetrics() []action_kit_api.Metric {
+	usage, err := readConntrackUsage()
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to read the conntrack usage")
+		return []action_kit_api.Metric{}
+	}
+	now := time.Now()
+	return []action_kit_api.Metric{
+		{
+			Name:      new("conntrack_entries"),
+			Metric:    map[string]string{"conntrack": "nf_conntrack_count"},
+			Value:     float64(usage.Count),
+			Timestamp: now,
+		},
+		{
+			Name:      new("conntrack_entries"),
+			Metric:    map[string]string{"conntrack": "nf_conntrack_max"},
+			Value:     float64(usage.Max),
+			Timestamp: now,
+		},
+	}
+}
+
+// readConntrackUsage reads the entries of the host network namespace
+var readConntrackUsage = func() (conntrack.Usage, error) {
+	return conntrack.ReadUsage("/proc/1/net")
+}
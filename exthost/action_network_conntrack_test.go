@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-host/exthost/conntrack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func conntrackRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestActionConntrack_PrepareRejectsInvalidMode(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	action := &conntrackAction{}
+
+	state := action.NewEmptyState()
+	request := conntrackRequest(map[string]any{"duration": "10000", "mode": "drain", "usage": 90})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "Invalid mode 'drain'", result.Error.Title)
+}
+
+func TestActionConntrack_StatusReportsUsage(t *testing.T) {
+	saved := readConntrackUsage
+	readConntrackUsage = func() (conntrack.Usage, error) { return conntrack.Usage{Count: 1200, Max: 262144}, nil }
+	defer func() { readConntrackUsage = saved }()
+
+	action := &conntrackAction{}
+	state := ConntrackActionState{ExecutionId: uuid.New(), ConntrackOpts: conntrack.Opts{Mode: conntrack.ModeFill, Usage: 90}}
+	status, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	require.Len(t, *status.Metrics, 2)
+	assert.Equal(t, map[string]string{"conntrack": "nf_conntrack_count"}, (*status.Metrics)[0].Metric)
+	assert.Equal(t, float64(1200), (*status.Metrics)[0].Value)
+	assert.Equal(t, map[string]string{"conntrack": "nf_conntrack_max"}, (*status.Metrics)[1].Metric)
+	assert.Equal(t, float64(262144), (*status.Metrics)[1].Value)
+}
+
+func TestActionConntrack_RequiresCapability(t *testing.T) {
+	assert.Equal(t, requireCapability(capabilityConntrack), NewNetworkConntrackAction(nil).Describe().TargetSelection.TargetType)
+}
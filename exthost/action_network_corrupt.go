@@ -7,6 +7,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
 	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
@@ -16,12 +18,12 @@ import (
 )
 
 func NewNetworkCorruptPackagesContainerAction(r ociruntime.OciRuntime) action_kit_sdk.Action[NetworkActionState] {
-	return &networkAction{
+	return journaled[NetworkActionState](&networkAction{
 		ociRuntime:   r,
 		optsProvider: corruptPackages(r),
 		optsDecoder:  corruptPackagesDecode,
 		description:  getNetworkCorruptPackagesDescription(),
-	}
+	})
 }
 
 func getNetworkCorruptPackagesDescription() action_kit_api.ActionDescription {
@@ -32,35 +34,38 @@ func getNetworkCorruptPackagesDescription() action_kit_api.ActionDescription {
 		Version:     extbuild.GetSemverVersionStringOrUnknown(),
 		Icon:        new(corruptIcon),
 		TargetSelection: &action_kit_api.TargetSelection{
-			TargetType:         targetID,
+			TargetType:         requireCapability(capabilityTc, capabilityDig),
 			SelectionTemplates: &targetSelectionTemplates,
 		},
 		Technology:  new("Linux Host"),
 		Category:    new("Network"),
 		Kind:        action_kit_api.Attack,
 		TimeControl: action_kit_api.TimeControlExternal,
-		Parameters: append(
+		Parameters: slices.Concat(
 			commonNetworkParameters,
-			action_kit_api.ActionParameter{
-				Name:         "networkCorruption",
-				Label:        "Package Corruption",
-				Description:  new("How much of the traffic should be corrupted?"),
-				Type:         action_kit_api.ActionParameterTypePercentage,
-				DefaultValue: new("15"),
-				Required:     new(true),
-				MinValue:     new(0),
-				MaxValue:     new(100),
-				Order:        new(1),
-			},
-			action_kit_api.ActionParameter{
-				Name:        "networkInterface",
-				Label:       "Network Interface",
-				Description: new("Target Network Interface which should be affected. All if none specified."),
-				Type:        action_kit_api.ActionParameterTypeStringArray,
-				Required:    new(false),
-				Advanced:    new(true),
-				Order:       new(106),
+			[]action_kit_api.ActionParameter{
+				{
+					Name:         "networkCorruption",
+					Label:        "Package Corruption",
+					Description:  new("How much of the traffic should be corrupted?"),
+					Type:         action_kit_api.ActionParameterTypePercentage,
+					DefaultValue: new("15"),
+					Required:     new(true),
+					MinValue:     new(0),
+					MaxValue:     new(100),
+					Order:        new(1),
+				},
+				{
+					Name:        "networkInterface",
+					Label:       "Network Interface",
+					Description: new("Target Network Interface which should be affected. All if none specified."),
+					Type:        action_kit_api.ActionParameterTypeStringArray,
+					Required:    new(false),
+					Advanced:    new(true),
+					Order:       new(106),
+				},
 			},
+			networkProcessParameters,
 		),
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
@@ -17,13 +18,23 @@ import (
 	"github.com/steadybit/extension-kit/extutil"
 )
 
+const (
+	delayDistributionNormal       = "normal"
+	delayDistributionPareto       = "pareto"
+	delayDistributionParetoNormal = "paretonormal"
+
+	maxDelayRampSteps = 60
+)
+
 func NewNetworkDelayContainerAction(r ociruntime.OciRuntime) action_kit_sdk.Action[NetworkActionState] {
-	return &networkAction{
-		ociRuntime:   r,
-		optsProvider: delay(r),
-		optsDecoder:  delayDecode,
-		description:  getNetworkDelayDescription(),
-	}
+	return journaled[NetworkActionState](&networkAction{
+		ociRuntime:    r,
+		optsProvider:  delay(r),
+		optsDecoder:   delayDecode,
+		stepsProvider: delayRamp,
+		stepChanger:   changeDelay,
+		description:   getNetworkDelayDescription(),
+	})
 }
 
 func getNetworkDelayDescription() action_kit_api.ActionDescription {
@@ -34,54 +45,94 @@ func getNetworkDelayDescription() action_kit_api.ActionDescription {
 		Version:     extbuild.GetSemverVersionStringOrUnknown(),
 		Icon:        new(delayIcon),
 		TargetSelection: &action_kit_api.TargetSelection{
-			TargetType:         targetID,
+			TargetType:         requireCapability(capabilityTc, capabilityDig),
 			SelectionTemplates: &targetSelectionTemplates,
 		},
 		Technology:  new("Linux Host"),
 		Category:    new("Network"),
 		Kind:        action_kit_api.Attack,
 		TimeControl: action_kit_api.TimeControlExternal,
-		Parameters: append(
+		Parameters: slices.Concat(
 			commonNetworkParameters,
-			action_kit_api.ActionParameter{
-				Name:         "networkDelay",
-				Label:        "Network Delay",
-				Description:  new("How much should the traffic be delayed?"),
-				Type:         action_kit_api.ActionParameterTypeDuration,
-				DefaultValue: new("500ms"),
-				MinValue:     new(0),
-				MaxValue:     new(4294967), //1 hour (less then tc limit - 4294967295 usecs)
-				Required:     new(true),
-				Order:        new(1),
-			},
-			action_kit_api.ActionParameter{
-				Name:         "networkDelayJitter",
-				Label:        "Jitter",
-				Description:  new("Add random +/-30% jitter to network delay?"),
-				Type:         action_kit_api.ActionParameterTypeBoolean,
-				DefaultValue: new("false"),
-				Required:     new(true),
-				Order:        new(2),
-			},
-			action_kit_api.ActionParameter{
-				Name:        "networkInterface",
-				Label:       "Network Interface",
-				Description: new("Target Network Interface which should be affected. All if none specified."),
-				Type:        action_kit_api.ActionParameterTypeStringArray,
-				Required:    new(false),
-				Advanced:    new(true),
-				Order:       new(106),
-			},
-			action_kit_api.ActionParameter{
-				Name:         "tcpDataPacketsOnly",
-				Label:        "TCP Data Packets Only [beta]",
-				Description:  new("Delay only TCP data packets (PSH flag heuristic). UDP is not delayed. When you observe the actual delay being a multiple of the configured delay, you might choose this option to avoid delaying the TCP handshake."),
-				Type:         action_kit_api.ActionParameterTypeBoolean,
-				DefaultValue: new("false"),
-				Required:     new(true),
-				Advanced:     new(true),
-				Order:        new(107),
+			[]action_kit_api.ActionParameter{
+				{
+					Name:         "networkDelay",
+					Label:        "Network Delay",
+					Description:  new("How much should the traffic be delayed?"),
+					Type:         action_kit_api.ActionParameterTypeDuration,
+					DefaultValue: new("500ms"),
+					MinValue:     new(0),
+					MaxValue:     new(4294967), //1 hour (less then tc limit - 4294967295 usecs)
+					Required:     new(true),
+					Order:        new(1),
+				},
+				{
+					Name:         "networkDelayJitter",
+					Label:        "Jitter",
+					Description:  new("Add random +/-30% jitter to network delay?"),
+					Type:         action_kit_api.ActionParameterTypeBoolean,
+					DefaultValue: new("false"),
+					Required:     new(true),
+					Order:        new(2),
+				},
+				{
+					Name:         "networkDelayDistribution",
+					Label:        "Jitter Distribution",
+					Description:  new("How is the jitter distributed? Pareto distributions cause a long tail of high delays, like WAN links. Requires jitter."),
+					Type:         action_kit_api.ActionParameterTypeString,
+					DefaultValue: new(""),
+					Advanced:     new(true),
+					Order:        new(3),
+					Options: new([]action_kit_api.ParameterOption{
+						action_kit_api.ExplicitParameterOption{Label: "Uniform", Value: ""},
+						action_kit_api.ExplicitParameterOption{Label: "Normal", Value: delayDistributionNormal},
+						action_kit_api.ExplicitParameterOption{Label: "Pareto", Value: delayDistributionPareto},
+						action_kit_api.ExplicitParameterOption{Label: "Pareto-Normal", Value: delayDistributionParetoNormal},
+					}),
+				},
+				{
+					Name:         "networkDelayCorrelation",
+					Label:        "Correlation",
+					Description:  new("How much does the delay of a packet depend on the previous one?"),
+					Type:         action_kit_api.ActionParameterTypePercentage,
+					DefaultValue: new("0"),
+					MinValue:     new(0),
+					MaxValue:     new(100),
+					Advanced:     new(true),
+					Order:        new(4),
+				},
+				{
+					Name:         "networkDelayRampSteps",
+					Label:        "Ramp Steps",
+					Description:  new("Increase the delay stepwise over the duration, starting with the delay divided by the number of steps. No ramp if less than 2."),
+					Type:         action_kit_api.ActionParameterTypeInteger,
+					DefaultValue: new("0"),
+					MinValue:     new(0),
+					MaxValue:     new(maxDelayRampSteps),
+					Advanced:     new(true),
+					Order:        new(5),
+				},
+				{
+					Name:        "networkInterface",
+					Label:       "Network Interface",
+					Description: new("Target Network Interface which should be affected. All if none specified."),
+					Type:        action_kit_api.ActionParameterTypeStringArray,
+					Required:    new(false),
+					Advanced:    new(true),
+					Order:       new(106),
+				},
+				{
+					Name:         "tcpDataPacketsOnly",
+					Label:        "TCP Data Packets Only [beta]",
+					Description:  new("Delay only TCP data packets (PSH flag heuristic). UDP is not delayed. When you observe the actual delay being a multiple of the configured delay, you might choose this option to avoid delaying the TCP handshake."),
+					Type:         action_kit_api.ActionParameterTypeBoolean,
+					DefaultValue: new("false"),
+					Required:     new(true),
+					Advanced:     new(true),
+					Order:        new(107),
+				},
 			},
+			networkProcessParameters,
 		),
 	}
 }
@@ -100,6 +151,16 @@ func delay(r ociruntime.OciRuntime) networkOptsProvider {
 			jitter = delay * 30 / 100
 		}
 
+		distribution := extutil.ToString(request.Config["networkDelayDistribution"])
+		switch distribution {
+		case "", delayDistributionNormal, delayDistributionPareto, delayDistributionParetoNormal:
+		default:
+			return nil, nil, fmt.Errorf("unknown delay distribution %s", distribution)
+		}
+		if distribution != "" && !hasJitter {
+			return nil, nil, fmt.Errorf("the %s delay distribution requires jitter", distribution)
+		}
+
 		filter, messages, err := mapToNetworkFilter(ctx, r, sidecar, request.Config, getRestrictedEndpoints(request))
 		if err != nil {
 			return nil, nil, err
@@ -122,12 +183,75 @@ func delay(r ociruntime.OciRuntime) networkOptsProvider {
 			ExecutionContext: mapToExecutionContext(request),
 			Delay:            delay,
 			Jitter:           jitter,
+			Distribution:     distribution,
+			Correlation:      extutil.ToUInt(request.Config["networkDelayCorrelation"]),
 			Interfaces:       interfaces,
 			TcpPshOnly:       extutil.ToBool(request.Config["tcpDataPacketsOnly"]),
 		}, messages, nil
 	}
 }
 
+// delayRamp starts with the delay divided by the number of steps and increases it evenly over the duration.
+func delayRamp(request action_kit_api.PrepareActionRequestBody, opts netfault.Opts) ([]NetworkStep, error) {
+	steps := extutil.ToInt(request.Config["networkDelayRampSteps"])
+	if steps < 2 {
+		return nil, nil
+	}
+	if steps > maxDelayRampSteps {
+		return nil, fmt.Errorf("at most %d ramp steps are supported", maxDelayRampSteps)
+	}
+	duration := time.Duration(extutil.ToInt64(request.Config["duration"])) * time.Millisecond
+	if duration <= 0 {
+		return nil, fmt.Errorf("a delay ramp requires a duration")
+	}
+
+	target := *opts.(*netfault.DelayOpts)
+	initial := opts.(*netfault.DelayOpts)
+	initial.Delay = target.Delay / time.Duration(steps)
+	initial.Jitter = target.Jitter / time.Duration(steps)
+
+	var result []NetworkStep
+	for i := 2; i <= steps; i++ {
+		step := target
+		step.Delay = target.Delay * time.Duration(i) / time.Duration(steps)
+		step.Jitter = target.Jitter * time.Duration(i) / time.Duration(steps)
+		raw, err := json.Marshal(&step)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, NetworkStep{
+			After:       duration * time.Duration(i-1) / time.Duration(steps),
+			NetworkOpts: raw,
+		})
+	}
+	return result, nil
+}
+
+// delayNetemHandle is the handle of the netem qdisc netfault.Apply adds below the prio root qdisc 1: of each
+// interface to delay the traffic.
+const delayNetemHandle = "30:"
+
+// changeDelay changes the netem qdiscs of the applied delay to the delay of a step.
+func changeDelay(ctx context.Context, r netfault.CommandRunner, opts netfault.Opts) error {
+	delay := opts.(*netfault.DelayOpts)
+	netem := []string{"netem", "delay", fmt.Sprintf("%dus", delay.Delay.Microseconds()), fmt.Sprintf("%dus", delay.Jitter.Microseconds())}
+	if delay.Correlation > 0 {
+		netem = append(netem, fmt.Sprintf("%d%%", delay.Correlation))
+	}
+	if delay.Distribution != "" {
+		netem = append(netem, "distribution", delay.Distribution)
+	}
+	for _, iface := range delay.Interfaces {
+		args := append([]string{"tc", "qdisc", "change", "dev", iface, "parent", "1:1", "handle", delayNetemHandle}, netem...)
+		if err := r.Run(ctx, args); err != nil {
+			return fmt.Errorf("failed to change the delay of %s: %w", iface, err)
+		}
+	}
+	return nil
+}
+
+// delayDecode also reads states serialized before distribution and correlation were added, both default
+// to none.
 func delayDecode(data json.RawMessage) (netfault.Opts, error) {
 	var opts netfault.DelayOpts
 	err := json.Unmarshal(data, &opts)
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/extension-host/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelayDecodeReadsStateWithoutDistribution(t *testing.T) {
+	opts, err := delayDecode(json.RawMessage(`{"Delay":500000000,"Jitter":150000000,"Interfaces":["eth0"],"TcpPshOnly":false}`))
+	require.NoError(t, err)
+
+	delayOpts := opts.(*netfault.DelayOpts)
+	assert.Equal(t, 500*time.Millisecond, delayOpts.Delay)
+	assert.Equal(t, 150*time.Millisecond, delayOpts.Jitter)
+	assert.Equal(t, "", delayOpts.Distribution)
+	assert.Equal(t, uint(0), delayOpts.Correlation)
+	assert.Equal(t, []string{"eth0"}, delayOpts.Interfaces)
+}
+
+func TestDelayRamp(t *testing.T) {
+	opts := &netfault.DelayOpts{Delay: 300 * time.Millisecond, Jitter: 90 * time.Millisecond, Distribution: "pareto", Interfaces: []string{"eth0"}}
+	request := action_kit_api.PrepareActionRequestBody{Config: map[string]any{"duration": 60000, "networkDelayRampSteps": 3}}
+
+	steps, err := delayRamp(request, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, 100*time.Millisecond, opts.Delay)
+	assert.Equal(t, 30*time.Millisecond, opts.Jitter)
+	require.Len(t, steps, 2)
+	assert.Equal(t, 20*time.Second, steps[0].After)
+	assert.Equal(t, 40*time.Second, steps[1].After)
+
+	step, err := delayDecode(steps[1].NetworkOpts)
+	require.NoError(t, err)
+	assert.Equal(t, &netfault.DelayOpts{Delay: 300 * time.Millisecond, Jitter: 90 * time.Millisecond, Distribution: "pareto", Interfaces: []string{"eth0"}}, step)
+
+	steps, err = delayRamp(action_kit_api.PrepareActionRequestBody{Config: map[string]any{"duration": 60000, "networkDelayRampSteps": 1}}, opts)
+	require.NoError(t, err)
+	assert.Empty(t, steps)
+}
+
+type fakeCommandRunner struct {
+	commands []string
+}
+
+func (r *fakeCommandRunner) Run(_ context.Context, args []string) error {
+	r.commands = append(r.commands, strings.Join(args, " "))
+	return nil
+}
+
+func TestChangeDelay(t *testing.T) {
+	r := &fakeCommandRunner{}
+	opts := &netfault.DelayOpts{Delay: 200 * time.Millisecond, Jitter: 60 * time.Millisecond, Distribution: delayDistributionPareto, Correlation: 25, Interfaces: []string{"eth0", "eth1"}}
+
+	require.NoError(t, changeDelay(context.Background(), r, opts))
+	assert.Equal(t, []string{
+		"tc qdisc change dev eth0 parent 1:1 handle 30: netem delay 200000us 60000us 25% distribution pareto",
+		"tc qdisc change dev eth1 parent 1:1 handle 30: netem delay 200000us 60000us 25% distribution pareto",
+	}, r.commands)
+}
+
+func TestNetworkActionStatusChangesDueStepsInPlace(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+	var changed, reverted []time.Duration
+	revertNetworkFault = func(_ context.Context, _ netfault.CommandRunner, opts netfault.Opts, _ netfault.QdiscSnapshot) error {
+		reverted = append(reverted, opts.(*netfault.DelayOpts).Delay)
+		return nil
+	}
+	defer func() { revertNetworkFault = netfault.Revert }()
+
+	raw := func(delay time.Duration) json.RawMessage {
+		b, _ := json.Marshal(&netfault.DelayOpts{Delay: delay})
+		return b
+	}
+	action := &networkAction{
+		optsDecoder: delayDecode,
+		stepChanger: func(_ context.Context, _ netfault.CommandRunner, opts netfault.Opts) error {
+			changed = append(changed, opts.(*netfault.DelayOpts).Delay)
+			return nil
+		},
+		description: getNetworkDelayDescription(),
+	}
+	state := NetworkActionState{
+		ExecutionId: uuid.New(),
+		NetworkOpts: raw(100 * time.Millisecond),
+		Steps: []NetworkStep{
+			{After: 0, NetworkOpts: raw(200 * time.Millisecond)},
+			{After: time.Hour, NetworkOpts: raw(300 * time.Millisecond)},
+		},
+		StartedAt: time.Now(),
+	}
+
+	result, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.False(t, result.Completed)
+	require.NotNil(t, result.Messages)
+	assert.Equal(t, 1, state.AppliedSteps)
+	assert.Equal(t, []time.Duration{200 * time.Millisecond}, changed)
+	assert.Empty(t, reverted)
+
+	result, err = action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Nil(t, result.Messages)
+	assert.Len(t, changed, 1)
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{200 * time.Millisecond}, reverted)
+}
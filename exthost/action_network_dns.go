@@ -16,12 +16,12 @@ import (
 )
 
 func NewNetworkBlockDnsContainerAction(r ociruntime.OciRuntime) action_kit_sdk.Action[NetworkActionState] {
-	return &networkAction{
+	return journaled[NetworkActionState](&networkAction{
 		ociRuntime:   r,
 		optsProvider: blockDns(),
 		optsDecoder:  blackholeDecode,
 		description:  getNetworkBlockDnsDescription(),
-	}
+	})
 }
 
 func getNetworkBlockDnsDescription() action_kit_api.ActionDescription {
@@ -32,7 +32,7 @@ func getNetworkBlockDnsDescription() action_kit_api.ActionDescription {
 		Version:     extbuild.GetSemverVersionStringOrUnknown(),
 		Icon:        new(dnsIcon),
 		TargetSelection: &action_kit_api.TargetSelection{
-			TargetType:         targetID,
+			TargetType:         requireCapability(capabilityIptables, capabilityIp6tables),
 			SelectionTemplates: &targetSelectionTemplates,
 		},
 		Technology:  new("Linux Host"),
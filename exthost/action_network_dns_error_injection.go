@@ -10,8 +10,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/opencontainers/runtime-spec/specs-go"
-	"github.com/rs/zerolog/log"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_commons/network"
 	"github.com/steadybit/action-kit/go/action_kit_commons/network/dnsinject"
@@ -26,12 +26,12 @@ var _ action_kit_sdk.ActionWithStatus[DNSErrorInjectionState] = (*dnsErrorInject
 var _ action_kit_sdk.ActionWithStop[DNSErrorInjectionState] = (*dnsErrorInjectionAction)(nil)
 
 var (
-	dnsInjectHandles     = map[string]dnsinject.DNSInject{}
+	dnsInjectHandles     = map[uuid.UUID]dnsinject.DNSInject{}
 	dnsInjectHandlesLock sync.Mutex
 )
 
 type DNSErrorInjectionState struct {
-	ExecutionId string
+	ExecutionId uuid.UUID
 }
 
 type dnsErrorInjectionAction struct {
@@ -39,7 +39,7 @@ type dnsErrorInjectionAction struct {
 }
 
 func NewNetworkDNSErrorInjectionAction(r ociruntime.OciRuntime) action_kit_sdk.Action[DNSErrorInjectionState] {
-	return &dnsErrorInjectionAction{ociRuntime: r}
+	return tracked[DNSErrorInjectionState](&dnsErrorInjectionAction{ociRuntime: r})
 }
 
 func (a *dnsErrorInjectionAction) NewEmptyState() DNSErrorInjectionState {
@@ -54,7 +54,7 @@ func (a *dnsErrorInjectionAction) Describe() action_kit_api.ActionDescription {
 		Version:     extbuild.GetSemverVersionStringOrUnknown(),
 		Icon:        new(dnsErrorInjectIcon),
 		TargetSelection: &action_kit_api.TargetSelection{
-			TargetType:         targetID,
+			TargetType:         requireCapability(capabilityOciRuntime),
 			SelectionTemplates: &targetSelectionTemplates,
 		},
 		Technology:  new("Linux Host"),
@@ -98,7 +98,7 @@ func (a *dnsErrorInjectionAction) Prepare(ctx context.Context, state *DNSErrorIn
 		return nil, fmt.Errorf("failed to create dns-inject process: %w", err)
 	}
 
-	state.ExecutionId = request.ExecutionId.String()
+	state.ExecutionId = request.ExecutionId
 
 	dnsInjectHandlesLock.Lock()
 	dnsInjectHandles[state.ExecutionId] = handle
@@ -116,6 +116,7 @@ func (a *dnsErrorInjectionAction) Start(_ context.Context, state *DNSErrorInject
 	if err := handle.Start(); err != nil {
 		return nil, fmt.Errorf("failed to start dns-inject: %w", err)
 	}
+	trackExecution(state.ExecutionId, a.Describe().Id, state)
 
 	return &action_kit_api.StartResult{}, nil
 }
@@ -155,13 +156,15 @@ func (a *dnsErrorInjectionAction) Status(_ context.Context, state *DNSErrorInjec
 func (a *dnsErrorInjectionAction) Stop(_ context.Context, state *DNSErrorInjectionState) (*action_kit_api.StopResult, error) {
 	handle, ok := getDNSInjectHandle(state.ExecutionId)
 	if !ok {
+		forgetExecution(state.ExecutionId)
 		return nil, nil
 	}
-	removeDNSInjectHandle(state.ExecutionId)
 
 	if err := handle.Stop(); err != nil {
-		log.Warn().Err(err).Str("execution_id", state.ExecutionId).Msg("failed to stop dns-inject")
+		return nil, fmt.Errorf("failed to stop dns-inject: %w", err)
 	}
+	removeDNSInjectHandle(state.ExecutionId)
+	forgetExecution(state.ExecutionId)
 
 	return nil, nil
 }
@@ -301,14 +304,14 @@ func formatDNSMetricsMessages(metrics *dnsinject.Metrics) []action_kit_api.Messa
 	}
 }
 
-func getDNSInjectHandle(executionId string) (dnsinject.DNSInject, bool) {
+func getDNSInjectHandle(executionId uuid.UUID) (dnsinject.DNSInject, bool) {
 	dnsInjectHandlesLock.Lock()
 	defer dnsInjectHandlesLock.Unlock()
 	h, ok := dnsInjectHandles[executionId]
 	return h, ok
 }
 
-func removeDNSInjectHandle(executionId string) {
+func removeDNSInjectHandle(executionId uuid.UUID) {
 	dnsInjectHandlesLock.Lock()
 	defer dnsInjectHandlesLock.Unlock()
 	delete(dnsInjectHandles, executionId)
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+func NewNetworkDuplicatePackagesContainerAction(r ociruntime.OciRuntime) action_kit_sdk.Action[NetworkActionState] {
+	return journaled[NetworkActionState](&networkAction{
+		ociRuntime:   r,
+		optsProvider: duplicatePackages(r),
+		optsDecoder:  duplicatePackagesDecode,
+		description:  getNetworkDuplicatePackagesDescription(),
+	})
+}
+
+func getNetworkDuplicatePackagesDescription() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          fmt.Sprintf("%s.network_package_duplication", BaseActionID),
+		Label:       "Duplicate Packets",
+		Description: "Send duplicates of egress network packets.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(corruptIcon),
+		TargetSelection: &action_kit_api.TargetSelection{
+			TargetType:         requireCapability(capabilityTc, capabilityDig),
+			SelectionTemplates: &targetSelectionTemplates,
+		},
+		Technology:  new("Linux Host"),
+		Category:    new("Network"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Parameters: slices.Concat(
+			commonNetworkParameters,
+			[]action_kit_api.ActionParameter{
+				{
+					Name:         "networkDuplication",
+					Label:        "Packet Duplication",
+					Description:  new("How much of the traffic should be duplicated?"),
+					Type:         action_kit_api.ActionParameterTypePercentage,
+					DefaultValue: new("10"),
+					Required:     new(true),
+					MinValue:     new(0),
+					MaxValue:     new(100),
+					Order:        new(1),
+				},
+				{
+					Name:         "networkCorrelation",
+					Label:        "Correlation",
+					Description:  new("How much does the decision to duplicate a packet depend on the previous one? Higher values cause bursts."),
+					Type:         action_kit_api.ActionParameterTypePercentage,
+					DefaultValue: new("0"),
+					Required:     new(true),
+					MinValue:     new(0),
+					MaxValue:     new(100),
+					Advanced:     new(true),
+					Order:        new(2),
+				},
+				{
+					Name:        "networkInterface",
+					Label:       "Network Interface",
+					Description: new("Target Network Interface which should be affected. All if none specified."),
+					Type:        action_kit_api.ActionParameterTypeStringArray,
+					Required:    new(false),
+					Advanced:    new(true),
+					Order:       new(106),
+				},
+			},
+			networkProcessParameters,
+		),
+	}
+}
+
+func duplicatePackages(r ociruntime.OciRuntime) networkOptsProvider {
+	return func(ctx context.Context, sidecar netfault.SidecarOpts, request action_kit_api.PrepareActionRequestBody) (netfault.Opts, action_kit_api.Messages, error) {
+		_, err := CheckTargetHostname(request.Target.Attributes)
+		if err != nil {
+			return nil, nil, err
+		}
+		duplication := extutil.ToUInt(request.Config["networkDuplication"])
+		correlation := extutil.ToUInt(request.Config["networkCorrelation"])
+
+		filter, messages, err := mapToNetworkFilter(ctx, r, sidecar, request.Config, getRestrictedEndpoints(request))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		interfaces := extutil.ToStringArray(request.Config["networkInterface"])
+		if len(interfaces) == 0 {
+			interfaces, err = netfault.ListNonLoopbackInterfaceNames(ctx, runner(r, sidecar))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if len(interfaces) == 0 {
+			return nil, nil, fmt.Errorf("no network interfaces specified")
+		}
+
+		return &netfault.DuplicatePackagesOpts{
+			Filter:           filter,
+			ExecutionContext: mapToExecutionContext(request),
+			Duplication:      duplication,
+			Correlation:      correlation,
+			Interfaces:       interfaces,
+		}, messages, nil
+	}
+}
+
+func duplicatePackagesDecode(data json.RawMessage) (netfault.Opts, error) {
+	var opts netfault.DuplicatePackagesOpts
+	err := json.Unmarshal(data, &opts)
+	return &opts, err
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/extension-host/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func duplicateRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestDuplicatePackages(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+	osHostname = func() (string, error) { return "myhostname", nil }
+
+	request := duplicateRequest(map[string]any{"duration": 10000, "networkDuplication": 30, "networkCorrelation": 25, "networkInterface": []any{"eth0", "eth1"}})
+	opts, _, err := duplicatePackages(nil)(context.Background(), netfault.SidecarOpts{}, request)
+	require.NoError(t, err)
+
+	duplicateOpts := opts.(*netfault.DuplicatePackagesOpts)
+	assert.Equal(t, uint(30), duplicateOpts.Duplication)
+	assert.Equal(t, uint(25), duplicateOpts.Correlation)
+	assert.Equal(t, []string{"eth0", "eth1"}, duplicateOpts.Interfaces)
+}
+
+func TestDuplicatePackagesRequiresTargetHost(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+
+	request := action_kit_api.PrepareActionRequestBody{
+		Config:      map[string]any{"duration": 10000, "networkDuplication": 30, "networkInterface": []any{"eth0"}},
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"otherhostname"}}}),
+	}
+	_, _, err := duplicatePackages(nil)(context.Background(), netfault.SidecarOpts{}, request)
+	assert.Error(t, err)
+}
+
+func TestDuplicatePackagesDecode(t *testing.T) {
+	opts, err := duplicatePackagesDecode([]byte(`{"Duplication":30,"Correlation":25,"Interfaces":["eth0"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, &netfault.DuplicatePackagesOpts{Duplication: 30, Correlation: 25, Interfaces: []string{"eth0"}}, opts)
+}
@@ -0,0 +1,390 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/holder"
+	"github.com/steadybit/extension-host/exthost/portfill"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+	"golang.org/x/sync/syncmap"
+)
+
+type exhaustPortsAction struct {
+	ociRuntime ociruntime.OciRuntime
+	portfills  syncmap.Map
+}
+
+type ExhaustPortsActionState struct {
+	ExecutionId      uuid.UUID
+	Sidecar          holder.SidecarOpts
+	ExhaustPortsOpts portfill.Opts
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[ExhaustPortsActionState]           = (*exhaustPortsAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[ExhaustPortsActionState] = (*exhaustPortsAction)(nil)
+	_ action_kit_sdk.ActionWithStop[ExhaustPortsActionState]   = (*exhaustPortsAction)(nil)
+)
+
+var exhaustPortsActionID = fmt.Sprintf("%s.network_exhaust_ports", BaseActionID)
+
+func NewNetworkExhaustPortsAction(r ociruntime.OciRuntime) action_kit_sdk.Action[ExhaustPortsActionState] {
+	return journaled[ExhaustPortsActionState](&exhaustPortsAction{
+		ociRuntime: r,
+	})
+}
+
+func (a *exhaustPortsAction) NewEmptyState() ExhaustPortsActionState {
+	return ExhaustPortsActionState{}
+}
+
+func (a *exhaustPortsAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          exhaustPortsActionID,
+		Label:       "Exhaust Ephemeral Ports",
+		Description: "Opens and holds outbound connections to the given destinations until the ephemeral port range of the host is almost used up.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(blackHoleIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         requireOciRuntime(),
+			SelectionTemplates: new(targetSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("Network"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Widgets: new([]action_kit_api.Widget{
+			action_kit_api.LineChartWidget{
+				Type:  action_kit_api.ComSteadybitWidgetLineChart,
+				Title: "Ephemeral Ports",
+				Identity: action_kit_api.LineChartWidgetIdentityConfig{
+					MetricName: "ephemeral_ports",
+					From:       "ports",
+					Mode:       action_kit_api.ComSteadybitWidgetLineChartIdentityModeWidgetPerValue,
+				},
+				Tooltip: new(action_kit_api.LineChartWidgetTooltipConfig{
+					MetricValueTitle: new("Ports"),
+				}),
+			},
+		}),
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long should the ports be held?"),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(0),
+			},
+			{
+				Name:         "usage",
+				Label:        "Usage",
+				Description:  new("Opens connections until this share of the ephemeral port range (net.ipv4.ip_local_port_range) is used."),
+				Type:         action_kit_api.ActionParameterTypePercentage,
+				DefaultValue: new("90"),
+				MinValue:     new(1),
+				MaxValue:     new(100),
+				Required:     new(true),
+				Order:        new(1),
+			},
+			{
+				Name:        "hostname",
+				Label:       "Destination Hostnames",
+				Description: new("Hosts the connections are opened to. They must accept the connections, spread them over several destinations to not overload a single one."),
+				Type:        action_kit_api.ActionParameterTypeStringArray,
+				Order:       new(2),
+			},
+			{
+				Name:        "ip",
+				Label:       "Destination IPs",
+				Description: new("IP addresses the connections are opened to. CIDR blocks are not supported."),
+				Type:        action_kit_api.ActionParameterTypeStringArray,
+				Order:       new(3),
+			},
+			{
+				Name:         "port",
+				Label:        "Destination Ports",
+				Description:  new("Ports the connections are opened to. Port ranges are not supported."),
+				Type:         action_kit_api.ActionParameterTypeStringArray,
+				DefaultValue: new("[\"80\"]"),
+				Required:     new(true),
+				Order:        new(4),
+			},
+			{
+				Name:        "excludeHostname",
+				Label:       "Exclude Hostnames",
+				Description: new("Never open connections to these hosts, e.g. if a hostname resolves to several addresses."),
+				Type:        action_kit_api.ActionParameterTypeStringArray,
+				Required:    new(false),
+				Advanced:    new(true),
+				Order:       new(104),
+			},
+			{
+				Name:        "excludeIp",
+				Label:       "Exclude IPs/CIDRs",
+				Description: new("Never open connections to these IP addresses or CIDR blocks."),
+				Type:        action_kit_api.ActionParameterTypeStringArray,
+				Required:    new(false),
+				Advanced:    new(true),
+				Order:       new(105),
+			},
+		},
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("1s"),
+		}),
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (a *exhaustPortsAction) Prepare(ctx context.Context, state *ExhaustPortsActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	if len(extutil.ToStringArray(request.Config["ip"])) == 0 && len(extutil.ToStringArray(request.Config["hostname"])) == 0 {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  "At least one destination hostname or IP is required",
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	initProcess, err := ociruntime.ReadLinuxProcessInfo(ctx, 1, specs.NetworkNamespace)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to read root process infos.", err)
+	}
+
+	sidecar := netfault.SidecarOpts{
+		TargetProcess: initProcess,
+		Id:            fmt.Sprintf("%s-host", request.ExecutionId.String()[24:]),
+	}
+	filter, messages, err := mapToNetworkFilter(ctx, a.ociRuntime, sidecar, request.Config, getRestrictedEndpoints(request))
+	if err != nil {
+		return nil, extension_kit.WrapError(err)
+	}
+
+	destinations, skipped, err := portDestinations(filter)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  err.Error(),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+	if len(skipped) > 0 {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("Skipping excluded destinations %s", strings.Join(skipped, ", ")),
+		})
+	}
+	if len(destinations) == 0 {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  "All destinations are excluded",
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	state.Sidecar = holder.SidecarOpts{
+		TargetProcess: sidecar.TargetProcess,
+		Id:            sidecar.Id,
+	}
+	state.ExhaustPortsOpts = portfill.Opts{
+		Destinations: destinations,
+		Usage:        extutil.ToInt(request.Config["usage"]),
+	}
+	state.ExecutionId = request.ExecutionId
+	return &action_kit_api.PrepareResult{Messages: &messages}, nil
+}
+
+// portDestinations turns the includes of the filter into host:port destinations. Destinations covered
+// by an exclude, like the agent or restricted endpoints, are skipped.
+func portDestinations(filter netfault.Filter) ([]string, []string, error) {
+	var destinations, skipped []string
+	for _, include := range filter.Include {
+		if ones, bits := include.Net.Mask.Size(); ones != bits {
+			return nil, nil, fmt.Errorf("Destination %s is not a single IP address", include.Net.String())
+		}
+		if include.PortRange.From != include.PortRange.To {
+			return nil, nil, fmt.Errorf("Destination port %d-%d is not a single port", include.PortRange.From, include.PortRange.To)
+		}
+
+		destination := net.JoinHostPort(include.Net.IP.String(), strconv.Itoa(int(include.PortRange.From)))
+		if isExcludedDestination(filter.Exclude, include.Net.IP, include.PortRange.From) {
+			skipped = append(skipped, destination)
+		} else {
+			destinations = append(destinations, destination)
+		}
+	}
+	return destinations, skipped, nil
+}
+
+func isExcludedDestination(excludes []network.NetWithPortRange, ip net.IP, port uint16) bool {
+	for _, exclude := range excludes {
+		if exclude.Net.Contains(ip) && exclude.PortRange.From <= port && port <= exclude.PortRange.To {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *exhaustPortsAction) portfill(ctx context.Context, sidecar holder.SidecarOpts, opts portfill.Opts) (holder.Holder, error) {
+	if config.Config.DisableRunc {
+		return portfill.NewPortfillProcess(opts)
+	}
+
+	return portfill.NewPortfillRunc(ctx, a.ociRuntime, sidecar, opts)
+}
+
+func (a *exhaustPortsAction) Start(ctx context.Context, state *ExhaustPortsActionState) (*action_kit_api.StartResult, error) {
+	portFill, err := a.portfill(ctx, state.Sidecar, state.ExhaustPortsOpts)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to prepare exhaust ephemeral ports on host", err)
+	}
+
+	a.portfills.Store(state.ExecutionId, portFill)
+	recordExecution(state.ExecutionId, exhaustPortsActionID, state)
+
+	if err := portFill.Start(); err != nil {
+		return nil, extension_kit.ToError("Failed to exhaust ephemeral ports on host", err)
+	}
+
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Starting exhaust ephemeral ports on host with args %s", strings.Join(portFill.Args(), " ")),
+			},
+		}),
+	}, nil
+}
+
+func (a *exhaustPortsAction) Status(_ context.Context, state *ExhaustPortsActionState) (*action_kit_api.StatusResult, error) {
+	metrics := portMetrics()
+
+	exited, err := a.exhaustPortsExited(state.ExecutionId)
+	if !exited {
+		return &action_kit_api.StatusResult{Completed: false, Metrics: &metrics}, nil
+	}
+
+	if err == nil {
+		return &action_kit_api.StatusResult{
+			Completed: true,
+			Metrics:   &metrics,
+			Messages: &[]action_kit_api.Message{
+				{
+					Level:   extutil.Ptr(action_kit_api.Info),
+					Message: "exhaust ephemeral ports on host stopped",
+				},
+			},
+		}, nil
+	}
+
+	errMessage := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		errMessage = fmt.Sprintf("%s\n%s", exitErr.Error(), string(exitErr.Stderr))
+	}
+	return &action_kit_api.StatusResult{
+		Completed: true,
+		Metrics:   &metrics,
+		Error: &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Failed),
+			Title:  fmt.Sprintf("Failed to exhaust ephemeral ports on host: %s", errMessage),
+		},
+	}, nil
+}
+
+func (a *exhaustPortsAction) Stop(ctx context.Context, state *ExhaustPortsActionState) (*action_kit_api.StopResult, error) {
+	messages := make([]action_kit_api.Message, 0)
+
+	stopped, err := a.stopExhaustPortsHost(ctx, state)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to stop exhaust ephemeral ports on host", err)
+	}
+	forgetExecution(state.ExecutionId)
+	if stopped {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Info),
+			Message: "Canceled exhaust ephemeral ports on host",
+		})
+	}
+
+	return &action_kit_api.StopResult{
+		Messages: &messages,
+	}, nil
+}
+
+func (a *exhaustPortsAction) exhaustPortsExited(executionId uuid.UUID) (bool, error) {
+	s, ok := a.portfills.Load(executionId)
+	if !ok {
+		return true, nil
+	}
+	return s.(holder.Holder).Exited()
+}
+
+func (a *exhaustPortsAction) stopExhaustPortsHost(ctx context.Context, state *ExhaustPortsActionState) (bool, error) {
+	s, ok := a.portfills.LoadAndDelete(state.ExecutionId)
+	if !ok {
+		// the handle is lost if the extension was restarted meanwhile, but the sidecar still holds on
+		if config.Config.DisableRunc {
+			return false, nil
+		}
+		return false, holder.DeleteSidecar(ctx, a.ociRuntime, state.Sidecar.Id)
+	}
+	return true, s.(holder.Holder).Stop()
+}
+
+func portMetrics() []action_kit_api.Metric {
+	usage, err := readPortUsage()
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to read the ephemeral port usage")
+		return []action_kit_api.Metric{}
+	}
+	now := time.Now()
+	return []action_kit_api.Metric{
+		{
+			Name:      new("ephemeral_ports"),
+			Metric:    map[string]string{"ports": "Used"},
+			Value:     float64(usage.Used),
+			Timestamp: now,
+		},
+		{
+			Name:      new("ephemeral_ports"),
+			Metric:    map[string]string{"ports": "Range"},
+			Value:     float64(usage.Range),
+			Timestamp: now,
+		},
+	}
+}
+
+// readPortUsage reads the sockets of the host network namespace
+var readPortUsage = func() (portfill.Usage, error) {
+	return portfill.ReadUsage("/proc/1/net")
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/extension-host/exthost/portfill"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exhaustPortsRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestActionExhaustPorts_PrepareRequiresDestination(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	action := &exhaustPortsAction{}
+
+	state := action.NewEmptyState()
+	request := exhaustPortsRequest(map[string]any{"duration": "10000", "usage": 90, "port": []string{"80"}})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "At least one destination hostname or IP is required", result.Error.Title)
+}
+
+func TestPortDestinations(t *testing.T) {
+	filter := netfault.Filter{
+		Include: []network.NetWithPortRange{
+			{Net: hostNet("10.0.0.1"), PortRange: network.PortRange{From: 80, To: 80}},
+			{Net: hostNet("10.0.0.2"), PortRange: network.PortRange{From: 80, To: 80}},
+			{Net: hostNet("fd00::1"), PortRange: network.PortRange{From: 443, To: 443}},
+		},
+		Exclude: []network.NetWithPortRange{
+			{Net: hostNet("10.0.0.2"), PortRange: network.PortRangeAny},
+			{Net: hostNet("fd00::1"), PortRange: network.PortRange{From: 8080, To: 8080}},
+		},
+	}
+
+	destinations, skipped, err := portDestinations(filter)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1:80", "[fd00::1]:443"}, destinations)
+	assert.Equal(t, []string{"10.0.0.2:80"}, skipped)
+}
+
+func TestPortDestinationsRejectsRanges(t *testing.T) {
+	_, cidr, _ := net.ParseCIDR("10.0.0.0/24")
+	_, _, err := portDestinations(netfault.Filter{Include: []network.NetWithPortRange{{Net: *cidr, PortRange: network.PortRange{From: 80, To: 80}}}})
+	assert.ErrorContains(t, err, "10.0.0.0/24 is not a single IP address")
+
+	_, _, err = portDestinations(netfault.Filter{Include: []network.NetWithPortRange{{Net: hostNet("10.0.0.1"), PortRange: network.PortRangeAny}}})
+	assert.ErrorContains(t, err, "is not a single port")
+}
+
+func TestActionExhaustPorts_StatusReportsUsage(t *testing.T) {
+	saved := readPortUsage
+	readPortUsage = func() (portfill.Usage, error) { return portfill.Usage{Used: 25000, Range: 28232}, nil }
+	defer func() { readPortUsage = saved }()
+
+	action := &exhaustPortsAction{}
+	state := ExhaustPortsActionState{ExecutionId: uuid.New()}
+	status, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	require.Len(t, *status.Metrics, 2)
+	assert.Equal(t, map[string]string{"ports": "Used"}, (*status.Metrics)[0].Metric)
+	assert.Equal(t, float64(25000), (*status.Metrics)[0].Value)
+	assert.Equal(t, map[string]string{"ports": "Range"}, (*status.Metrics)[1].Metric)
+	assert.Equal(t, float64(28232), (*status.Metrics)[1].Value)
+}
+
+func hostNet(ip string) net.IPNet {
+	parsed := net.ParseIP(ip)
+	if v4 := parsed.To4(); v4 != nil {
+		return net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}
+	}
+	return net.IPNet{IP: parsed, Mask: net.CIDRMask(128, 128)}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/extension-host/exthost/ifb"
+)
+
+var (
+	ifbAvailable = ifb.Available
+	setupIfb     = ifb.Setup
+	teardownIfb  = ifb.Teardown
+)
+
+// trafficShapingInterfacesOf returns the interfaces of the opts shaping the traffic with tc, or nil if the
+// fault is applied with iptables, which matches incoming packets itself.
+func trafficShapingInterfacesOf(opts netfault.Opts) *[]string {
+	switch o := opts.(type) {
+	case *netfault.DelayOpts:
+		return &o.Interfaces
+	case *netfault.PackageLossOpts:
+		return &o.Interfaces
+	case *netfault.CorruptPackagesOpts:
+		return &o.Interfaces
+	case *netfault.LimitBandwidthOpts:
+		return &o.Interfaces
+	case *netfault.DuplicatePackagesOpts:
+		return &o.Interfaces
+	case *netfault.ReorderPackagesOpts:
+		return &o.Interfaces
+	}
+	return nil
+}
+
+// redirectIngress prepares the redirects of the incoming traffic of the interfaces to ifb devices for tc
+// faults on ingress, and applies the fault to the ifb devices instead of the interfaces.
+func redirectIngress(state *NetworkActionState, opts netfault.Opts) error {
+	filter := networkFilterOf(opts)
+	interfaces := trafficShapingInterfacesOf(opts)
+	if filter == nil || filter.Direction != netfault.DirectionIngress || interfaces == nil {
+		return nil
+	}
+	if err := ifbAvailable(); err != nil {
+		return fmt.Errorf("affecting incoming traffic requires ifb devices: %w", err)
+	}
+	state.Redirects = ifb.Redirects(state.ExecutionId, *interfaces)
+	*interfaces = ifb.Devices(state.Redirects)
+	return nil
+}
+
+// setupRedirects sets up the redirects to the ifb devices and marks the traffic of the cgroups if the
+// redirects are restricted to it. Everything set up already is torn down if it fails.
+func setupRedirects(ctx context.Context, r netfault.CommandRunner, state *NetworkActionState) error {
+	if err := setupIfb(ctx, r, state.Redirects); err != nil {
+		return err
+	}
+	if redirectsMarked(state.Redirects) {
+		if err := markCgroups(ctx, r, state.Cgroups); err != nil {
+			return errors.Join(err, teardownIfb(ctx, r, state.Redirects))
+		}
+	}
+	return nil
+}
+
+func teardownRedirects(ctx context.Context, r netfault.CommandRunner, state *NetworkActionState) error {
+	var errs []error
+	if redirectsMarked(state.Redirects) {
+		errs = append(errs, unmarkCgroups(ctx, r, state.Cgroups))
+	}
+	return errors.Join(append(errs, teardownIfb(ctx, r, state.Redirects))...)
+}
+
+func redirectsMarked(redirects []ifb.Redirect) bool {
+	return len(redirects) > 0 && redirects[0].Mark != 0
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/ifb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectIngress(t *testing.T) {
+	ifbAvailable = func() error { return nil }
+	defer func() { ifbAvailable = ifb.Available }()
+	executionId := uuid.MustParse("1a2b3c4d-0000-0000-0000-000000000000")
+
+	state := NetworkActionState{ExecutionId: executionId}
+	opts := &netfault.DelayOpts{Filter: netfault.Filter{Direction: netfault.DirectionIngress}, Interfaces: []string{"eth0", "eth1"}}
+	require.NoError(t, redirectIngress(&state, opts))
+	assert.Equal(t, []ifb.Redirect{{Interface: "eth0", Device: "ifb1a2b3c4d0"}, {Interface: "eth1", Device: "ifb1a2b3c4d1"}}, state.Redirects)
+	assert.Equal(t, []string{"ifb1a2b3c4d0", "ifb1a2b3c4d1"}, opts.Interfaces)
+
+	state = NetworkActionState{ExecutionId: executionId}
+	egress := &netfault.PackageLossOpts{Filter: netfault.Filter{Direction: netfault.DirectionEgress}, Interfaces: []string{"eth0"}}
+	require.NoError(t, redirectIngress(&state, egress))
+	assert.Empty(t, state.Redirects)
+	assert.Equal(t, []string{"eth0"}, egress.Interfaces)
+
+	// iptables matches incoming packets itself
+	blackhole := &netfault.BlackholeOpts{Filter: netfault.Filter{Direction: netfault.DirectionIngress}}
+	require.NoError(t, redirectIngress(&state, blackhole))
+	assert.Empty(t, state.Redirects)
+}
+
+func TestRedirectIngressRequiresIfb(t *testing.T) {
+	ifbAvailable = func() error { return errors.New("the ifb kernel module is not loaded") }
+	defer func() { ifbAvailable = ifb.Available }()
+
+	state := NetworkActionState{ExecutionId: uuid.New()}
+	opts := &netfault.CorruptPackagesOpts{Filter: netfault.Filter{Direction: netfault.DirectionIngress}, Interfaces: []string{"eth0"}}
+	assert.ErrorContains(t, redirectIngress(&state, opts), "affecting incoming traffic requires ifb devices")
+}
+
+func TestNetworkActionRedirectsIngressWhileApplied(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+	var calls []string
+	applyNetworkFault = func(_ context.Context, _ netfault.CommandRunner, opts netfault.Opts) (netfault.QdiscSnapshot, error) {
+		calls = append(calls, "apply "+opts.(*netfault.DelayOpts).Interfaces[0])
+		return netfault.QdiscSnapshot{}, nil
+	}
+	revertNetworkFault = func(_ context.Context, _ netfault.CommandRunner, opts netfault.Opts, _ netfault.QdiscSnapshot) error {
+		calls = append(calls, "revert "+opts.(*netfault.DelayOpts).Interfaces[0])
+		return nil
+	}
+	setupIfb = func(_ context.Context, _ netfault.CommandRunner, redirects []ifb.Redirect) error {
+		calls = append(calls, "setup "+redirects[0].Device)
+		return nil
+	}
+	teardownIfb = func(_ context.Context, _ netfault.CommandRunner, redirects []ifb.Redirect) error {
+		calls = append(calls, "teardown "+redirects[0].Device)
+		return nil
+	}
+	defer func() {
+		applyNetworkFault = netfault.Apply
+		revertNetworkFault = netfault.Revert
+		setupIfb = ifb.Setup
+		teardownIfb = ifb.Teardown
+	}()
+
+	raw, err := json.Marshal(&netfault.DelayOpts{Interfaces: []string{"ifb1a2b3c4d0"}})
+	require.NoError(t, err)
+	action := &networkAction{optsDecoder: delayDecode, description: getNetworkDelayDescription()}
+	state := NetworkActionState{
+		ExecutionId: uuid.New(),
+		NetworkOpts: raw,
+		Redirects:   []ifb.Redirect{{Interface: "eth0", Device: "ifb1a2b3c4d0"}},
+	}
+
+	_, err = action.Start(context.Background(), &state)
+	require.NoError(t, err)
+	assert.True(t, state.Redirected)
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"setup ifb1a2b3c4d0",
+		"apply ifb1a2b3c4d0",
+		"revert ifb1a2b3c4d0",
+		"teardown ifb1a2b3c4d0",
+	}, calls)
+}
+
+func TestNetworkActionFailingRedirectLeavesNothingToRevert(t *testing.T) {
+	var calls []string
+	applyNetworkFault = func(_ context.Context, _ netfault.CommandRunner, _ netfault.Opts) (netfault.QdiscSnapshot, error) {
+		calls = append(calls, "apply")
+		return netfault.QdiscSnapshot{}, nil
+	}
+	revertNetworkFault = func(_ context.Context, _ netfault.CommandRunner, _ netfault.Opts, _ netfault.QdiscSnapshot) error {
+		calls = append(calls, "revert")
+		return nil
+	}
+	setupIfb = func(_ context.Context, _ netfault.CommandRunner, _ []ifb.Redirect) error {
+		return errors.New("Exclusivity flag on, cannot modify")
+	}
+	teardownIfb = func(_ context.Context, _ netfault.CommandRunner, _ []ifb.Redirect) error {
+		calls = append(calls, "teardown")
+		return nil
+	}
+	defer func() {
+		applyNetworkFault = netfault.Apply
+		revertNetworkFault = netfault.Revert
+		setupIfb = ifb.Setup
+		teardownIfb = ifb.Teardown
+	}()
+
+	raw, err := json.Marshal(&netfault.DelayOpts{Interfaces: []string{"ifb1a2b3c4d0"}})
+	require.NoError(t, err)
+	action := &networkAction{optsDecoder: delayDecode, description: getNetworkDelayDescription()}
+	state := NetworkActionState{
+		ExecutionId: uuid.New(),
+		NetworkOpts: raw,
+		Redirects:   []ifb.Redirect{{Interface: "eth0", Device: "ifb1a2b3c4d0"}},
+	}
+
+	_, err = action.Start(context.Background(), &state)
+	assert.Error(t, err)
+	assert.False(t, state.Redirected)
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Empty(t, calls)
+}
+
+func TestNetworkActionsHaveDistinctParameterOrders(t *testing.T) {
+	for _, newAction := range []func(ociruntime.OciRuntime) action_kit_sdk.Action[NetworkActionState]{
+		NewNetworkLimitBandwidthContainerAction,
+		NewNetworkBlackholeContainerAction,
+		NewNetworkCorruptPackagesContainerAction,
+		NewNetworkDelayContainerAction,
+		NewNetworkBlockDnsContainerAction,
+		NewNetworkDuplicatePackagesContainerAction,
+		NewNetworkPackageLossContainerAction,
+		NewNetworkReorderPackagesContainerAction,
+		NewNetworkTcpResetAction,
+	} {
+		description := newAction(nil).Describe()
+		orders := map[int]string{}
+		for _, p := range description.Parameters {
+			require.NotNil(t, p.Order, "%s: %s", description.Id, p.Name)
+			if other, ok := orders[*p.Order]; ok {
+				t.Errorf("%s: %s and %s share order %d", description.Id, other, p.Name, *p.Order)
+			}
+			orders[*p.Order] = p.Name
+		}
+	}
+}
+
+func TestRedirectsMarkTheTrafficOfCgroups(t *testing.T) {
+	var calls []string
+	setupIfb = func(_ context.Context, _ netfault.CommandRunner, redirects []ifb.Redirect) error {
+		calls = append(calls, "setup "+redirects[0].Device)
+		return nil
+	}
+	teardownIfb = func(_ context.Context, _ netfault.CommandRunner, redirects []ifb.Redirect) error {
+		calls = append(calls, "teardown "+redirects[0].Device)
+		return nil
+	}
+	markCgroups = func(_ context.Context, _ netfault.CommandRunner, cgroups []string) error {
+		calls = append(calls, "mark "+cgroups[0])
+		return errors.New("Couldn't load match `cgroup'")
+	}
+	unmarkCgroups = func(_ context.Context, _ netfault.CommandRunner, cgroups []string) error {
+		calls = append(calls, "unmark "+cgroups[0])
+		return nil
+	}
+	defer func() {
+		setupIfb = ifb.Setup
+		teardownIfb = ifb.Teardown
+		markCgroups = markCgroupTraffic
+		unmarkCgroups = unmarkCgroupTraffic
+	}()
+
+	state := NetworkActionState{
+		Cgroups:   []string{"/system.slice/nginx.service"},
+		Redirects: []ifb.Redirect{{Interface: "eth0", Device: "ifb1a2b3c4d0", Mark: cgroupMark}},
+	}
+	assert.Error(t, setupRedirects(context.Background(), nil, &state))
+	assert.Equal(t, []string{"setup ifb1a2b3c4d0", "mark /system.slice/nginx.service", "teardown ifb1a2b3c4d0"}, calls)
+
+	calls = nil
+	require.NoError(t, teardownRedirects(context.Background(), nil, &state))
+	assert.Equal(t, []string{"unmark /system.slice/nginx.service", "teardown ifb1a2b3c4d0"}, calls)
+}
@@ -0,0 +1,371 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	networkutils "github.com/steadybit/action-kit/go/action_kit_commons/network"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/holder"
+	"github.com/steadybit/extension-host/exthost/link"
+	"github.com/steadybit/extension-host/exthost/route"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+	"golang.org/x/sync/syncmap"
+)
+
+// linkRestoreTimeout limits how long Stop waits for the interface to be set up again
+const linkRestoreTimeout = 30 * time.Second
+
+type linkAction struct {
+	ociRuntime ociruntime.OciRuntime
+	links      syncmap.Map
+}
+
+type LinkActionState struct {
+	ExecutionId uuid.UUID
+	Sidecar     holder.SidecarOpts
+	LinkOpts    link.Opts
+	// SetDown tells whether the holder may have set the interface down. It is cleared if the holder found
+	// the interface down already, which must not be set up when the attack ends.
+	SetDown bool
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[LinkActionState]           = (*linkAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[LinkActionState] = (*linkAction)(nil)
+	_ action_kit_sdk.ActionWithStop[LinkActionState]   = (*linkAction)(nil)
+)
+
+var linkActionID = fmt.Sprintf("%s.network_link_down", BaseActionID)
+
+func NewNetworkLinkDownAction(r ociruntime.OciRuntime) action_kit_sdk.Action[LinkActionState] {
+	return journaled[LinkActionState](&linkAction{
+		ociRuntime: r,
+	})
+}
+
+func (a *linkAction) NewEmptyState() LinkActionState {
+	return LinkActionState{}
+}
+
+func (a *linkAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          linkActionID,
+		Label:       "Network Interface Down",
+		Description: "Sets a network interface of the host administratively down or flaps it. The interface is set up again when the attack ends.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(blackHoleIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         requireOciRuntime(),
+			SelectionTemplates: new(targetSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("Network"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long should the interface be down?"),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(0),
+			},
+			{
+				Name:        "networkInterface",
+				Label:       "Network Interface",
+				Description: new("The network interface to set down."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(true),
+				Order:       new(1),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ParameterOptionsFromTargetAttribute{Attribute: "host.nic"},
+				}),
+			},
+			{
+				Name:         "flapInterval",
+				Label:        "Flap Interval",
+				Description:  new("Flap the interface, it is down and up for this long in turns. The interface is kept down if not set."),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("0s"),
+				Required:     new(false),
+				Order:        new(2),
+			},
+			{
+				Name:         "allowAgentInterface",
+				Label:        "Allow Agent Interface",
+				Description:  new("Allow to set down the interface carrying the connection of the agent. The agent may lose the connection and not be able to stop the attack, the interface is still set up again after the duration."),
+				Type:         action_kit_api.ActionParameterTypeBoolean,
+				DefaultValue: new("false"),
+				Required:     new(false),
+				Advanced:     new(true),
+				Order:        new(3),
+			},
+		},
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("1s"),
+		}),
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (a *linkAction) Prepare(ctx context.Context, state *LinkActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	opts := link.Opts{
+		Interface: extutil.ToString(request.Config["networkInterface"]),
+		Interval:  time.Duration(extutil.ToInt64(request.Config["flapInterval"])) * time.Millisecond,
+		Duration:  time.Duration(extutil.ToInt64(request.Config["duration"])) * time.Millisecond,
+	}
+	if !slices.Contains(ownNetworkInterfaces(), opts.Interface) {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Unknown network interface '%s'", opts.Interface),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+	if opts.Interval > 0 && opts.Interval < time.Second {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  "The flap interval must be at least 1s",
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	if !extutil.ToBool(request.Config["allowAgentInterface"]) {
+		protected, err := agentInterfaces(getRestrictedEndpoints(request))
+		if err != nil {
+			return nil, extension_kit.ToError("Failed to determine the interfaces carrying the agent connection. Allow the agent interface explicitly to set it down anyway.", err)
+		}
+		if slices.Contains(protected, opts.Interface) {
+			return &action_kit_api.PrepareResult{
+				Error: new(action_kit_api.ActionKitError{
+					Title:  fmt.Sprintf("The network interface '%s' carries the connection of the agent", opts.Interface),
+					Detail: new("Setting it down may prevent the agent from stopping the attack. Choose another interface or allow the agent interface explicitly."),
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+	}
+
+	initProcess, err := ociruntime.ReadLinuxProcessInfo(ctx, 1, specs.NetworkNamespace)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to read root process infos.", err)
+	}
+
+	state.Sidecar = holder.SidecarOpts{
+		TargetProcess: initProcess,
+		Id:            fmt.Sprintf("%s-host", request.ExecutionId.String()[24:]),
+	}
+	state.LinkOpts = opts
+	state.ExecutionId = request.ExecutionId
+	return nil, nil
+}
+
+func (a *linkAction) link(ctx context.Context, sidecar holder.SidecarOpts, opts link.Opts) (holder.Holder, error) {
+	if config.Config.DisableRunc {
+		return link.NewLinkProcess(opts)
+	}
+
+	return link.NewLinkRunc(ctx, a.ociRuntime, sidecar, opts)
+}
+
+func (a *linkAction) Start(ctx context.Context, state *LinkActionState) (*action_kit_api.StartResult, error) {
+	l, err := a.link(ctx, state.Sidecar, state.LinkOpts)
+	if err != nil {
+		return nil, extension_kit.ToError(fmt.Sprintf("Failed to prepare setting %s down", state.LinkOpts.Interface), err)
+	}
+
+	a.links.Store(state.ExecutionId, l)
+	// journaled, so the interface is set up again even if the extension crashes
+	state.SetDown = true
+	recordExecution(state.ExecutionId, linkActionID, state)
+
+	if err := l.Start(); err != nil {
+		state.SetDown = false
+		recordExecution(state.ExecutionId, linkActionID, state)
+		return nil, extension_kit.ToError(fmt.Sprintf("Failed to set %s down", state.LinkOpts.Interface), err)
+	}
+
+	message := fmt.Sprintf("Setting %s down", state.LinkOpts.Interface)
+	if state.LinkOpts.Interval > 0 {
+		message = fmt.Sprintf("Flapping %s every %s", state.LinkOpts.Interface, state.LinkOpts.Interval)
+	}
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("%s with args %s", message, strings.Join(l.Args(), " ")),
+			},
+		}),
+	}, nil
+}
+
+func (a *linkAction) Status(_ context.Context, state *LinkActionState) (*action_kit_api.StatusResult, error) {
+	exited, err := a.linkExited(state.ExecutionId)
+	if !exited {
+		return &action_kit_api.StatusResult{Completed: false}, nil
+	}
+
+	if err == nil {
+		// the holder set the interface up again before exiting
+		state.SetDown = false
+		recordExecution(state.ExecutionId, linkActionID, state)
+		return &action_kit_api.StatusResult{
+			Completed: true,
+			Messages: &[]action_kit_api.Message{
+				{
+					Level:   extutil.Ptr(action_kit_api.Info),
+					Message: fmt.Sprintf("%s set up again", state.LinkOpts.Interface),
+				},
+			},
+		}, nil
+	}
+
+	if isDownAlready(err) {
+		state.SetDown = false
+		recordExecution(state.ExecutionId, linkActionID, state)
+	}
+
+	errMessage := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		errMessage = fmt.Sprintf("%s\n%s", exitErr.Error(), string(exitErr.Stderr))
+	}
+	return &action_kit_api.StatusResult{
+		Completed: true,
+		Error: &action_kit_api.ActionKitError{
+			Status: extutil.Ptr(action_kit_api.Failed),
+			Title:  fmt.Sprintf("Failed to set %s down: %s", state.LinkOpts.Interface, errMessage),
+		},
+	}, nil
+}
+
+func (a *linkAction) Stop(ctx context.Context, state *LinkActionState) (*action_kit_api.StopResult, error) {
+	if s, ok := a.links.LoadAndDelete(state.ExecutionId); ok {
+		l := s.(holder.Holder)
+		if err := l.Stop(); err != nil {
+			return nil, extension_kit.ToError(fmt.Sprintf("Failed to stop setting %s down", state.LinkOpts.Interface), err)
+		}
+		// the holder sets the interface up again when it's terminated, unless it was down already
+		if exited, err := l.Exited(); exited && (err == nil || isDownAlready(err)) {
+			state.SetDown = false
+		}
+	}
+
+	if !state.SetDown {
+		forgetExecution(state.ExecutionId)
+		return nil, nil
+	}
+
+	// the holder sets the interface up when it's terminated, but it may have been killed or may be gone
+	// together with a crashed extension
+	if err := a.restore(ctx, state); err != nil {
+		return nil, extension_kit.ToError(fmt.Sprintf("Failed to set %s up again", state.LinkOpts.Interface), err)
+	}
+	state.SetDown = false
+	forgetExecution(state.ExecutionId)
+
+	return &action_kit_api.StopResult{
+		Messages: &[]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("%s is up again", state.LinkOpts.Interface),
+			},
+		},
+	}, nil
+}
+
+func (a *linkAction) restore(ctx context.Context, state *LinkActionState) error {
+	opts := state.LinkOpts
+	opts.Restore = true
+	sidecar := state.Sidecar
+	sidecar.Id = strings.TrimSuffix(sidecar.Id, "-host") + "-restore"
+
+	l, err := a.link(ctx, sidecar, opts)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Stop() }()
+	if err := l.Start(); err != nil {
+		return err
+	}
+	return waitForExit(ctx, l, linkRestoreTimeout)
+}
+
+func waitForExit(ctx context.Context, h holder.Holder, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if exited, err := h.Exited(); exited {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// isDownAlready tells whether the holder exited as the interface was down already.
+func isDownAlready(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == link.ExitDownAlready
+}
+
+func (a *linkAction) linkExited(executionId uuid.UUID) (bool, error) {
+	s, ok := a.links.Load(executionId)
+	if !ok {
+		return true, nil
+	}
+	return s.(holder.Holder).Exited()
+}
+
+var ownNetworkInterfaces = networkutils.GetOwnNetworkInterfaces
+
+// agentInterfaces returns the interfaces of the host the agent's connections to the extension and the
+// restricted endpoints, e.g. the platform, are routed on. The connections are looked up in the extension's
+// network namespace, where the agent connects to, and the routes in the host's.
+var agentInterfaces = func(restrictedEndpoints []action_kit_api.RestrictedEndpoint) ([]string, error) {
+	destinations, err := link.ConnectionPeers("/proc/self/net", int(config.Config.Port))
+	if err != nil {
+		return nil, err
+	}
+	if len(destinations) == 0 {
+		return nil, fmt.Errorf("no connection of the agent to port %d found, it may connect through a unix socket", config.Config.Port)
+	}
+	for _, endpoint := range restrictedEndpoints {
+		_, cidr, err := net.ParseCIDR(endpoint.Cidr)
+		if err != nil || cidr.IP.IsUnspecified() {
+			continue
+		}
+		destinations = append(destinations, cidr.IP)
+	}
+	return route.Interfaces(hostNetworkNamespace, destinations)
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	networkutils "github.com/steadybit/action-kit/go/action_kit_commons/network"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/holder"
+	"github.com/steadybit/extension-host/exthost/link"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeLinkInterfaces(t *testing.T, own, agent []string) {
+	ownNetworkInterfaces = func() []string { return own }
+	savedAgentInterfaces := agentInterfaces
+	agentInterfaces = func([]action_kit_api.RestrictedEndpoint) ([]string, error) { return agent, nil }
+	t.Cleanup(func() {
+		ownNetworkInterfaces = networkutils.GetOwnNetworkInterfaces
+		agentInterfaces = savedAgentInterfaces
+	})
+}
+
+func linkDownRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestActionLinkDown_PrepareRejectsUnknownInterface(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fakeLinkInterfaces(t, []string{"eth0", "eth1"}, []string{"eth0"})
+	action := &linkAction{}
+
+	state := action.NewEmptyState()
+	request := linkDownRequest(map[string]any{"duration": "10000", "networkInterface": "wlan0"})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "Unknown network interface 'wlan0'", result.Error.Title)
+}
+
+func TestActionLinkDown_PrepareRejectsAgentInterface(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fakeLinkInterfaces(t, []string{"eth0", "eth1"}, []string{"eth0"})
+	action := &linkAction{}
+
+	state := action.NewEmptyState()
+	request := linkDownRequest(map[string]any{"duration": "10000", "networkInterface": "eth0"})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "The network interface 'eth0' carries the connection of the agent", result.Error.Title)
+}
+
+func TestActionLinkDown_PrepareRejectsShortFlapInterval(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fakeLinkInterfaces(t, []string{"eth0", "eth1"}, []string{"eth0"})
+	action := &linkAction{}
+
+	state := action.NewEmptyState()
+	request := linkDownRequest(map[string]any{"duration": "10000", "networkInterface": "eth1", "flapInterval": 200})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "The flap interval must be at least 1s", result.Error.Title)
+}
+
+func TestActionLinkDown_PreparePassesDuration(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fakeLinkInterfaces(t, []string{"eth0", "eth1"}, []string{"eth0"})
+	action := &linkAction{}
+
+	state := action.NewEmptyState()
+	request := linkDownRequest(map[string]any{"duration": "10000", "networkInterface": "eth1", "flapInterval": 2000})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	assert.Equal(t, link.Opts{Interface: "eth1", Interval: 2 * time.Second, Duration: 10 * time.Second}, state.LinkOpts)
+	assert.False(t, state.SetDown)
+}
+
+type exitedHolder struct {
+	holder.Holder
+	err error
+}
+
+func (h *exitedHolder) Stop() error           { return nil }
+func (h *exitedHolder) Exited() (bool, error) { return true, h.err }
+func (h *exitedHolder) Args() []string        { return []string{"link"} }
+func (h *exitedHolder) Output() string        { return "" }
+
+func TestActionLinkDown_StopKeepsInterfaceWhichWasDownAlready(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+	downAlready := exec.Command("sh", "-c", fmt.Sprintf("exit %d", link.ExitDownAlready)).Run()
+	require.Error(t, downAlready)
+
+	action := &linkAction{}
+	state := LinkActionState{ExecutionId: uuid.New(), LinkOpts: link.Opts{Interface: "eth1"}, SetDown: true}
+	action.links.Store(state.ExecutionId, &exitedHolder{err: downAlready})
+
+	status, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.False(t, state.SetDown)
+
+	// restoring would fail, as the test binary started as holder is no link holder
+	state.SetDown = true
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.False(t, state.SetDown)
+}
+
+func TestActionLinkDown_StopSkipsRestoreAfterCleanExit(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+
+	action := &linkAction{}
+	state := LinkActionState{ExecutionId: uuid.New(), LinkOpts: link.Opts{Interface: "eth1"}, SetDown: true}
+	action.links.Store(state.ExecutionId, &exitedHolder{})
+
+	status, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.Nil(t, status.Error)
+	assert.False(t, state.SetDown)
+
+	// restoring would fail, as the test binary started as holder is no link holder
+	state.SetDown = true
+	result, err := action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.False(t, state.SetDown)
+}
+
+func TestActionLinkDown_StopWithoutSetDownDoesNothing(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+
+	action := &linkAction{}
+	state := LinkActionState{ExecutionId: uuid.New(), LinkOpts: link.Opts{Interface: "eth1"}}
+	_, err := action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
 	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
@@ -16,12 +18,12 @@ import (
 )
 
 func NewNetworkPackageLossContainerAction(r ociruntime.OciRuntime) action_kit_sdk.Action[NetworkActionState] {
-	return &networkAction{
+	return journaled[NetworkActionState](&networkAction{
 		ociRuntime:   r,
 		optsProvider: packageLoss(r),
 		optsDecoder:  packageLossDecode,
 		description:  getNetworkPackageLossDescription(),
-	}
+	})
 }
 
 func getNetworkPackageLossDescription() action_kit_api.ActionDescription {
@@ -32,33 +34,36 @@ func getNetworkPackageLossDescription() action_kit_api.ActionDescription {
 		Version:     extbuild.GetSemverVersionStringOrUnknown(),
 		Icon:        new(lossIcon),
 		TargetSelection: &action_kit_api.TargetSelection{
-			TargetType:         targetID,
+			TargetType:         requireCapability(capabilityTc, capabilityDig),
 			SelectionTemplates: &targetSelectionTemplates,
 		},
 		Technology:  new("Linux Host"),
 		Category:    new("Network"),
 		Kind:        action_kit_api.Attack,
 		TimeControl: action_kit_api.TimeControlExternal,
-		Parameters: append(
+		Parameters: slices.Concat(
 			commonNetworkParameters,
-			action_kit_api.ActionParameter{
-				Name:         "percentage",
-				Label:        "Network Loss",
-				Description:  new("How much of the traffic should be lost?"),
-				Type:         action_kit_api.ActionParameterTypePercentage,
-				DefaultValue: new("70"),
-				Required:     new(true),
-				Order:        new(1),
-			},
-			action_kit_api.ActionParameter{
-				Name:        "networkInterface",
-				Label:       "Network Interface",
-				Description: new("Target Network Interface which should be affected. All if none specified."),
-				Type:        action_kit_api.ActionParameterTypeStringArray,
-				Required:    new(false),
-				Advanced:    new(true),
-				Order:       new(106),
+			[]action_kit_api.ActionParameter{
+				{
+					Name:         "percentage",
+					Label:        "Network Loss",
+					Description:  new("How much of the traffic should be lost?"),
+					Type:         action_kit_api.ActionParameterTypePercentage,
+					DefaultValue: new("70"),
+					Required:     new(true),
+					Order:        new(1),
+				},
+				{
+					Name:        "networkInterface",
+					Label:       "Network Interface",
+					Description: new("Target Network Interface which should be affected. All if none specified."),
+					Type:        action_kit_api.ActionParameterTypeStringArray,
+					Required:    new(false),
+					Advanced:    new(true),
+					Order:       new(106),
+				},
 			},
+			networkProcessParameters,
 		),
 	}
 }
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-host/config"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// minPulseDuration is the resolution of the pulse, as it is driven by the status calls
+const minPulseDuration = time.Second
+
+type NetworkPulse struct {
+	On, Off time.Duration
+	// Jitter varies each on and off period randomly by up to this percentage
+	Jitter uint
+}
+
+var networkPulseParameters = []action_kit_api.ActionParameter{
+	{
+		Name:        "pulseOn",
+		Label:       "Pulse On",
+		Description: new("Apply the fault for this long, then revert it for the off duration and repeat until the attack ends. The fault is applied constantly if not set."),
+		Type:        action_kit_api.ActionParameterTypeDuration,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(112),
+	},
+	{
+		Name:        "pulseOff",
+		Label:       "Pulse Off",
+		Description: new("How long to revert the fault between the on durations?"),
+		Type:        action_kit_api.ActionParameterTypeDuration,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(113),
+	},
+	{
+		Name:         "pulseJitter",
+		Label:        "Pulse Jitter",
+		Description:  new("Vary each on and off duration randomly by up to this share."),
+		Type:         action_kit_api.ActionParameterTypePercentage,
+		DefaultValue: new("0"),
+		MinValue:     new(0),
+		MaxValue:     new(100),
+		Required:     new(false),
+		Advanced:     new(true),
+		Order:        new(114),
+	},
+}
+
+// withNetworkPulse adds the pulse parameters to the description of a network action if pulses are enabled,
+// and the status endpoint if pulses are enabled or the action has steps.
+func withNetworkPulse(description action_kit_api.ActionDescription, steps bool) action_kit_api.ActionDescription {
+	if config.Config.NetworkPulse || steps {
+		description.Status = new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("1s"),
+		})
+	}
+	if !config.Config.NetworkPulse {
+		return description
+	}
+	description.Parameters = append(slices.Clone(description.Parameters), networkPulseParameters...)
+	var widgets []action_kit_api.Widget
+	if description.Widgets != nil {
+		widgets = slices.Clone(*description.Widgets)
+	}
+	description.Widgets = new(append(widgets, action_kit_api.LineChartWidget{
+		Type:  action_kit_api.ComSteadybitWidgetLineChart,
+		Title: "Network Fault Pulse",
+		Identity: action_kit_api.LineChartWidgetIdentityConfig{
+			MetricName: "network_fault",
+			From:       "network_fault",
+			Mode:       action_kit_api.ComSteadybitWidgetLineChartIdentityModeWidgetPerValue,
+		},
+		Tooltip: new(action_kit_api.LineChartWidgetTooltipConfig{
+			MetricValueTitle: new("Applied"),
+		}),
+	}))
+	return description
+}
+
+func networkPulse(request action_kit_api.PrepareActionRequestBody) (*NetworkPulse, error) {
+	if !config.Config.NetworkPulse {
+		return nil, nil
+	}
+	pulse := NetworkPulse{
+		On:     time.Duration(extutil.ToInt64(request.Config["pulseOn"])) * time.Millisecond,
+		Off:    time.Duration(extutil.ToInt64(request.Config["pulseOff"])) * time.Millisecond,
+		Jitter: extutil.ToUInt(request.Config["pulseJitter"]),
+	}
+	if pulse.On == 0 && pulse.Off == 0 {
+		return nil, nil
+	}
+	if pulse.On < minPulseDuration || pulse.Off < minPulseDuration {
+		return nil, fmt.Errorf("pulse on and off durations must both be at least %s", minPulseDuration)
+	}
+	if pulse.Jitter > 100 {
+		return nil, fmt.Errorf("pulse jitter must not exceed 100%%")
+	}
+	return &pulse, nil
+}
+
+func (p NetworkPulse) vary(d time.Duration) time.Duration {
+	if p.Jitter == 0 {
+		return d
+	}
+	delta := float64(d) * float64(p.Jitter) / 100
+	return max(d+time.Duration((rand.Float64()*2-1)*delta), minPulseDuration)
+}
+
+// pulse reverts or reapplies the fault once its period is over.
+func (a *networkAction) pulse(ctx context.Context, state *NetworkActionState) (*action_kit_api.Message, error) {
+	now := time.Now()
+	if now.Before(state.NextTransition) {
+		return nil, nil
+	}
+
+	opts, err := a.optsDecoder(state.NetworkOpts)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to deserialize network settings.", err)
+	}
+	r := runner(a.ociRuntime, state.Sidecar)
+
+	if state.Paused {
+		// journal before applying, like Start
+		state.Paused = false
+		period := state.Pulse.vary(state.Pulse.On)
+		state.NextTransition = now.Add(period)
+		recordExecution(state.ExecutionId, a.description.Id, state)
+		snap, err := applyNetworkFault(ctx, r, opts)
+		state.QdiscSnapshot = snap
+		recordExecution(state.ExecutionId, a.description.Id, state)
+		if err != nil {
+			return nil, extension_kit.ToError("Failed to apply network settings.", err)
+		}
+		return &action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Info),
+			Message: fmt.Sprintf("Pulse on for %s: %s", period.Round(time.Second), opts.String()),
+		}, nil
+	}
+
+	if err := revertNetworkFault(ctx, r, opts, state.QdiscSnapshot); err != nil {
+		return nil, extension_kit.ToError("Failed to revert network settings.", err)
+	}
+	period := state.Pulse.vary(state.Pulse.Off)
+	state.Paused = true
+	state.NextTransition = now.Add(period)
+	recordExecution(state.ExecutionId, a.description.Id, state)
+	return &action_kit_api.Message{
+		Level:   extutil.Ptr(action_kit_api.Info),
+		Message: fmt.Sprintf("Pulse off for %s", period.Round(time.Second)),
+	}, nil
+}
+
+func pulseMetrics(state *NetworkActionState) []action_kit_api.Metric {
+	value := 1.0
+	if state.Paused {
+		value = 0
+	}
+	return []action_kit_api.Metric{
+		{
+			Name:      new("network_fault"),
+			Metric:    map[string]string{"network_fault": "Applied"},
+			Value:     value,
+			Timestamp: time.Now(),
+		},
+	}
+}
@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/extension-host/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkPulse(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.NetworkPulse = true
+	tests := []struct {
+		name    string
+		config  map[string]any
+		want    *NetworkPulse
+		wantErr bool
+	}{
+		{name: "not set", config: map[string]any{}},
+		{name: "on and off", config: map[string]any{"pulseOn": 5000, "pulseOff": 10000, "pulseJitter": 20}, want: &NetworkPulse{On: 5 * time.Second, Off: 10 * time.Second, Jitter: 20}},
+		{name: "only on", config: map[string]any{"pulseOn": 5000}, wantErr: true},
+		{name: "too short", config: map[string]any{"pulseOn": 500, "pulseOff": 5000}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pulse, err := networkPulse(action_kit_api.PrepareActionRequestBody{Config: tt.config})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, pulse)
+		})
+	}
+}
+
+func TestNetworkPulseDisabled(t *testing.T) {
+	pulse, err := networkPulse(action_kit_api.PrepareActionRequestBody{Config: map[string]any{"pulseOn": 5000, "pulseOff": 10000}})
+	require.NoError(t, err)
+	assert.Nil(t, pulse)
+}
+
+func TestWithNetworkPulse(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	description := getNetworkDelayDescription()
+
+	plain := withNetworkPulse(description, false)
+	assert.Nil(t, plain.Status)
+	assert.Nil(t, plain.Widgets)
+	assert.Len(t, plain.Parameters, len(description.Parameters))
+
+	assert.NotNil(t, withNetworkPulse(description, true).Status)
+
+	config.Config.NetworkPulse = true
+	pulsed := withNetworkPulse(description, false)
+	assert.NotNil(t, pulsed.Status)
+	require.NotNil(t, pulsed.Widgets)
+	assert.Len(t, *pulsed.Widgets, 1)
+	assert.Len(t, pulsed.Parameters, len(description.Parameters)+len(networkPulseParameters))
+}
+
+func TestNetworkPulseVary(t *testing.T) {
+	pulse := NetworkPulse{Jitter: 50}
+	for range 100 {
+		d := pulse.vary(10 * time.Second)
+		assert.GreaterOrEqual(t, d, 5*time.Second)
+		assert.LessOrEqual(t, d, 15*time.Second)
+	}
+	assert.Equal(t, minPulseDuration, NetworkPulse{Jitter: 100}.vary(time.Second/2))
+	assert.Equal(t, 3*time.Second, NetworkPulse{}.vary(3*time.Second))
+}
+
+func TestNetworkActionStatusPulses(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+	applied, reverted := 0, 0
+	applyNetworkFault = func(_ context.Context, _ netfault.CommandRunner, _ netfault.Opts) (netfault.QdiscSnapshot, error) {
+		applied++
+		return netfault.QdiscSnapshot{}, nil
+	}
+	revertNetworkFault = func(_ context.Context, _ netfault.CommandRunner, _ netfault.Opts, _ netfault.QdiscSnapshot) error {
+		reverted++
+		return nil
+	}
+	defer func() {
+		applyNetworkFault = netfault.Apply
+		revertNetworkFault = netfault.Revert
+	}()
+
+	opts, _ := json.Marshal(&netfault.DelayOpts{Delay: 100 * time.Millisecond})
+	action := &networkAction{optsDecoder: delayDecode, description: getNetworkDelayDescription()}
+	state := NetworkActionState{
+		ExecutionId:    uuid.New(),
+		NetworkOpts:    opts,
+		Pulse:          &NetworkPulse{On: time.Second, Off: time.Hour},
+		NextTransition: time.Now().Add(-time.Millisecond),
+	}
+
+	result, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.False(t, result.Completed)
+	require.NotNil(t, result.Messages)
+	assert.True(t, state.Paused)
+	assert.Equal(t, 1, reverted)
+	require.NotNil(t, result.Metrics)
+	assert.Equal(t, 0.0, (*result.Metrics)[0].Value)
+
+	result, err = action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Nil(t, result.Messages)
+	assert.Equal(t, 1, reverted)
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reverted, "paused fault must not be reverted twice")
+	assert.Equal(t, 0, applied)
+}
+
+func TestNetworkActionStatusPulseReapplies(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+	applied, reverted := 0, 0
+	applyNetworkFault = func(_ context.Context, _ netfault.CommandRunner, _ netfault.Opts) (netfault.QdiscSnapshot, error) {
+		applied++
+		return netfault.QdiscSnapshot{}, nil
+	}
+	revertNetworkFault = func(_ context.Context, _ netfault.CommandRunner, _ netfault.Opts, _ netfault.QdiscSnapshot) error {
+		reverted++
+		return nil
+	}
+	defer func() {
+		applyNetworkFault = netfault.Apply
+		revertNetworkFault = netfault.Revert
+	}()
+
+	opts, _ := json.Marshal(&netfault.DelayOpts{Delay: 100 * time.Millisecond})
+	action := &networkAction{optsDecoder: delayDecode, description: getNetworkDelayDescription()}
+	state := NetworkActionState{
+		ExecutionId:    uuid.New(),
+		NetworkOpts:    opts,
+		Pulse:          &NetworkPulse{On: time.Hour, Off: time.Second},
+		Paused:         true,
+		NextTransition: time.Now().Add(-time.Millisecond),
+	}
+
+	result, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	require.NotNil(t, result.Messages)
+	assert.False(t, state.Paused)
+	assert.Equal(t, 1, applied)
+	assert.Equal(t, 1.0, (*result.Metrics)[0].Value)
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reverted)
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+func NewNetworkReorderPackagesContainerAction(r ociruntime.OciRuntime) action_kit_sdk.Action[NetworkActionState] {
+	return journaled[NetworkActionState](&networkAction{
+		ociRuntime:   r,
+		optsProvider: reorderPackages(r),
+		optsDecoder:  reorderPackagesDecode,
+		description:  getNetworkReorderPackagesDescription(),
+	})
+}
+
+func getNetworkReorderPackagesDescription() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          fmt.Sprintf("%s.network_package_reorder", BaseActionID),
+		Label:       "Reorder Packets",
+		Description: "Reorder egress network packets by delaying all but a share of them.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(delayIcon),
+		TargetSelection: &action_kit_api.TargetSelection{
+			TargetType:         requireCapability(capabilityTc, capabilityDig),
+			SelectionTemplates: &targetSelectionTemplates,
+		},
+		Technology:  new("Linux Host"),
+		Category:    new("Network"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Parameters: slices.Concat(
+			commonNetworkParameters,
+			[]action_kit_api.ActionParameter{
+				{
+					Name:         "networkReorder",
+					Label:        "Packet Reordering",
+					Description:  new("How much of the traffic should be sent immediately, overtaking the delayed packets?"),
+					Type:         action_kit_api.ActionParameterTypePercentage,
+					DefaultValue: new("25"),
+					Required:     new(true),
+					MinValue:     new(0),
+					MaxValue:     new(100),
+					Order:        new(1),
+				},
+				{
+					Name:         "networkCorrelation",
+					Label:        "Correlation",
+					Description:  new("How much does the decision to reorder a packet depend on the previous one? Higher values cause bursts."),
+					Type:         action_kit_api.ActionParameterTypePercentage,
+					DefaultValue: new("50"),
+					Required:     new(true),
+					MinValue:     new(0),
+					MaxValue:     new(100),
+					Advanced:     new(true),
+					Order:        new(2),
+				},
+				{
+					Name:         "networkDelay",
+					Label:        "Network Delay",
+					Description:  new("How much should the packets which aren't reordered be delayed? Reordering requires a delay larger than the gap between the packets."),
+					Type:         action_kit_api.ActionParameterTypeDuration,
+					DefaultValue: new("10ms"),
+					MinValue:     new(1),
+					MaxValue:     new(4294967), //1 hour (less then tc limit - 4294967295 usecs)
+					Required:     new(true),
+					Order:        new(3),
+				},
+				{
+					Name:        "networkInterface",
+					Label:       "Network Interface",
+					Description: new("Target Network Interface which should be affected. All if none specified."),
+					Type:        action_kit_api.ActionParameterTypeStringArray,
+					Required:    new(false),
+					Advanced:    new(true),
+					Order:       new(106),
+				},
+			},
+			networkProcessParameters,
+		),
+	}
+}
+
+func reorderPackages(r ociruntime.OciRuntime) networkOptsProvider {
+	return func(ctx context.Context, sidecar netfault.SidecarOpts, request action_kit_api.PrepareActionRequestBody) (netfault.Opts, action_kit_api.Messages, error) {
+		_, err := CheckTargetHostname(request.Target.Attributes)
+		if err != nil {
+			return nil, nil, err
+		}
+		reorder := extutil.ToUInt(request.Config["networkReorder"])
+		correlation := extutil.ToUInt(request.Config["networkCorrelation"])
+		delay := time.Duration(extutil.ToInt64(request.Config["networkDelay"])) * time.Millisecond
+		if delay <= 0 {
+			return nil, nil, fmt.Errorf("reordering packets requires a delay")
+		}
+
+		filter, messages, err := mapToNetworkFilter(ctx, r, sidecar, request.Config, getRestrictedEndpoints(request))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		interfaces := extutil.ToStringArray(request.Config["networkInterface"])
+		if len(interfaces) == 0 {
+			interfaces, err = netfault.ListNonLoopbackInterfaceNames(ctx, runner(r, sidecar))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if len(interfaces) == 0 {
+			return nil, nil, fmt.Errorf("no network interfaces specified")
+		}
+
+		return &netfault.ReorderPackagesOpts{
+			Filter:           filter,
+			ExecutionContext: mapToExecutionContext(request),
+			Reorder:          reorder,
+			Correlation:      correlation,
+			Delay:            delay,
+			Interfaces:       interfaces,
+		}, messages, nil
+	}
+}
+
+func reorderPackagesDecode(data json.RawMessage) (netfault.Opts, error) {
+	var opts netfault.ReorderPackagesOpts
+	err := json.Unmarshal(data, &opts)
+	return &opts, err
+}
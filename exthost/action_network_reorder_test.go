@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+	"github.com/steadybit/extension-host/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reorderRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestReorderPackages(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+	osHostname = func() (string, error) { return "myhostname", nil }
+
+	request := reorderRequest(map[string]any{"duration": 10000, "networkReorder": 25, "networkCorrelation": 50, "networkDelay": 20, "networkInterface": []any{"eth0"}})
+	opts, _, err := reorderPackages(nil)(context.Background(), netfault.SidecarOpts{}, request)
+	require.NoError(t, err)
+
+	reorderOpts := opts.(*netfault.ReorderPackagesOpts)
+	assert.Equal(t, uint(25), reorderOpts.Reorder)
+	assert.Equal(t, uint(50), reorderOpts.Correlation)
+	assert.Equal(t, 20*time.Millisecond, reorderOpts.Delay)
+	assert.Equal(t, []string{"eth0"}, reorderOpts.Interfaces)
+}
+
+func TestReorderPackagesRequiresDelay(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+	osHostname = func() (string, error) { return "myhostname", nil }
+
+	request := reorderRequest(map[string]any{"duration": 10000, "networkReorder": 25, "networkCorrelation": 50, "networkDelay": 0, "networkInterface": []any{"eth0"}})
+	_, _, err := reorderPackages(nil)(context.Background(), netfault.SidecarOpts{}, request)
+	assert.ErrorContains(t, err, "reordering packets requires a delay")
+}
+
+func TestReorderPackagesDecode(t *testing.T) {
+	opts, err := reorderPackagesDecode([]byte(`{"Reorder":25,"Correlation":50,"Delay":20000000,"Interfaces":["eth0"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, &netfault.ReorderPackagesOpts{Reorder: 25, Correlation: 50, Delay: 20 * time.Millisecond, Interfaces: []string{"eth0"}}, opts)
+}
@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-host/exthost/route"
+	extension_kit "github.com/steadybit/extension-kit"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// hostNetworkNamespace is entered to change the routes, the extension may run in a namespace of its own
+const hostNetworkNamespace = "/proc/1/ns/net"
+
+type routeAction struct{}
+
+type RouteActionState struct {
+	ExecutionId uuid.UUID
+	RouteOpts   route.Opts
+	// Restricted are the networks of the restricted endpoints, which are kept reachable
+	Restricted []net.IPNet
+	// Snapshot holds the routes removed and added, to restore them on Stop
+	Snapshot route.Snapshot
+	Applied  bool
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[RouteActionState]         = (*routeAction)(nil)
+	_ action_kit_sdk.ActionWithStop[RouteActionState] = (*routeAction)(nil)
+)
+
+var routeActionID = fmt.Sprintf("%s.network_route", BaseActionID)
+
+var (
+	planRoutes    = route.Plan
+	applyRoutes   = route.Apply
+	restoreRoutes = route.Restore
+)
+
+func NewNetworkRouteAction() action_kit_sdk.Action[RouteActionState] {
+	return journaled[RouteActionState](&routeAction{})
+}
+
+func (a *routeAction) NewEmptyState() RouteActionState {
+	return RouteActionState{}
+}
+
+func (a *routeAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          routeActionID,
+		Label:       "Manipulate Routes",
+		Description: "Installs blackhole, unreachable or prohibit routes, or removes or replaces the default route of the host. The original routes are restored when the attack ends.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(blackHoleIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         requireCapability(capabilityNetAdmin),
+			SelectionTemplates: new(targetSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("Network"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long should the routes be changed?"),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(0),
+			},
+			{
+				Name:         "mode",
+				Label:        "Mode",
+				Description:  new("*Blackhole:* Silently drop the traffic to the CIDRs.\n\n*Unreachable:* Reject the traffic to the CIDRs with 'network unreachable'.\n\n*Prohibit:* Reject the traffic to the CIDRs with 'administratively prohibited'.\n\n*Remove Default Route:* Remove the default routes.\n\n*Replace Default Route:* Route the traffic via another gateway."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(string(route.ModeBlackhole)),
+				Required:     new(true),
+				Order:        new(1),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "Blackhole", Value: string(route.ModeBlackhole)},
+					action_kit_api.ExplicitParameterOption{Label: "Unreachable", Value: string(route.ModeUnreachable)},
+					action_kit_api.ExplicitParameterOption{Label: "Prohibit", Value: string(route.ModeProhibit)},
+					action_kit_api.ExplicitParameterOption{Label: "Remove Default Route", Value: string(route.ModeRemoveDefault)},
+					action_kit_api.ExplicitParameterOption{Label: "Replace Default Route", Value: string(route.ModeReplaceDefault)},
+				}),
+			},
+			{
+				Name:         "ip",
+				Label:        "IPs/CIDRs",
+				Description:  new("The IP addresses or blocks to route. Only used for the blackhole, unreachable and prohibit modes."),
+				Type:         action_kit_api.ActionParameterTypeStringArray,
+				DefaultValue: new(""),
+				Order:        new(2),
+			},
+			{
+				Name:        "gateway",
+				Label:       "Gateway",
+				Description: new("The gateway to route the traffic via instead. Only used for replacing the default route, which is replaced for the IP version of the gateway."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(false),
+				Order:       new(3),
+			},
+		},
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (a *routeAction) Prepare(_ context.Context, state *RouteActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	opts := route.Opts{Mode: route.Mode(extutil.ToString(request.Config["mode"]))}
+	cidrs, err := parseRouteCidrs(extutil.ToStringArray(request.Config["ip"]))
+	if err != nil {
+		return nil, extension_kit.WrapError(err)
+	}
+	switch opts.Mode {
+	case route.ModeBlackhole, route.ModeUnreachable, route.ModeProhibit:
+		opts.Cidrs = cidrs
+	case route.ModeReplaceDefault:
+		if gateway := extutil.ToString(request.Config["gateway"]); gateway != "" {
+			if opts.Gateway = net.ParseIP(gateway); opts.Gateway == nil {
+				return nil, extension_kit.WrapError(fmt.Errorf("invalid gateway '%s'", gateway))
+			}
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid route settings: %s", err),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	restricted, err := parseRouteCidrs(restrictedCidrs(getRestrictedEndpoints(request)))
+	if err != nil {
+		return nil, extension_kit.WrapError(err)
+	}
+
+	// fail early, e.g. if a restricted endpoint would be affected. The snapshot is taken on start.
+	if _, err := planRoutes(hostNetworkNamespace, opts, restricted); err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Cannot change routes: %s", err),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	state.ExecutionId = request.ExecutionId
+	state.RouteOpts = opts
+	state.Restricted = restricted
+	return nil, nil
+}
+
+func (a *routeAction) Start(_ context.Context, state *RouteActionState) (*action_kit_api.StartResult, error) {
+	snapshot, err := planRoutes(hostNetworkNamespace, state.RouteOpts, state.Restricted)
+	if err != nil {
+		return nil, extension_kit.ToError("Failed to snapshot the routes.", err)
+	}
+
+	state.Snapshot = snapshot
+	state.Applied = true
+	recordExecution(state.ExecutionId, routeActionID, state)
+
+	if err := applyRoutes(hostNetworkNamespace, snapshot); err != nil {
+		if restoreErr := restoreRoutes(hostNetworkNamespace, snapshot); restoreErr != nil {
+			return nil, extension_kit.ToError("Failed to change the routes and to restore them.", errors.Join(err, restoreErr))
+		}
+		state.Applied = false
+		forgetExecution(state.ExecutionId)
+		return nil, extension_kit.ToError("Failed to change the routes.", err)
+	}
+
+	var messages []action_kit_api.Message
+	for _, r := range snapshot.Removed {
+		messages = append(messages, action_kit_api.Message{Level: extutil.Ptr(action_kit_api.Info), Message: fmt.Sprintf("Removed route %s", r)})
+	}
+	for _, r := range snapshot.Added {
+		messages = append(messages, action_kit_api.Message{Level: extutil.Ptr(action_kit_api.Info), Message: fmt.Sprintf("Added route %s", r)})
+	}
+	for _, r := range snapshot.Pinned {
+		messages = append(messages, action_kit_api.Message{Level: extutil.Ptr(action_kit_api.Info), Message: fmt.Sprintf("Pinned route %s to keep the restricted endpoint reachable", r)})
+	}
+	return &action_kit_api.StartResult{Messages: &messages}, nil
+}
+
+func (a *routeAction) Stop(_ context.Context, state *RouteActionState) (*action_kit_api.StopResult, error) {
+	if !state.Applied {
+		return nil, nil
+	}
+
+	if err := restoreRoutes(hostNetworkNamespace, state.Snapshot); err != nil {
+		return nil, extension_kit.ToError("Failed to restore the routes.", err)
+	}
+	state.Applied = false
+	forgetExecution(state.ExecutionId)
+
+	return &action_kit_api.StopResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Restored %d routes", len(state.Snapshot.Removed)),
+			},
+		}),
+	}, nil
+}
+
+func restrictedCidrs(restrictedEndpoints []action_kit_api.RestrictedEndpoint) []string {
+	cidrs := make([]string, 0, len(restrictedEndpoints))
+	for _, endpoint := range restrictedEndpoints {
+		cidrs = append(cidrs, endpoint.Cidr)
+	}
+	return cidrs
+}
+
+// parseRouteCidrs parses IP addresses and CIDRs, duplicates are kept.
+func parseRouteCidrs(values []string) ([]net.IPNet, error) {
+	var cidrs []net.IPNet
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address '%s'", value)
+			}
+			if ip4 := ip.To4(); ip4 != nil {
+				cidrs = append(cidrs, net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)})
+			} else {
+				cidrs = append(cidrs, net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)})
+			}
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR '%s': %w", value, err)
+		}
+		cidrs = append(cidrs, *cidr)
+	}
+	return cidrs, nil
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-host/exthost/route"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRoutes struct {
+	planned  []route.Opts
+	applied  []route.Snapshot
+	restored []route.Snapshot
+	snapshot route.Snapshot
+	applyErr error
+}
+
+func (f *fakeRoutes) install(t *testing.T) {
+	planRoutes = func(_ string, opts route.Opts, _ []net.IPNet) (route.Snapshot, error) {
+		f.planned = append(f.planned, opts)
+		return f.snapshot, nil
+	}
+	applyRoutes = func(_ string, snapshot route.Snapshot) error {
+		f.applied = append(f.applied, snapshot)
+		return f.applyErr
+	}
+	restoreRoutes = func(_ string, snapshot route.Snapshot) error {
+		f.restored = append(f.restored, snapshot)
+		return nil
+	}
+	t.Cleanup(func() {
+		planRoutes = route.Plan
+		applyRoutes = route.Apply
+		restoreRoutes = route.Restore
+	})
+}
+
+func blackholeSnapshot() route.Snapshot {
+	_, dst, _ := net.ParseCIDR("10.0.0.0/24")
+	return route.Snapshot{Added: []route.Route{{Family: 2, Type: 6, Dst: *dst}}}
+}
+
+func routeRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestActionRoute(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeRoutes{snapshot: blackholeSnapshot()}
+	fake.install(t)
+	action := &routeAction{}
+
+	state := action.NewEmptyState()
+	request := routeRequest(map[string]any{"duration": "10000", "mode": "blackhole", "ip": []any{"10.0.0.0/24", "10.0.1.1"}})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	require.Len(t, state.RouteOpts.Cidrs, 2)
+	assert.Equal(t, "10.0.0.0/24", state.RouteOpts.Cidrs[0].String())
+	assert.Equal(t, "10.0.1.1/32", state.RouteOpts.Cidrs[1].String())
+
+	start, err := action.Start(context.Background(), &state)
+	require.NoError(t, err)
+	assert.True(t, state.Applied)
+	assert.Equal(t, fake.snapshot, state.Snapshot)
+	require.Len(t, fake.applied, 1)
+	assert.Equal(t, "Added route blackhole 10.0.0.0/24", (*start.Messages)[0].Message)
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.False(t, state.Applied)
+	assert.Equal(t, []route.Snapshot{fake.snapshot}, fake.restored)
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Len(t, fake.restored, 1)
+}
+
+func TestActionRoute_PrepareRejectsInvalidSettings(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeRoutes{}
+	fake.install(t)
+	action := &routeAction{}
+
+	state := action.NewEmptyState()
+	request := routeRequest(map[string]any{"duration": "10000", "mode": "blackhole"})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Contains(t, result.Error.Title, "Invalid route settings")
+	assert.Empty(t, fake.planned)
+
+	request = routeRequest(map[string]any{"duration": "10000", "mode": "blackhole", "ip": []any{"example.com"}})
+	_, err = action.Prepare(context.Background(), &state, request)
+	assert.ErrorContains(t, err, "invalid IP address 'example.com'")
+}
+
+func TestActionRoute_StartRestoresOnFailure(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeRoutes{snapshot: blackholeSnapshot(), applyErr: errors.New("operation not permitted")}
+	fake.install(t)
+	action := &routeAction{}
+
+	state := action.NewEmptyState()
+	request := routeRequest(map[string]any{"duration": "10000", "mode": "prohibit", "ip": []any{"10.0.0.0/24"}})
+	_, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+
+	_, err = action.Start(context.Background(), &state)
+	assert.ErrorContains(t, err, "Failed to change the routes.")
+	assert.False(t, state.Applied)
+	assert.Equal(t, []route.Snapshot{fake.snapshot}, fake.restored)
+}
@@ -81,3 +81,76 @@ func TestMapToNetworkFilterExcludeIp(t *testing.T) {
 		})
 	}
 }
+
+func TestParseProtocolAndDirection(t *testing.T) {
+	tests := []struct {
+		name          string
+		actionConfig  map[string]any
+		wantProtocol  netfault.IpProto
+		wantDirection netfault.Direction
+		wantErr       bool
+	}{
+		{
+			name:         "defaults to all protocols and the action's direction",
+			actionConfig: map[string]any{},
+		},
+		{
+			name:          "inbound udp on a port",
+			actionConfig:  map[string]any{"protocol": "UDP", "direction": "ingress", "port": []any{"5353"}},
+			wantProtocol:  netfault.IpProtoUdp,
+			wantDirection: netfault.DirectionIngress,
+		},
+		{
+			name:          "outbound icmp",
+			actionConfig:  map[string]any{"protocol": "icmp", "direction": "egress"},
+			wantProtocol:  netfault.IpProtoIcmp,
+			wantDirection: netfault.DirectionEgress,
+		},
+		{
+			name:         "icmp ignores empty ports",
+			actionConfig: map[string]any{"protocol": "icmp", "port": []any{""}},
+			wantProtocol: netfault.IpProtoIcmp,
+		},
+		{
+			name:         "icmp has no ports",
+			actionConfig: map[string]any{"protocol": "icmp", "port": []any{"80"}},
+			wantErr:      true,
+		},
+		{
+			name:         "unknown protocol",
+			actionConfig: map[string]any{"protocol": "sctp"},
+			wantErr:      true,
+		},
+		{
+			name:         "unknown direction",
+			actionConfig: map[string]any{"direction": "sideways"},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			protocol, direction, err := parseProtocolAndDirection(tt.actionConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProtocol, protocol)
+			assert.Equal(t, tt.wantDirection, direction)
+		})
+	}
+}
+
+func TestMapToNetworkFilterProtocolAndDirection(t *testing.T) {
+	defer func(c config.Specification) { config.Config = c }(config.Config)
+	config.Config.DisableRunc = true
+
+	filter, _, err := mapToNetworkFilter(context.Background(), nil, netfault.SidecarOpts{}, map[string]any{"protocol": "udp", "direction": "ingress", "port": []any{"53"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, netfault.IpProtoUdp, filter.Protocol)
+	assert.Equal(t, netfault.DirectionIngress, filter.Direction)
+
+	_, _, err = mapToNetworkFilter(context.Background(), nil, netfault.SidecarOpts{}, map[string]any{"direction": "sideways"}, nil)
+	assert.ErrorContains(t, err, "unsupported direction")
+}
@@ -0,0 +1,301 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+type rlimitProcessAction struct {
+}
+
+type RlimitProcessActionState struct {
+	ExecutionId   uuid.UUID
+	ProcessFilter string
+	MatchMode     string
+	// Limits maps the resource names to the soft limits to apply
+	Limits map[string]uint64
+	// LowerHard also lowers the hard limit, so the processes can't raise the soft limit again
+	LowerHard bool
+	Processes []LimitedProcess
+	Applied   bool
+}
+
+type LimitedProcess struct {
+	Pid       int
+	Name      string
+	StartedAt time.Time
+	// Original holds the limits restored on Stop
+	Original map[string]stopprocess.Rlimit
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[RlimitProcessActionState]         = (*rlimitProcessAction)(nil)
+	_ action_kit_sdk.ActionWithStop[RlimitProcessActionState] = (*rlimitProcessAction)(nil)
+)
+
+func NewRlimitProcessAction() action_kit_sdk.Action[RlimitProcessActionState] {
+	return journaled[RlimitProcessActionState](&rlimitProcessAction{})
+}
+
+func (a *rlimitProcessAction) NewEmptyState() RlimitProcessActionState {
+	return RlimitProcessActionState{}
+}
+
+func (a *rlimitProcessAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          rlimitProcessActionID,
+		Label:       "Lower Process Limits",
+		Description: "Lowers the resource limits (open files, processes, address space) of the targeted processes at runtime for the given duration.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(stopProcessIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         targetID,
+			SelectionTemplates: new(targetSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("Resource"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long should the limits be lowered?"),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(0),
+			},
+			{
+				Name:        "process",
+				Label:       "Process",
+				Description: new("PID or string to match the process name or command. How it is matched is defined by 'Match by'."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(true),
+				Order:       new(1),
+			},
+			processMatchModeParameter(new(2)),
+			{
+				Name:         "nofile",
+				Label:        "Max open files",
+				Description:  new("Lowers RLIMIT_NOFILE to this number of file descriptors. 0 leaves it unchanged."),
+				Type:         action_kit_api.ActionParameterTypeInteger,
+				DefaultValue: new("0"),
+				Order:        new(3),
+			},
+			{
+				Name:         "nproc",
+				Label:        "Max processes",
+				Description:  new("Lowers RLIMIT_NPROC, the number of processes and threads of the user of the process. 0 leaves it unchanged."),
+				Type:         action_kit_api.ActionParameterTypeInteger,
+				DefaultValue: new("0"),
+				Order:        new(4),
+			},
+			{
+				Name:         "as",
+				Label:        "Max address space (MB)",
+				Description:  new("Lowers RLIMIT_AS, the virtual memory of the process. 0 leaves it unchanged."),
+				Type:         action_kit_api.ActionParameterTypeInteger,
+				DefaultValue: new("0"),
+				Order:        new(5),
+			},
+			{
+				Name:         "lowerHard",
+				Label:        "Lower hard limit",
+				Description:  new("Also lower the hard limit, so the processes can't raise their limits again."),
+				Type:         action_kit_api.ActionParameterTypeBoolean,
+				DefaultValue: new("false"),
+				Advanced:     new(true),
+				Order:        new(6),
+			},
+		},
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (a *rlimitProcessAction) Prepare(_ context.Context, state *RlimitProcessActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	selector, err := stopprocess.NewSelector(extutil.ToString(request.Config["matchMode"]), extutil.ToString(request.Config["process"]))
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid process selection: %s", err),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	limits := map[string]uint64{}
+	if nofile := extutil.ToInt64(request.Config["nofile"]); nofile > 0 {
+		limits[stopprocess.RlimitNofile] = uint64(nofile)
+	}
+	if nproc := extutil.ToInt64(request.Config["nproc"]); nproc > 0 {
+		limits[stopprocess.RlimitNproc] = uint64(nproc)
+	}
+	if as := extutil.ToInt64(request.Config["as"]); as > 0 {
+		limits[stopprocess.RlimitAs] = uint64(as) * 1024 * 1024
+	}
+	if len(limits) == 0 {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  "At least one limit is required",
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	state.ExecutionId = request.ExecutionId
+	state.ProcessFilter = selector.Value
+	state.MatchMode = selector.Mode
+	state.Limits = limits
+	state.LowerHard = extutil.ToBool(request.Config["lowerHard"])
+	return nil, nil
+}
+
+func (a *rlimitProcessAction) Start(_ context.Context, state *RlimitProcessActionState) (*action_kit_api.StartResult, error) {
+	selector, err := stopprocess.NewSelector(state.MatchMode, state.ProcessFilter)
+	if err != nil {
+		return nil, err
+	}
+	processes, err := findProcesses(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Processes = make([]LimitedProcess, 0, len(processes))
+	for _, p := range processes {
+		original := map[string]stopprocess.Rlimit{}
+		for resource := range state.Limits {
+			limit, err := getRlimit(p.Pid, resource)
+			if errors.Is(err, syscall.ESRCH) {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			original[resource] = limit
+		}
+		if len(original) == len(state.Limits) {
+			state.Processes = append(state.Processes, LimitedProcess{Pid: p.Pid, Name: p.Name, StartedAt: p.StartedAt, Original: original})
+		}
+	}
+	if len(state.Processes) == 0 {
+		return &action_kit_api.StartResult{
+			Messages: new([]action_kit_api.Message{
+				{
+					Level:   extutil.Ptr(action_kit_api.Warn),
+					Message: fmt.Sprintf("No processes matching %s (%s) found", state.ProcessFilter, state.MatchMode),
+				},
+			}),
+		}, nil
+	}
+
+	// journal before lowering, so a restarted extension restores them in any case
+	state.Applied = true
+	recordExecution(state.ExecutionId, rlimitProcessActionID, state)
+
+	if err := a.lower(state); err != nil {
+		if restoreErr := a.restore(state); restoreErr != nil {
+			return nil, errors.Join(err, restoreErr)
+		}
+		state.Applied = false
+		forgetExecution(state.ExecutionId)
+		return nil, err
+	}
+
+	limits := make([]string, 0, len(state.Limits))
+	for _, resource := range slices.Sorted(maps.Keys(state.Limits)) {
+		limits = append(limits, fmt.Sprintf("%s=%d", resource, state.Limits[resource]))
+	}
+	names := make([]string, 0, len(state.Processes))
+	for _, p := range state.Processes {
+		names = append(names, fmt.Sprintf("%s (%d)", p.Name, p.Pid))
+	}
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Lowered %s of %d processes: %s", strings.Join(limits, ", "), len(names), strings.Join(names, ", ")),
+			},
+		}),
+	}, nil
+}
+
+func (a *rlimitProcessAction) Stop(_ context.Context, state *RlimitProcessActionState) (*action_kit_api.StopResult, error) {
+	if !state.Applied {
+		return nil, nil
+	}
+
+	if err := a.restore(state); err != nil {
+		return nil, err
+	}
+	state.Applied = false
+	forgetExecution(state.ExecutionId)
+
+	return &action_kit_api.StopResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Restored the limits of %d processes", len(state.Processes)),
+			},
+		}),
+	}, nil
+}
+
+// lower never raises a limit: a soft limit below the requested one is kept.
+func (a *rlimitProcessAction) lower(state *RlimitProcessActionState) error {
+	var errs error
+	for _, p := range state.Processes {
+		for _, resource := range slices.Sorted(maps.Keys(state.Limits)) {
+			original := p.Original[resource]
+			limit := stopprocess.Rlimit{Soft: min(state.Limits[resource], original.Soft), Hard: original.Hard}
+			if state.LowerHard {
+				limit.Hard = limit.Soft
+			}
+			if err := setRlimit(p.Pid, resource, limit); err != nil && !errors.Is(err, syscall.ESRCH) {
+				errs = errors.Join(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+// restore writes back the original limits of all processes still running.
+func (a *rlimitProcessAction) restore(state *RlimitProcessActionState) error {
+	var errs error
+	for _, p := range state.Processes {
+		if !isSameProcess(p.Pid, p.StartedAt) {
+			continue
+		}
+		for _, resource := range slices.Sorted(maps.Keys(p.Original)) {
+			if err := setRlimit(p.Pid, resource, p.Original[resource]); err != nil && !errors.Is(err, syscall.ESRCH) {
+				errs = errors.Join(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+var (
+	getRlimit = stopprocess.GetRlimit
+	setRlimit = stopprocess.SetRlimit
+)
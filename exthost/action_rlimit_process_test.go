@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRlimits struct {
+	limits map[string]stopprocess.Rlimit
+}
+
+func (f *fakeRlimits) install(t *testing.T, processes []stopprocess.Process) {
+	findProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) { return processes, nil }
+	getRlimit = func(pid int, resource string) (stopprocess.Rlimit, error) {
+		limit, ok := f.limits[fmt.Sprintf("%d %s", pid, resource)]
+		if !ok {
+			return stopprocess.Rlimit{}, fmt.Errorf("failed to read %s limit of %d: %w", resource, pid, syscall.ESRCH)
+		}
+		return limit, nil
+	}
+	setRlimit = func(pid int, resource string, limit stopprocess.Rlimit) error {
+		f.limits[fmt.Sprintf("%d %s", pid, resource)] = limit
+		return nil
+	}
+	isSameProcess = func(int, time.Time) bool { return true }
+	t.Cleanup(func() {
+		findProcesses = stopprocess.FindProcesses
+		getRlimit = stopprocess.GetRlimit
+		setRlimit = stopprocess.SetRlimit
+		isSameProcess = stopprocess.IsSameProcess
+	})
+}
+
+func rlimitProcessRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestActionRlimitProcess(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeRlimits{limits: map[string]stopprocess.Rlimit{
+		"10 nofile": {Soft: 65536, Hard: 524288},
+		"10 as":     {Soft: stopprocess.RlimitInfinity, Hard: stopprocess.RlimitInfinity},
+		"11 nofile": {Soft: 32, Hard: 4096},
+		"11 as":     {Soft: stopprocess.RlimitInfinity, Hard: stopprocess.RlimitInfinity},
+	}}
+	// 12 exits before its limits are read
+	fake.install(t, []stopprocess.Process{{Pid: 10, Name: "nginx"}, {Pid: 11, Name: "nginx"}, {Pid: 12, Name: "nginx"}})
+
+	action := &rlimitProcessAction{}
+	state := action.NewEmptyState()
+	request := rlimitProcessRequest(map[string]any{"duration": "10000", "process": "nginx", "matchMode": "name", "nofile": 64, "as": 512})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	assert.Equal(t, map[string]uint64{"nofile": 64, "as": 512 * 1024 * 1024}, state.Limits)
+
+	start, err := action.Start(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, "Lowered as=536870912, nofile=64 of 2 processes: nginx (10), nginx (11)", (*start.Messages)[0].Message)
+	assert.Equal(t, stopprocess.Rlimit{Soft: 64, Hard: 524288}, fake.limits["10 nofile"])
+	assert.Equal(t, stopprocess.Rlimit{Soft: 32, Hard: 4096}, fake.limits["11 nofile"], "a lower limit is kept")
+	assert.Equal(t, stopprocess.Rlimit{Soft: 512 * 1024 * 1024, Hard: stopprocess.RlimitInfinity}, fake.limits["11 as"])
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.False(t, state.Applied)
+	assert.Equal(t, stopprocess.Rlimit{Soft: 65536, Hard: 524288}, fake.limits["10 nofile"])
+	assert.Equal(t, stopprocess.Rlimit{Soft: stopprocess.RlimitInfinity, Hard: stopprocess.RlimitInfinity}, fake.limits["11 as"])
+}
+
+func TestActionRlimitProcess_LowerHard(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeRlimits{limits: map[string]stopprocess.Rlimit{"10 nproc": {Soft: 4096, Hard: 8192}}}
+	fake.install(t, []stopprocess.Process{{Pid: 10, Name: "worker"}})
+	isSameProcess = func(int, time.Time) bool { return false }
+
+	action := &rlimitProcessAction{}
+	state := action.NewEmptyState()
+	request := rlimitProcessRequest(map[string]any{"duration": "10000", "process": "worker", "nproc": 100, "lowerHard": true})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	_, err = action.Start(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, stopprocess.Rlimit{Soft: 100, Hard: 100}, fake.limits["10 nproc"])
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, stopprocess.Rlimit{Soft: 100, Hard: 100}, fake.limits["10 nproc"], "a reused PID must not be touched")
+}
+
+func TestActionRlimitProcess_RequiresLimit(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	action := &rlimitProcessAction{}
+	state := action.NewEmptyState()
+	request := rlimitProcessRequest(map[string]any{"duration": "10000", "process": "worker"})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "At least one limit is required", result.Error.Title)
+}
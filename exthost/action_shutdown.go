@@ -47,7 +47,7 @@ func (l *shutdownAction) Describe() action_kit_api.ActionDescription {
 		Icon:        new(shutdownIcon),
 		TargetSelection: new(action_kit_api.TargetSelection{
 			// The target type this action is for
-			TargetType: targetID,
+			TargetType: requireCapability(capabilityShutdown),
 			// You can provide a list of target templates to help the user select targets.
 			// A template can be used to pre-fill a selection
 			SelectionTemplates: &targetSelectionTemplates,
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+type signalProcessAction struct {
+}
+
+type SignalProcessActionState struct {
+	ExecutionId   uuid.UUID
+	ProcessFilter string
+	MatchMode     string
+	Signal        syscall.Signal
+	// Interval is the time between repeated signals, 0 sends it only once
+	Interval     time.Duration
+	NextSignalAt time.Time
+	Rounds       int
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[SignalProcessActionState]           = (*signalProcessAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[SignalProcessActionState] = (*signalProcessAction)(nil)
+)
+
+func NewSignalProcessAction() action_kit_sdk.Action[SignalProcessActionState] {
+	return &signalProcessAction{}
+}
+
+func (a *signalProcessAction) NewEmptyState() SignalProcessActionState {
+	return SignalProcessActionState{}
+}
+
+func (a *signalProcessAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          signalProcessActionID,
+		Label:       "Signal Processes",
+		Description: "Sends a signal to the targeted processes, e.g. SIGHUP to trigger a reload or SIGQUIT for a thread dump.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(stopProcessIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         targetID,
+			SelectionTemplates: new(targetSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("State"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("Over this period the signal is repeated."),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(0),
+			},
+			{
+				Name:        "process",
+				Label:       "Process",
+				Description: new("PID or string to match the process name or command. How it is matched is defined by 'Match by'."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(true),
+				Order:       new(1),
+			},
+			processMatchModeParameter(new(2)),
+			{
+				Name:         "signal",
+				Label:        "Signal",
+				Description:  new("The signal to send, by name or number, e.g. SIGHUP, SIGALRM or SIGRTMIN+3. SIGKILL and the signals suspending or resuming processes aren't supported, use Stop Processes or Freeze Processes instead."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new("SIGHUP"),
+				Required:     new(true),
+				Order:        new(3),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "SIGHUP (reload)", Value: "SIGHUP"},
+					action_kit_api.ExplicitParameterOption{Label: "SIGUSR1", Value: "SIGUSR1"},
+					action_kit_api.ExplicitParameterOption{Label: "SIGUSR2", Value: "SIGUSR2"},
+					action_kit_api.ExplicitParameterOption{Label: "SIGQUIT (thread dump)", Value: "SIGQUIT"},
+					action_kit_api.ExplicitParameterOption{Label: "SIGINT", Value: "SIGINT"},
+					action_kit_api.ExplicitParameterOption{Label: "SIGTERM", Value: "SIGTERM"},
+					action_kit_api.ExplicitParameterOption{Label: "SIGALRM", Value: "SIGALRM"},
+					action_kit_api.ExplicitParameterOption{Label: "SIGABRT", Value: "SIGABRT"},
+					action_kit_api.ExplicitParameterOption{Label: "SIGPIPE", Value: "SIGPIPE"},
+					action_kit_api.ExplicitParameterOption{Label: "SIGWINCH", Value: "SIGWINCH"},
+				}),
+			},
+			{
+				Name:         "interval",
+				Label:        "Repeat every",
+				Description:  new("Repeats the signal at this interval during the duration. 0 sends it once."),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("0s"),
+				Advanced:     new(true),
+				Order:        new(4),
+			},
+		},
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("1s"),
+		}),
+	}
+}
+
+func (a *signalProcessAction) Prepare(_ context.Context, state *SignalProcessActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	selector, err := stopprocess.NewSelector(extutil.ToString(request.Config["matchMode"]), extutil.ToString(request.Config["process"]))
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid process selection: %s", err),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	signal, err := stopprocess.ParseSignal(extutil.ToString(request.Config["signal"]))
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid signal: %s", err),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	state.ExecutionId = request.ExecutionId
+	state.ProcessFilter = selector.Value
+	state.MatchMode = selector.Mode
+	state.Signal = signal
+	state.Interval = time.Duration(extutil.ToInt64(request.Config["interval"])) * time.Millisecond
+	return nil, nil
+}
+
+func (a *signalProcessAction) Start(_ context.Context, state *SignalProcessActionState) (*action_kit_api.StartResult, error) {
+	messages, err := a.signal(state)
+	if err != nil {
+		return nil, err
+	}
+	return &action_kit_api.StartResult{Messages: &messages}, nil
+}
+
+func (a *signalProcessAction) Status(_ context.Context, state *SignalProcessActionState) (*action_kit_api.StatusResult, error) {
+	if state.Interval <= 0 || time.Now().Before(state.NextSignalAt) {
+		return &action_kit_api.StatusResult{Completed: false}, nil
+	}
+	messages, err := a.signal(state)
+	if err != nil {
+		return nil, err
+	}
+	return &action_kit_api.StatusResult{Completed: false, Messages: &messages}, nil
+}
+
+// signal sends the signal to all matching processes and reports the delivery per PID.
+func (a *signalProcessAction) signal(state *SignalProcessActionState) ([]action_kit_api.Message, error) {
+	selector, err := stopprocess.NewSelector(state.MatchMode, state.ProcessFilter)
+	if err != nil {
+		return nil, err
+	}
+	processes, err := findProcesses(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Rounds++
+	state.NextSignalAt = time.Now().Add(state.Interval)
+	if len(processes) == 0 {
+		return []action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Warn),
+				Message: fmt.Sprintf("No processes matching %s (%s) found", state.ProcessFilter, state.MatchMode),
+			},
+		}, nil
+	}
+
+	level := action_kit_api.Info
+	results := make([]string, 0, len(processes))
+	for _, p := range processes {
+		result := "delivered"
+		if err := sendSignal(p.Pid, state.Signal); err != nil {
+			result = err.Error()
+			level = action_kit_api.Warn
+		}
+		results = append(results, fmt.Sprintf("%s (%d): %s", p.Name, p.Pid, result))
+	}
+	return []action_kit_api.Message{
+		{
+			Level:   extutil.Ptr(level),
+			Message: fmt.Sprintf("Sent %s to %d processes: %s", stopprocess.SignalName(state.Signal), len(processes), strings.Join(results, ", ")),
+		},
+	}, nil
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signalProcessRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestActionSignalProcess(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	findProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) {
+		return []stopprocess.Process{{Pid: 812, Name: "nginx"}, {Pid: 813, Name: "nginx"}}, nil
+	}
+	var sent []string
+	sendSignal = func(pid int, signal syscall.Signal) error {
+		sent = append(sent, fmt.Sprintf("%d %d", pid, signal))
+		if pid == 813 {
+			return errors.New("operation not permitted")
+		}
+		return nil
+	}
+	defer func() {
+		findProcesses = stopprocess.FindProcesses
+		sendSignal = stopprocess.SendSignal
+	}()
+
+	action := &signalProcessAction{}
+	state := action.NewEmptyState()
+	request := signalProcessRequest(map[string]any{"duration": "10000", "process": "nginx", "matchMode": "name", "signal": "hup", "interval": "5000"})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	assert.Equal(t, syscall.SIGHUP, state.Signal)
+	assert.Equal(t, 5*time.Second, state.Interval)
+
+	start, err := action.Start(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, "Sent SIGHUP to 2 processes: nginx (812): delivered, nginx (813): operation not permitted", (*start.Messages)[0].Message)
+	assert.Equal(t, []string{"812 1", "813 1"}, sent)
+
+	// not yet due
+	status, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Nil(t, status.Messages)
+
+	state.NextSignalAt = time.Now().Add(-time.Millisecond)
+	status, err = action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	require.NotNil(t, status.Messages)
+	assert.Len(t, sent, 4)
+	assert.Equal(t, 2, state.Rounds)
+}
+
+func TestActionSignalProcess_InvalidSignal(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+
+	action := &signalProcessAction{}
+	state := action.NewEmptyState()
+	request := signalProcessRequest(map[string]any{"process": "nginx", "signal": "SIGFOO"})
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	assert.Equal(t, "Invalid signal: unknown signal 'SIGFOO'", result.Error.Title)
+}
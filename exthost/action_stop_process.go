@@ -6,6 +6,11 @@ package exthost
 import (
 	"context"
 	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,6 +26,8 @@ import (
 
 type stopProcessAction struct {
 	processStoppers sync.Map
+	// targetType is processTargetID if a single discovered process is stopped instead of the matches on a host
+	targetType string
 }
 
 type StopProcessActionState struct {
@@ -30,8 +37,23 @@ type StopProcessActionState struct {
 	Graceful      bool
 	Deadline      time.Time
 	Duration      time.Duration
+	// MatchMode defines how ProcessFilter is matched, empty for states from older versions (auto)
+	MatchMode string
+	// Selection defines which of the matching processes are stopped per interval, empty for states from
+	// older versions (all)
+	Selection  string
+	Count      int
+	Percentage int
+	// OneShot stops the processes once and observes whether they are replaced during the duration
+	OneShot bool
 }
 
+const (
+	stopProcessSelectionAll        = "all"
+	stopProcessSelectionCount      = "count"
+	stopProcessSelectionPercentage = "percentage"
+)
+
 // Make sure action implements all required interfaces
 var (
 	_ action_kit_sdk.Action[StopProcessActionState]           = (*stopProcessAction)(nil)
@@ -40,7 +62,12 @@ var (
 )
 
 func NewStopProcessAction() action_kit_sdk.Action[StopProcessActionState] {
-	return &stopProcessAction{}
+	return tracked[StopProcessActionState](&stopProcessAction{targetType: targetID})
+}
+
+// NewStopProcessTargetAction stops a process picked from the process discovery.
+func NewStopProcessTargetAction() action_kit_sdk.Action[StopProcessActionState] {
+	return tracked[StopProcessActionState](&stopProcessAction{targetType: processTargetID})
 }
 
 func (a *stopProcessAction) NewEmptyState() StopProcessActionState {
@@ -48,7 +75,7 @@ func (a *stopProcessAction) NewEmptyState() StopProcessActionState {
 }
 
 func (a *stopProcessAction) Describe() action_kit_api.ActionDescription {
-	return action_kit_api.ActionDescription{
+	description := action_kit_api.ActionDescription{
 		Id:          stopProcessActionID,
 		Label:       "Stop Processes",
 		Description: "Stops targeted processes in the given duration.",
@@ -66,11 +93,12 @@ func (a *stopProcessAction) Describe() action_kit_api.ActionDescription {
 			{
 				Name:        "process",
 				Label:       "Process",
-				Description: new("PID or string to match the process name or command."),
+				Description: new("PID or string to match the process name or command. How it is matched is defined by 'Match by'."),
 				Type:        action_kit_api.ActionParameterTypeString,
 				Required:    new(true),
 				Order:       new(1),
 			},
+			processMatchModeParameter(new(2)),
 			{
 				Name:         "graceful",
 				Label:        "Graceful",
@@ -78,7 +106,7 @@ func (a *stopProcessAction) Describe() action_kit_api.ActionDescription {
 				Type:         action_kit_api.ActionParameterTypeBoolean,
 				DefaultValue: new("true"),
 				Required:     new(true),
-				Order:        new(2),
+				Order:        new(3),
 			},
 			{
 				Name:         "duration",
@@ -87,7 +115,7 @@ func (a *stopProcessAction) Describe() action_kit_api.ActionDescription {
 				Type:         action_kit_api.ActionParameterTypeDuration,
 				DefaultValue: new("30s"),
 				Required:     new(true),
-				Order:        new(3),
+				Order:        new(4),
 			}, {
 				Name:         "delay",
 				Label:        "Delay",
@@ -98,9 +126,92 @@ func (a *stopProcessAction) Describe() action_kit_api.ActionDescription {
 				Advanced:     new(true),
 				Order:        new(1),
 			},
+			{
+				Name:         "selection",
+				Label:        "Processes to stop",
+				Description:  new("Stop all matching processes, a number of random ones or a percentage of them per interval."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(stopProcessSelectionAll),
+				Required:     new(true),
+				Advanced:     new(true),
+				Order:        new(2),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "All matches", Value: stopProcessSelectionAll},
+					action_kit_api.ExplicitParameterOption{Label: "Number of random matches", Value: stopProcessSelectionCount},
+					action_kit_api.ExplicitParameterOption{Label: "Percentage of matches", Value: stopProcessSelectionPercentage},
+				}),
+			},
+			{
+				Name:         "count",
+				Label:        "Number of processes",
+				Description:  new("How many random matches are stopped per interval, if 'Processes to stop' is set to a number."),
+				Type:         action_kit_api.ActionParameterTypeInteger,
+				DefaultValue: new("1"),
+				Advanced:     new(true),
+				Order:        new(3),
+			},
+			{
+				Name:         "percentage",
+				Label:        "Percentage of processes",
+				Description:  new("Which share of the matches is stopped per interval, if 'Processes to stop' is set to a percentage."),
+				Type:         action_kit_api.ActionParameterTypePercentage,
+				DefaultValue: new("50"),
+				MinValue:     new(1),
+				MaxValue:     new(100),
+				Advanced:     new(true),
+				Order:        new(4),
+			},
+			{
+				Name:         "oneShot",
+				Label:        "Stop once",
+				Description:  new("If true the processes are stopped only once and it is observed whether they are replaced within the duration."),
+				Type:         action_kit_api.ActionParameterTypeBoolean,
+				DefaultValue: new("false"),
+				Advanced:     new(true),
+				Order:        new(5),
+			},
 		},
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("1s"),
+		}),
 		Stop: new(action_kit_api.MutatingEndpointReference{}),
 	}
+
+	if a.targetType == processTargetID {
+		// the process is given by the target, so matching and picking processes don't apply
+		description.Id = stopProcessTargetActionID
+		description.Label = "Stop Process"
+		description.Description = "Stops the targeted process in the given duration."
+		description.TargetSelection = new(action_kit_api.TargetSelection{
+			TargetType:         processTargetID,
+			SelectionTemplates: new(processSelectionTemplates),
+		})
+		description.Parameters = slices.DeleteFunc(description.Parameters, func(p action_kit_api.ActionParameter) bool {
+			return !slices.Contains([]string{"graceful", "duration", "delay"}, p.Name)
+		})
+	}
+	return description
+}
+
+// processMatchModeParameter is shared by all actions selecting processes with a stopprocess.Selector.
+func processMatchModeParameter(order *int) action_kit_api.ActionParameter {
+	return action_kit_api.ActionParameter{
+		Name:         "matchMode",
+		Label:        "Match by",
+		Description:  new("How the processes are matched. PID 1, kernel threads, the extension and the Steadybit agent are never matched."),
+		Type:         action_kit_api.ActionParameterTypeString,
+		DefaultValue: new(stopprocess.MatchAuto),
+		Required:     new(true),
+		Order:        order,
+		Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{Label: "PID or part of the process name", Value: stopprocess.MatchAuto},
+			action_kit_api.ExplicitParameterOption{Label: "Exact process name or executable", Value: stopprocess.MatchName},
+			action_kit_api.ExplicitParameterOption{Label: "Regular expression on the command line", Value: stopprocess.MatchCmdline},
+			action_kit_api.ExplicitParameterOption{Label: "User name or uid", Value: stopprocess.MatchUser},
+			action_kit_api.ExplicitParameterOption{Label: "Systemd unit or cgroup", Value: stopprocess.MatchCgroup},
+			action_kit_api.ExplicitParameterOption{Label: "Parent PID", Value: stopprocess.MatchParent},
+		}),
+	}
 }
 
 func (a *stopProcessAction) Prepare(_ context.Context, state *StopProcessActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
@@ -109,6 +220,13 @@ func (a *stopProcessAction) Prepare(_ context.Context, state *StopProcessActionS
 		return nil, err
 	}
 	processOrPid := extutil.ToString(request.Config["process"])
+	matchMode := extutil.ToString(request.Config["matchMode"])
+	if a.targetType == processTargetID {
+		if result := checkTargetProcess(request.Target.Attributes); result != nil {
+			return result, nil
+		}
+		processOrPid, matchMode = request.Target.Attributes["process.pid"][0], stopprocess.MatchAuto
+	}
 	if processOrPid == "" {
 		return &action_kit_api.PrepareResult{
 			Error: new(action_kit_api.ActionKitError{
@@ -117,7 +235,28 @@ func (a *stopProcessAction) Prepare(_ context.Context, state *StopProcessActionS
 			}),
 		}, nil
 	}
+
+	selector, err := stopprocess.NewSelector(matchMode, processOrPid)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid process selection: %s", err),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+	if pid, ok := selector.Pid(); ok {
+		if p, err := stopprocess.ReadProcess(pid); err == nil && stopprocess.IsProtected(p) {
+			return &action_kit_api.PrepareResult{
+				Error: new(action_kit_api.ActionKitError{
+					Title:  fmt.Sprintf("Process %d (%s) is protected and can't be stopped", pid, p.Name),
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+	}
 	state.ProcessFilter = processOrPid
+	state.MatchMode = selector.Mode
 
 	parsedDuration := extutil.ToUInt64(request.Config["duration"])
 	if parsedDuration == 0 {
@@ -140,23 +279,102 @@ func (a *stopProcessAction) Prepare(_ context.Context, state *StopProcessActionS
 	}
 	state.Delay = delay
 
+	state.Selection = extutil.ToString(request.Config["selection"])
+	switch state.Selection {
+	case "", stopProcessSelectionAll:
+		state.Selection = stopProcessSelectionAll
+	case stopProcessSelectionCount:
+		state.Count = extutil.ToInt(request.Config["count"])
+		if state.Count <= 0 {
+			return &action_kit_api.PrepareResult{
+				Error: new(action_kit_api.ActionKitError{
+					Title:  "Number of processes must be greater than 0",
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+	case stopProcessSelectionPercentage:
+		state.Percentage = extutil.ToInt(request.Config["percentage"])
+		if state.Percentage <= 0 || state.Percentage > 100 {
+			return &action_kit_api.PrepareResult{
+				Error: new(action_kit_api.ActionKitError{
+					Title:  "Percentage of processes must be between 1 and 100",
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+	default:
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid selection '%s'", state.Selection),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+	state.OneShot = extutil.ToBool(request.Config["oneShot"])
+
 	graceful := extutil.ToBool(request.Config["graceful"])
 	state.Graceful = graceful
 	state.ExecutionId = request.ExecutionId
 	return nil, nil
 }
 
+// checkTargetProcess makes sure the discovered process still runs, its PID could have been reused since.
+func checkTargetProcess(attributes map[string][]string) *action_kit_api.PrepareResult {
+	pids := attributes["process.pid"]
+	if len(pids) == 0 || pids[0] == "" {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  "Target is missing the 'process.pid' attribute.",
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}
+	}
+	pid, err := strconv.Atoi(pids[0])
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid PID '%s'", pids[0]),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}
+	}
+	p, err := stopprocess.ReadProcess(pid)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Process %d is not running anymore", pid),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}
+	}
+	if names := attributes["process.name"]; len(names) > 0 && names[0] != p.Name {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Process %d is not %s anymore but %s", pid, names[0], p.Name),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}
+	}
+	return nil
+}
+
 func (a *stopProcessAction) Start(_ context.Context, state *StopProcessActionState) (*action_kit_api.StartResult, error) {
-	stopper := newProcessStopper(state.ProcessFilter, state.Graceful, state.Delay, state.Duration)
+	selector, err := stopprocess.NewSelector(state.MatchMode, state.ProcessFilter)
+	if err != nil {
+		return nil, err
+	}
+	stopper := newProcessStopper(selector, *state)
 
 	a.processStoppers.Store(state.ExecutionId, stopper)
+	trackExecution(state.ExecutionId, a.Describe().Id, state)
 
 	stopper.start()
 	return &action_kit_api.StartResult{
 		Messages: new([]action_kit_api.Message{
 			{
 				Level:   extutil.Ptr(action_kit_api.Info),
-				Message: fmt.Sprintf("Starting stop processes %s", state.ProcessFilter),
+				Message: fmt.Sprintf("Starting stop processes %s (%s)", state.ProcessFilter, selector.Mode),
 			},
 		}),
 	}, nil
@@ -169,10 +387,13 @@ func (a *stopProcessAction) Status(_ context.Context, state *StopProcessActionSt
 	}
 
 	s := stopper.(*processStopper)
+	s.observeReplacements()
+	messages := s.drainMessages()
 
 	if errPtr := s.err.Load(); errPtr != nil {
 		return &action_kit_api.StatusResult{
 			Completed: true,
+			Messages:  &messages,
 			Error: &action_kit_api.ActionKitError{
 				Title:  (*errPtr).Error(),
 				Status: extutil.Ptr(action_kit_api.Errored),
@@ -180,11 +401,12 @@ func (a *stopProcessAction) Status(_ context.Context, state *StopProcessActionSt
 		}, nil
 	}
 
-	return &action_kit_api.StatusResult{Completed: false}, nil
+	return &action_kit_api.StatusResult{Completed: false, Messages: &messages}, nil
 }
 
 func (a *stopProcessAction) Stop(_ context.Context, state *StopProcessActionState) (*action_kit_api.StopResult, error) {
 	stopper, ok := a.processStoppers.Load(state.ExecutionId)
+	forgetExecution(state.ExecutionId)
 	if !ok {
 		log.Debug().Msg("Execution run data not found, stop was already called")
 		return nil, nil
@@ -194,27 +416,44 @@ func (a *stopProcessAction) Stop(_ context.Context, state *StopProcessActionStat
 	s.cancel()
 	a.processStoppers.Delete(state.ExecutionId)
 
+	s.observeReplacements()
+	messages := append(s.drainMessages(), s.summary()...)
+
 	if errPtr := s.err.Load(); errPtr != nil {
 		return &action_kit_api.StopResult{
+			Messages: &messages,
 			Error: &action_kit_api.ActionKitError{
 				Title:  (*errPtr).Error(),
 				Status: extutil.Ptr(action_kit_api.Errored),
 			},
 		}, nil
 	}
-	return nil, nil
+	return &action_kit_api.StopResult{Messages: &messages}, nil
 }
 
 type processStopper struct {
 	cancel func()
 	start  func()
 	err    atomic.Pointer[error]
+
+	selector stopprocess.Selector
+	oneShot  bool
+
+	mu       sync.Mutex
+	messages []action_kit_api.Message
+	stopped  []stopprocess.Process
+	// survivors are the PIDs matching at the time of the one-shot stop which weren't stopped
+	survivors     map[int]bool
+	stoppedAt     time.Time
+	replacedAfter *time.Duration
 }
 
-func newProcessStopper(processFilter string, graceful bool, delay, duration time.Duration) *processStopper {
-	ctx, cancel := context.WithTimeout(context.Background(), duration)
+func newProcessStopper(selector stopprocess.Selector, state StopProcessActionState) *processStopper {
+	ctx, cancel := context.WithTimeout(context.Background(), state.Duration)
 	s := &processStopper{
-		cancel: cancel,
+		cancel:   cancel,
+		selector: selector,
+		oneShot:  state.OneShot,
 	}
 
 	s.start = func() {
@@ -222,15 +461,28 @@ func newProcessStopper(processFilter string, graceful bool, delay, duration time
 			defer cancel()
 			for {
 				select {
-				case <-time.After(delay):
-					pids := stopprocess.FindProcessIds(processFilter)
-					log.Debug().Msgf("Found %d processes to stop", len(pids))
-					err := stopprocess.StopProcesses(pids, !graceful)
+				case <-time.After(state.Delay):
+					processes, err := findProcesses(selector)
 					if err != nil {
+						log.Error().Err(err).Msg("Failed to list processes")
+						s.err.Store(&err)
+						return
+					}
+					victims := selectProcessesToStop(processes, state)
+					log.Debug().Msgf("Found %d processes, stopping %d", len(processes), len(victims))
+					pids := make([]int, 0, len(victims))
+					for _, p := range victims {
+						pids = append(pids, p.Pid)
+					}
+					if err := stopprocess.StopProcesses(pids, !state.Graceful); err != nil {
 						log.Error().Err(err).Msg("Failed to stop processes")
 						s.err.Store(&err)
 						return
 					}
+					s.recordStopped(processes, victims)
+					if state.OneShot {
+						return
+					}
 				case <-ctx.Done():
 					return
 				}
@@ -240,3 +492,122 @@ func newProcessStopper(processFilter string, graceful bool, delay, duration time
 
 	return s
 }
+
+// selectProcessesToStop picks random processes from the matches according to the selection.
+func selectProcessesToStop(processes []stopprocess.Process, state StopProcessActionState) []stopprocess.Process {
+	n := len(processes)
+	switch state.Selection {
+	case stopProcessSelectionCount:
+		n = state.Count
+	case stopProcessSelectionPercentage:
+		n = int(math.Ceil(float64(len(processes)) * float64(state.Percentage) / 100))
+	}
+	if n >= len(processes) {
+		return processes
+	}
+	shuffled := slices.Clone(processes)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	return shuffled[:n]
+}
+
+func (s *processStopper) recordStopped(matches, stopped []stopprocess.Process) {
+	if len(stopped) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = append(s.stopped, stopped...)
+	s.stoppedAt = time.Now()
+	if s.oneShot {
+		s.survivors = make(map[int]bool, len(matches))
+		for _, p := range matches {
+			s.survivors[p.Pid] = true
+		}
+		for _, p := range stopped {
+			delete(s.survivors, p.Pid)
+		}
+	}
+
+	names := make([]string, 0, len(stopped))
+	for _, p := range stopped {
+		names = append(names, fmt.Sprintf("%s (%d)", p.Name, p.Pid))
+	}
+	s.messages = append(s.messages, action_kit_api.Message{
+		Level:   extutil.Ptr(action_kit_api.Info),
+		Message: fmt.Sprintf("Stopped %d of %d matching processes: %s", len(stopped), len(matches), strings.Join(names, ", ")),
+	})
+}
+
+// observeReplacements checks after a one-shot stop whether new processes took over for the stopped ones.
+func (s *processStopper) observeReplacements() {
+	s.mu.Lock()
+	observe := s.oneShot && len(s.stopped) > 0 && s.replacedAfter == nil
+	s.mu.Unlock()
+	if !observe {
+		return
+	}
+
+	processes, err := findProcesses(s.selector)
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to list processes")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	replacements := 0
+	for _, p := range processes {
+		if !s.survivors[p.Pid] && !slices.ContainsFunc(s.stopped, func(stopped stopprocess.Process) bool { return stopped.Pid == p.Pid }) {
+			replacements++
+		}
+	}
+	if replacements < len(s.stopped) {
+		return
+	}
+	s.replacedAfter = new(time.Since(s.stoppedAt).Round(time.Second))
+	s.messages = append(s.messages, action_kit_api.Message{
+		Level:   extutil.Ptr(action_kit_api.Info),
+		Message: fmt.Sprintf("All %d stopped processes were replaced after %s", len(s.stopped), *s.replacedAfter),
+	})
+}
+
+func (s *processStopper) drainMessages() []action_kit_api.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := s.messages
+	s.messages = nil
+	if messages == nil {
+		return []action_kit_api.Message{}
+	}
+	return messages
+}
+
+func (s *processStopper) summary() []action_kit_api.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.stopped) == 0 {
+		return []action_kit_api.Message{{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: "No matching processes were stopped",
+		}}
+	}
+	pids := make([]string, 0, len(s.stopped))
+	for _, p := range s.stopped {
+		pids = append(pids, strconv.Itoa(p.Pid))
+	}
+	messages := []action_kit_api.Message{{
+		Level:   extutil.Ptr(action_kit_api.Info),
+		Message: fmt.Sprintf("Stopped %d processes in total (PIDs %s)", len(s.stopped), strings.Join(pids, ", ")),
+	}}
+	if s.oneShot && s.replacedAfter == nil {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("Not all of the %d stopped processes were replaced within the duration", len(s.stopped)),
+		})
+	}
+	return messages
+}
+
+var findProcesses = stopprocess.FindProcesses
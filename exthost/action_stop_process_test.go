@@ -2,9 +2,15 @@ package exthost
 
 import (
 	"context"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -41,6 +47,7 @@ func TestActionStopProcess_Prepare(t *testing.T) {
 
 			wantedState: &StopProcessActionState{
 				ProcessFilter: "tail",
+				MatchMode:     "auto",
 				Graceful:      true,
 				Duration:      10 * time.Second,
 				Delay:         1 * time.Second,
@@ -64,6 +71,43 @@ func TestActionStopProcess_Prepare(t *testing.T) {
 			},
 
 			wantedError: "Duration is required",
+		}, {
+			name: "Should return error for invalid regex",
+			requestBody: action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":    "prepare",
+					"duration":  "10000",
+					"graceful":  "true",
+					"process":   "java.*(",
+					"matchMode": "cmdline",
+				},
+				ExecutionId: uuid.New(),
+				Target: new(action_kit_api.Target{
+					Attributes: map[string][]string{
+						"host.hostname": {"myhostname"},
+					},
+				}),
+			},
+
+			wantedError: "Invalid process selection: invalid regular expression: error parsing regexp: missing closing ): `java.*(`",
+		}, {
+			name: "Should return error for protected pid",
+			requestBody: action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":   "prepare",
+					"duration": "10000",
+					"graceful": "true",
+					"process":  strconv.Itoa(os.Getpid()),
+				},
+				ExecutionId: uuid.New(),
+				Target: new(action_kit_api.Target{
+					Attributes: map[string][]string{
+						"host.hostname": {"myhostname"},
+					},
+				}),
+			},
+
+			wantedError: fmt.Sprintf("Process %d (%s) is protected and can't be stopped", os.Getpid(), selfName()),
 		},
 	}
 	action := NewStopProcessAction()
@@ -90,6 +134,7 @@ func TestActionStopProcess_Prepare(t *testing.T) {
 			if tt.wantedState != nil {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.wantedState.ProcessFilter, state.ProcessFilter)
+				assert.Equal(t, tt.wantedState.MatchMode, state.MatchMode)
 				assert.Equal(t, tt.wantedState.Graceful, state.Graceful)
 				assert.Equal(t, tt.wantedState.Delay, state.Delay)
 				deadline := now.Add(state.Duration * time.Second)
@@ -98,3 +143,148 @@ func TestActionStopProcess_Prepare(t *testing.T) {
 		})
 	}
 }
+
+func selfName() string {
+	p, _ := stopprocess.ReadProcess(os.Getpid())
+	return p.Name
+}
+
+func TestSelectProcessesToStop(t *testing.T) {
+	processes := []stopprocess.Process{{Pid: 10}, {Pid: 11}, {Pid: 12}, {Pid: 13}, {Pid: 14}}
+
+	assert.Len(t, selectProcessesToStop(processes, StopProcessActionState{Selection: stopProcessSelectionAll}), 5)
+	assert.Len(t, selectProcessesToStop(processes, StopProcessActionState{}), 5)
+	assert.Len(t, selectProcessesToStop(processes, StopProcessActionState{Selection: stopProcessSelectionCount, Count: 2}), 2)
+	assert.Len(t, selectProcessesToStop(processes, StopProcessActionState{Selection: stopProcessSelectionCount, Count: 10}), 5)
+	assert.Len(t, selectProcessesToStop(processes, StopProcessActionState{Selection: stopProcessSelectionPercentage, Percentage: 50}), 3)
+	assert.Len(t, selectProcessesToStop(processes[:1], StopProcessActionState{Selection: stopProcessSelectionPercentage, Percentage: 10}), 1)
+
+	selected := selectProcessesToStop(processes, StopProcessActionState{Selection: stopProcessSelectionCount, Count: 3})
+	for _, p := range selected {
+		assert.Contains(t, processes, p)
+	}
+	assert.Len(t, processes, 5, "input must not be modified")
+}
+
+func TestProcessStopper_ObservesReplacements(t *testing.T) {
+	workers := []stopprocess.Process{{Pid: 10, Name: "worker"}, {Pid: 11, Name: "worker"}, {Pid: 12, Name: "worker"}}
+	current := workers
+	findProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) { return current, nil }
+	defer func() { findProcesses = stopprocess.FindProcesses }()
+
+	s := newProcessStopper(stopprocess.Selector{}, StopProcessActionState{Duration: time.Minute, OneShot: true})
+	defer s.cancel()
+	s.recordStopped(workers, workers[:2])
+
+	messages := s.drainMessages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Stopped 2 of 3 matching processes: worker (10), worker (11)", messages[0].Message)
+
+	// only one replacement so far
+	current = []stopprocess.Process{workers[2], {Pid: 20, Name: "worker"}}
+	s.observeReplacements()
+	assert.Empty(t, s.drainMessages())
+
+	current = []stopprocess.Process{workers[2], {Pid: 20, Name: "worker"}, {Pid: 21, Name: "worker"}}
+	s.observeReplacements()
+	messages = s.drainMessages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "All 2 stopped processes were replaced after 0s", messages[0].Message)
+
+	summary := s.summary()
+	require.Len(t, summary, 1)
+	assert.Equal(t, "Stopped 2 processes in total (PIDs 10, 11)", summary[0].Message)
+}
+
+func TestProcessStopper_ReportsMissingReplacements(t *testing.T) {
+	findProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) { return nil, nil }
+	defer func() { findProcesses = stopprocess.FindProcesses }()
+
+	s := newProcessStopper(stopprocess.Selector{}, StopProcessActionState{Duration: time.Minute, OneShot: true})
+	defer s.cancel()
+	s.recordStopped([]stopprocess.Process{{Pid: 10, Name: "worker"}}, []stopprocess.Process{{Pid: 10, Name: "worker"}})
+	s.observeReplacements()
+
+	summary := s.summary()
+	require.Len(t, summary, 2)
+	assert.Equal(t, action_kit_api.Warn, *summary[1].Level)
+	assert.Equal(t, "Not all of the 1 stopped processes were replaced within the duration", summary[1].Message)
+}
+
+func TestActionStopProcessTarget_Prepare(t *testing.T) {
+	osHostname = func() (string, error) {
+		return "myhostname", nil
+	}
+	cmd := exec.Command("sleep", "30")
+	require.NoError(t, cmd.Start())
+	defer func() { _ = cmd.Process.Kill(); _ = cmd.Wait() }()
+	pid := strconv.Itoa(cmd.Process.Pid)
+
+	tests := []struct {
+		name        string
+		attributes  map[string][]string
+		wantedError string
+	}{
+		{
+			name:       "Should stop the targeted process",
+			attributes: map[string][]string{"host.hostname": {"myhostname"}, "process.pid": {pid}, "process.name": {"sleep"}},
+		}, {
+			name:        "Should return error for missing pid",
+			attributes:  map[string][]string{"host.hostname": {"myhostname"}},
+			wantedError: "Target is missing the 'process.pid' attribute.",
+		}, {
+			name:        "Should return error for reused pid",
+			attributes:  map[string][]string{"host.hostname": {"myhostname"}, "process.pid": {pid}, "process.name": {"nginx"}},
+			wantedError: fmt.Sprintf("Process %s is not nginx anymore but sleep", pid),
+		},
+	}
+	action := NewStopProcessTargetAction()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := StopProcessActionState{}
+			request := action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"duration": "10000",
+					"delay":    "1000",
+					"graceful": "true",
+				},
+				ExecutionId: uuid.New(),
+				Target:      new(action_kit_api.Target{Attributes: tt.attributes}),
+			}
+
+			result, err := action.Prepare(context.Background(), &state, request)
+			require.NoError(t, err)
+			if tt.wantedError != "" {
+				require.NotNil(t, result)
+				assert.Equal(t, tt.wantedError, result.Error.Title)
+				return
+			}
+			assert.Nil(t, result)
+			assert.Equal(t, pid, state.ProcessFilter)
+			assert.Equal(t, stopprocess.MatchAuto, state.MatchMode)
+			assert.Equal(t, stopProcessSelectionAll, state.Selection)
+			assert.True(t, state.Graceful)
+		})
+	}
+}
+
+func TestActionStopProcessTarget_Describe(t *testing.T) {
+	description := NewStopProcessTargetAction().Describe()
+
+	assert.Equal(t, stopProcessTargetActionID, description.Id)
+	assert.Equal(t, processTargetID, description.TargetSelection.TargetType)
+	var names []string
+	for _, p := range description.Parameters {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"graceful", "duration", "delay"}, names)
+}
+
+func TestActionStopProcess_DescribeHasDistinctParameterOrders(t *testing.T) {
+	orders := map[bool]map[int]string{false: {}, true: {}}
+	for _, p := range NewStopProcessAction().Describe().Parameters {
+		advanced := p.Advanced != nil && *p.Advanced
+		assert.NotContains(t, orders[advanced], *p.Order, "%s has the same order as %s", p.Name, orders[advanced][*p.Order])
+		orders[advanced][*p.Order] = p.Name
+	}
+}
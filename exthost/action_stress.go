@@ -54,12 +54,12 @@ func newStressAction(
 	description func() action_kit_api.ActionDescription,
 	optsProvider stressOptsProvider,
 ) action_kit_sdk.Action[StressActionState] {
-	return &stressAction{
+	return tracked[StressActionState](&stressAction{
 		description:  description(),
 		optsProvider: optsProvider,
 		ociRuntime:   runc,
 		stresses:     syncmap.Map{},
-	}
+	})
 }
 
 func (a *stressAction) NewEmptyState() StressActionState {
@@ -142,6 +142,7 @@ func (a *stressAction) Start(ctx context.Context, state *StressActionState) (*ac
 	}
 
 	a.stresses.Store(state.ExecutionId, s)
+	trackExecution(state.ExecutionId, a.description.Id, state)
 
 	if err := s.Start(); err != nil {
 		return nil, extension_kit.ToError("Failed to stress host", err)
@@ -210,6 +211,7 @@ func (a *stressAction) Stop(_ context.Context, state *StressActionState) (*actio
 	messages := make([]action_kit_api.Message, 0)
 
 	stopped := a.stopStressHost(state.ExecutionId)
+	forgetExecution(state.ExecutionId)
 	if stopped {
 		messages = append(messages, action_kit_api.Message{
 			Level:   extutil.Ptr(action_kit_api.Info),
@@ -29,7 +29,7 @@ func getStressCpuDescription() action_kit_api.ActionDescription {
 		Icon:        new(stressCPUIcon),
 		TargetSelection: new(action_kit_api.TargetSelection{
 			// The target type this action is for
-			TargetType: targetID,
+			TargetType: requireCapability(capabilityStressNg),
 			// You can provide a list of target templates to help the user select targets.
 			// A template can be used to pre-fill a selection
 			SelectionTemplates: &targetSelectionTemplates,
@@ -36,7 +36,7 @@ func getStressIoDescription() action_kit_api.ActionDescription {
 		Icon:        new(stressIOIcon),
 		TargetSelection: new(action_kit_api.TargetSelection{
 			// The target type this action is for
-			TargetType: targetID,
+			TargetType: requireCapability(capabilityStressNg),
 			// You can provide a list of target templates to help the user select targets.
 			// A template can be used to pre-fill a selection
 			SelectionTemplates: &targetSelectionTemplates,
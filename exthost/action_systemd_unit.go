@@ -0,0 +1,301 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-host/exthost/systemd"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+var systemdUnitActionID = fmt.Sprintf("%s.systemd-unit", BaseActionID)
+
+const (
+	systemdUnitModeStop    = "stop"
+	systemdUnitModeKill    = "kill"
+	systemdUnitModeRestart = "restart"
+	systemdUnitModeFreeze  = "freeze"
+)
+
+// protectedSystemdUnits would take down the extension itself or the agent driving the experiment,
+// so nothing could restore the unit afterwards.
+var protectedSystemdUnits = []string{"steadybit-extension-host.service", "steadybit-agent.service"}
+
+type systemdUnits interface {
+	ShowUnit(ctx context.Context, name string) (systemd.Unit, error)
+	Start(ctx context.Context, name string) error
+	Stop(ctx context.Context, name string) error
+	Restart(ctx context.Context, name string) error
+	KillMainProcess(ctx context.Context, name string) error
+	Freeze(ctx context.Context, name string) error
+	Thaw(ctx context.Context, name string) error
+}
+
+type systemdUnitAction struct {
+	units systemdUnits
+}
+
+type SystemdUnitActionState struct {
+	ExecutionId uuid.UUID
+	Unit        string
+	Mode        string
+	// RestartPolicy is the Restart= setting of the unit
+	RestartPolicy  string
+	WasActive      bool
+	PriorNRestarts int
+	StartedAt      time.Time
+	Applied        bool
+	// CameBackAfter is set once systemd restarted the unit during the attack
+	CameBackAfter *time.Duration
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[SystemdUnitActionState]           = (*systemdUnitAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[SystemdUnitActionState] = (*systemdUnitAction)(nil)
+	_ action_kit_sdk.ActionWithStop[SystemdUnitActionState]   = (*systemdUnitAction)(nil)
+)
+
+func NewSystemdUnitAction() action_kit_sdk.Action[SystemdUnitActionState] {
+	return journaled[SystemdUnitActionState](&systemdUnitAction{units: systemd.Systemctl{}})
+}
+
+func (a *systemdUnitAction) NewEmptyState() SystemdUnitActionState {
+	return SystemdUnitActionState{}
+}
+
+func (a *systemdUnitAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          systemdUnitActionID,
+		Label:       "Stop Service",
+		Description: "Stops, kills, restarts or freezes a systemd service for the given duration and restores its previous state afterwards.",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(systemdUnitIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         systemdUnitTargetID,
+			SelectionTemplates: new(systemdUnitSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("State"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long should the service be affected?"),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(0),
+			},
+			{
+				Name:         "mode",
+				Label:        "Mode",
+				Description:  new("Stop the service, kill its main process (an unexpected exit, so the service's Restart= policy applies), restart it once, or freeze all of its processes."),
+				Type:         action_kit_api.ActionParameterTypeString,
+				DefaultValue: new(systemdUnitModeStop),
+				Required:     new(true),
+				Order:        new(1),
+				Options: new([]action_kit_api.ParameterOption{
+					action_kit_api.ExplicitParameterOption{Label: "Stop", Value: systemdUnitModeStop},
+					action_kit_api.ExplicitParameterOption{Label: "Kill main process", Value: systemdUnitModeKill},
+					action_kit_api.ExplicitParameterOption{Label: "Restart", Value: systemdUnitModeRestart},
+					action_kit_api.ExplicitParameterOption{Label: "Freeze", Value: systemdUnitModeFreeze},
+				}),
+			},
+		},
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("1s"),
+		}),
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (a *systemdUnitAction) Prepare(ctx context.Context, state *SystemdUnitActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	names := request.Target.Attributes["systemd.unit.name"]
+	if len(names) == 0 || names[0] == "" {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  "Target is missing the 'systemd.unit.name' attribute.",
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	mode := extutil.ToString(request.Config["mode"])
+	switch mode {
+	case systemdUnitModeStop, systemdUnitModeKill, systemdUnitModeRestart, systemdUnitModeFreeze:
+	default:
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid mode '%s'", mode),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	unit, err := a.units.ShowUnit(ctx, names[0])
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Failed to read the state of %s", names[0]),
+				Status: extutil.Ptr(action_kit_api.Errored),
+				Detail: new(err.Error()),
+			}),
+		}, nil
+	}
+
+	if slices.Contains(protectedSystemdUnits, names[0]) || slices.Contains(protectedSystemdUnits, unit.Name) {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Service %s is needed to run the experiment and cannot be attacked", unit.Name),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	if (mode == systemdUnitModeKill || mode == systemdUnitModeFreeze) && !unit.IsActive() {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Service %s is not active (%s)", unit.Name, unit.ActiveState),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	state.ExecutionId = request.ExecutionId
+	state.Unit = unit.Name
+	state.Mode = mode
+	state.RestartPolicy = unit.Restart
+	state.WasActive = unit.IsActive()
+	state.PriorNRestarts = unit.NRestarts
+	return nil, nil
+}
+
+func (a *systemdUnitAction) Start(ctx context.Context, state *SystemdUnitActionState) (*action_kit_api.StartResult, error) {
+	// Journal before calling systemctl, so the unit is restored even if the extension dies meanwhile.
+	state.StartedAt = time.Now()
+	state.Applied = true
+	recordExecution(state.ExecutionId, systemdUnitActionID, state)
+
+	var err error
+	switch state.Mode {
+	case systemdUnitModeStop:
+		err = a.units.Stop(ctx, state.Unit)
+	case systemdUnitModeKill:
+		err = a.units.KillMainProcess(ctx, state.Unit)
+	case systemdUnitModeRestart:
+		err = a.units.Restart(ctx, state.Unit)
+	case systemdUnitModeFreeze:
+		err = a.units.Freeze(ctx, state.Unit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Applied %s to service %s (Restart=%s)", state.Mode, state.Unit, state.RestartPolicy),
+			},
+		}),
+	}, nil
+}
+
+func (a *systemdUnitAction) Status(ctx context.Context, state *SystemdUnitActionState) (*action_kit_api.StatusResult, error) {
+	if state.CameBackAfter != nil || (state.Mode != systemdUnitModeStop && state.Mode != systemdUnitModeKill) {
+		return &action_kit_api.StatusResult{Completed: false}, nil
+	}
+
+	unit, err := a.units.ShowUnit(ctx, state.Unit)
+	if err != nil {
+		log.Debug().Err(err).Str("unit", state.Unit).Msg("Failed to read service state")
+		return &action_kit_api.StatusResult{Completed: false}, nil
+	}
+
+	// A stopped unit isn't restarted by its Restart= policy, only through activation (socket, timer,
+	// dependencies). A killed one is expected to come back through its restart policy.
+	cameBack := unit.ActiveState == "active"
+	if state.Mode == systemdUnitModeKill {
+		cameBack = cameBack && unit.NRestarts > state.PriorNRestarts
+	}
+	if !cameBack {
+		return &action_kit_api.StatusResult{Completed: false}, nil
+	}
+
+	state.CameBackAfter = new(time.Since(state.StartedAt).Round(time.Second))
+	message := fmt.Sprintf("Service %s was activated again after %s", state.Unit, *state.CameBackAfter)
+	if state.Mode == systemdUnitModeKill {
+		message = fmt.Sprintf("Service %s was brought back by systemd (Restart=%s) after %s", state.Unit, state.RestartPolicy, *state.CameBackAfter)
+	}
+	return &action_kit_api.StatusResult{
+		Completed: false,
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: message,
+			},
+		}),
+	}, nil
+}
+
+func (a *systemdUnitAction) Stop(ctx context.Context, state *SystemdUnitActionState) (*action_kit_api.StopResult, error) {
+	if !state.Applied {
+		return nil, nil
+	}
+
+	if state.Mode == systemdUnitModeFreeze {
+		if err := a.units.Thaw(ctx, state.Unit); err != nil {
+			return nil, fmt.Errorf("failed to thaw %s: %w", state.Unit, err)
+		}
+	} else {
+		unit, err := a.units.ShowUnit(ctx, state.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the state of %s: %w", state.Unit, err)
+		}
+		if state.WasActive && !unit.IsActive() {
+			if err := a.units.Start(ctx, state.Unit); err != nil {
+				return nil, fmt.Errorf("failed to start %s: %w", state.Unit, err)
+			}
+		}
+		// a restart starts an inactive unit, which has to be stopped again
+		if !state.WasActive && unit.IsActive() {
+			if err := a.units.Stop(ctx, state.Unit); err != nil {
+				return nil, fmt.Errorf("failed to stop %s: %w", state.Unit, err)
+			}
+		}
+	}
+
+	state.Applied = false
+	forgetExecution(state.ExecutionId)
+
+	messages := []action_kit_api.Message{
+		{
+			Level:   extutil.Ptr(action_kit_api.Info),
+			Message: fmt.Sprintf("Restored service %s", state.Unit),
+		},
+	}
+	if state.Mode == systemdUnitModeKill && state.CameBackAfter == nil {
+		messages = append(messages, action_kit_api.Message{
+			Level:   extutil.Ptr(action_kit_api.Warn),
+			Message: fmt.Sprintf("Service %s was not brought back by systemd during the attack (Restart=%s)", state.Unit, state.RestartPolicy),
+		})
+	}
+	return &action_kit_api.StopResult{Messages: &messages}, nil
+}
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-host/exthost/systemd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSystemdUnits struct {
+	unit  systemd.Unit
+	calls []string
+	err   error
+}
+
+func (f *fakeSystemdUnits) ShowUnit(_ context.Context, _ string) (systemd.Unit, error) {
+	return f.unit, nil
+}
+
+func (f *fakeSystemdUnits) record(call string, activeState string) error {
+	f.calls = append(f.calls, call)
+	if f.err != nil {
+		return f.err
+	}
+	if activeState != "" {
+		f.unit.ActiveState = activeState
+	}
+	return nil
+}
+
+func (f *fakeSystemdUnits) Start(context.Context, string) error { return f.record("start", "active") }
+func (f *fakeSystemdUnits) Stop(context.Context, string) error  { return f.record("stop", "inactive") }
+func (f *fakeSystemdUnits) Restart(context.Context, string) error {
+	return f.record("restart", "active")
+}
+func (f *fakeSystemdUnits) KillMainProcess(context.Context, string) error {
+	return f.record("kill", "activating")
+}
+func (f *fakeSystemdUnits) Freeze(context.Context, string) error { return f.record("freeze", "") }
+func (f *fakeSystemdUnits) Thaw(context.Context, string) error   { return f.record("thaw", "") }
+
+func systemdUnitRequest(mode string) action_kit_api.PrepareActionRequestBody {
+	return systemdUnitRequestFor("nginx.service", mode)
+}
+
+func systemdUnitRequestFor(unit string, mode string) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      map[string]any{"duration": "10000", "mode": mode},
+		ExecutionId: uuid.New(),
+		Target: new(action_kit_api.Target{
+			Attributes: map[string][]string{
+				"host.hostname":     {"myhostname"},
+				"systemd.unit.name": {unit},
+			},
+		}),
+	}
+}
+
+func TestActionSystemdUnit_Prepare(t *testing.T) {
+	osHostname = func() (string, error) {
+		return "myhostname", nil
+	}
+
+	tests := []struct {
+		name        string
+		mode        string
+		activeState string
+		wantedError string
+	}{
+		{name: "stop active unit", mode: "stop", activeState: "active"},
+		{name: "stop inactive unit", mode: "stop", activeState: "inactive"},
+		{name: "invalid mode", mode: "reload", activeState: "active", wantedError: "Invalid mode 'reload'"},
+		{name: "kill inactive unit", mode: "kill", activeState: "inactive", wantedError: "Service nginx.service is not active (inactive)"},
+		{name: "freeze inactive unit", mode: "freeze", activeState: "failed", wantedError: "Service nginx.service is not active (failed)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			units := &fakeSystemdUnits{unit: systemd.Unit{Name: "nginx.service", ActiveState: tt.activeState, Restart: "always", NRestarts: 3}}
+			action := &systemdUnitAction{units: units}
+			state := action.NewEmptyState()
+
+			result, err := action.Prepare(context.Background(), &state, systemdUnitRequest(tt.mode))
+			require.NoError(t, err)
+			if tt.wantedError != "" {
+				require.NotNil(t, result)
+				assert.Equal(t, tt.wantedError, result.Error.Title)
+				return
+			}
+			assert.Nil(t, result)
+			assert.Equal(t, "nginx.service", state.Unit)
+			assert.Equal(t, tt.mode, state.Mode)
+			assert.Equal(t, "always", state.RestartPolicy)
+			assert.Equal(t, tt.activeState == "active", state.WasActive)
+			assert.Equal(t, 3, state.PriorNRestarts)
+		})
+	}
+}
+
+func TestActionSystemdUnit_KillReportsRestartAndRestores(t *testing.T) {
+	osHostname = func() (string, error) {
+		return "myhostname", nil
+	}
+	units := &fakeSystemdUnits{unit: systemd.Unit{Name: "nginx.service", ActiveState: "active", Restart: "on-failure", NRestarts: 1}}
+	action := &systemdUnitAction{units: units}
+	state := action.NewEmptyState()
+	ctx := context.Background()
+
+	_, err := action.Prepare(ctx, &state, systemdUnitRequest("kill"))
+	require.NoError(t, err)
+	_, err = action.Start(ctx, &state)
+	require.NoError(t, err)
+
+	status, err := action.Status(ctx, &state)
+	require.NoError(t, err)
+	assert.Nil(t, status.Messages)
+	assert.Nil(t, state.CameBackAfter)
+
+	// systemd restarted the unit
+	units.unit.ActiveState = "active"
+	units.unit.NRestarts = 2
+	status, err = action.Status(ctx, &state)
+	require.NoError(t, err)
+	require.NotNil(t, status.Messages)
+	assert.Contains(t, (*status.Messages)[0].Message, "was brought back by systemd (Restart=on-failure)")
+	assert.NotNil(t, state.CameBackAfter)
+
+	result, err := action.Stop(ctx, &state)
+	require.NoError(t, err)
+	assert.Len(t, *result.Messages, 1)
+	assert.Equal(t, []string{"kill"}, units.calls)
+}
+
+func TestActionSystemdUnit_StopRestoresPriorState(t *testing.T) {
+	osHostname = func() (string, error) {
+		return "myhostname", nil
+	}
+	tests := []struct {
+		name        string
+		mode        string
+		activeState string
+		wantedCalls []string
+	}{
+		{name: "stop active unit", mode: "stop", activeState: "active", wantedCalls: []string{"stop", "start"}},
+		{name: "stop inactive unit", mode: "stop", activeState: "inactive", wantedCalls: []string{"stop"}},
+		{name: "freeze", mode: "freeze", activeState: "active", wantedCalls: []string{"freeze", "thaw"}},
+		{name: "restart", mode: "restart", activeState: "active", wantedCalls: []string{"restart"}},
+		{name: "restart inactive unit", mode: "restart", activeState: "inactive", wantedCalls: []string{"restart", "stop"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			units := &fakeSystemdUnits{unit: systemd.Unit{Name: "nginx.service", ActiveState: tt.activeState}}
+			action := &systemdUnitAction{units: units}
+			state := action.NewEmptyState()
+			ctx := context.Background()
+
+			_, err := action.Prepare(ctx, &state, systemdUnitRequest(tt.mode))
+			require.NoError(t, err)
+			_, err = action.Start(ctx, &state)
+			require.NoError(t, err)
+			_, err = action.Stop(ctx, &state)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantedCalls, units.calls)
+			assert.False(t, state.Applied)
+		})
+	}
+}
+
+func TestActionSystemdUnit_PrepareRejectsProtectedUnits(t *testing.T) {
+	osHostname = func() (string, error) {
+		return "myhostname", nil
+	}
+
+	for _, name := range []string{"steadybit-extension-host.service", "steadybit-agent.service"} {
+		t.Run(name, func(t *testing.T) {
+			units := &fakeSystemdUnits{unit: systemd.Unit{Name: name, ActiveState: "active"}}
+			action := &systemdUnitAction{units: units}
+			state := action.NewEmptyState()
+
+			result, err := action.Prepare(context.Background(), &state, systemdUnitRequestFor(name, "stop"))
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			assert.Equal(t, "Service "+name+" is needed to run the experiment and cannot be attacked", result.Error.Title)
+		})
+	}
+}
+
+func TestActionSystemdUnit_StartMarksAppliedBeforeCallingSystemctl(t *testing.T) {
+	osHostname = func() (string, error) {
+		return "myhostname", nil
+	}
+	units := &fakeSystemdUnits{unit: systemd.Unit{Name: "nginx.service", ActiveState: "active"}}
+	action := &systemdUnitAction{units: units}
+	state := action.NewEmptyState()
+	ctx := context.Background()
+
+	_, err := action.Prepare(ctx, &state, systemdUnitRequest("freeze"))
+	require.NoError(t, err)
+
+	units.err = errors.New("connection timed out")
+	_, err = action.Start(ctx, &state)
+	require.Error(t, err)
+	assert.True(t, state.Applied)
+
+	units.err = nil
+	_, err = action.Stop(ctx, &state)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"freeze", "thaw"}, units.calls)
+}
@@ -0,0 +1,581 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+const (
+	cgroupCpuMax     = "cpu.max"
+	cgroupMemoryHigh = "memory.high"
+	cgroupMemoryMax  = "memory.max"
+	cgroupIoMax      = "io.max"
+	cgroupPidsMax    = "pids.max"
+
+	// cpuMaxPeriod is the default period of cpu.max in microseconds
+	cpuMaxPeriod = 100000
+)
+
+// throttlingCounters are reported from cpu.stat and memory.events during the attack.
+var throttlingCounters = map[string][]string{
+	"cpu.stat":      {"nr_throttled", "throttled_usec"},
+	"memory.events": {"high", "max", "oom_kill"},
+}
+
+type throttleProcessAction struct {
+}
+
+type ThrottleProcessActionState struct {
+	ExecutionId   uuid.UUID
+	ProcessFilter string
+	MatchMode     string
+	// Limits maps the cgroup interface files to the values to apply
+	Limits map[string]string
+	// Cgroups are the cgroups of the matching processes
+	Cgroups []string
+	// Pids are the matching processes per cgroup
+	Pids map[string][]int
+	// Delegated are the cgroups containing other processes as well, which are delegated to their unit
+	// (Delegate=yes). The matching processes are moved into a limited child, the others into a sibling, as a
+	// cgroup distributing resources to its children must not contain processes itself. The other cgroups
+	// contain only matching processes and are limited themselves.
+	Delegated []string
+	// PriorLimits are the values of the limited files before the attack, per cgroup limited itself
+	PriorLimits map[string]map[string]string
+	// EnabledControllers are the controllers enabled in the cgroup.subtree_control of each delegated cgroup
+	EnabledControllers map[string][]string
+	Applied            bool
+}
+
+// cgroupControllers are the controllers of the limited interface files
+var cgroupControllers = map[string]string{
+	cgroupCpuMax:     "cpu",
+	cgroupMemoryHigh: "memory",
+	cgroupMemoryMax:  "memory",
+	cgroupIoMax:      "io",
+	cgroupPidsMax:    "pids",
+}
+
+// Make sure action implements all required interfaces
+var (
+	_ action_kit_sdk.Action[ThrottleProcessActionState]           = (*throttleProcessAction)(nil)
+	_ action_kit_sdk.ActionWithStatus[ThrottleProcessActionState] = (*throttleProcessAction)(nil)
+	_ action_kit_sdk.ActionWithStop[ThrottleProcessActionState]   = (*throttleProcessAction)(nil)
+)
+
+func NewThrottleProcessAction() action_kit_sdk.Action[ThrottleProcessActionState] {
+	return journaled[ThrottleProcessActionState](&throttleProcessAction{})
+}
+
+func (a *throttleProcessAction) NewEmptyState() ThrottleProcessActionState {
+	return ThrottleProcessActionState{}
+}
+
+func (a *throttleProcessAction) Describe() action_kit_api.ActionDescription {
+	return action_kit_api.ActionDescription{
+		Id:          throttleProcessActionID,
+		Label:       "Throttle Processes",
+		Description: "Limits CPU, memory, IO or the number of tasks of the targeted processes for the given duration through the limits of their cgroup (cgroup v2 only). Processes sharing their cgroup with other processes can only be limited if the cgroup is delegated (Delegate=yes).",
+		Version:     extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:        new(stressCPUIcon),
+		TargetSelection: new(action_kit_api.TargetSelection{
+			TargetType:         targetID,
+			SelectionTemplates: new(targetSelectionTemplates),
+		}),
+		Technology:  new("Linux Host"),
+		Category:    new("Resource"),
+		Kind:        action_kit_api.Attack,
+		TimeControl: action_kit_api.TimeControlExternal,
+		Widgets: new([]action_kit_api.Widget{
+			action_kit_api.LineChartWidget{
+				Type:  action_kit_api.ComSteadybitWidgetLineChart,
+				Title: "Throttling",
+				Identity: action_kit_api.LineChartWidgetIdentityConfig{
+					MetricName: "cgroup_throttling",
+					From:       "counter",
+					Mode:       action_kit_api.ComSteadybitWidgetLineChartIdentityModeWidgetPerValue,
+				},
+				Tooltip: new(action_kit_api.LineChartWidgetTooltipConfig{
+					MetricValueTitle: new("Count"),
+					AdditionalContent: []action_kit_api.LineChartWidgetTooltipContent{
+						{
+							From:  "cgroup",
+							Title: "Cgroup",
+						},
+					},
+				}),
+			},
+		}),
+		Parameters: []action_kit_api.ActionParameter{
+			{
+				Name:         "duration",
+				Label:        "Duration",
+				Description:  new("How long should the processes be throttled?"),
+				Type:         action_kit_api.ActionParameterTypeDuration,
+				DefaultValue: new("30s"),
+				Required:     new(true),
+				Order:        new(0),
+			},
+			{
+				Name:        "process",
+				Label:       "Process",
+				Description: new("PID or string to match the process name or command. How it is matched is defined by 'Match by'. The other processes of their cgroups aren't limited."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Required:    new(true),
+				Order:       new(1),
+			},
+			processMatchModeParameter(new(2)),
+			{
+				Name:         "cpuLimit",
+				Label:        "CPU limit (% of one core)",
+				Description:  new("Sets cpu.max to this share of one CPU core, e.g. 50 for half a core or 200 for two cores. 0 leaves it unchanged."),
+				Type:         action_kit_api.ActionParameterTypeInteger,
+				DefaultValue: new("0"),
+				Order:        new(3),
+			},
+			{
+				Name:         "memoryHigh",
+				Label:        "Memory high (MB)",
+				Description:  new("Sets memory.high, above which the processes are throttled and reclaimed. 0 leaves it unchanged."),
+				Type:         action_kit_api.ActionParameterTypeInteger,
+				DefaultValue: new("0"),
+				Order:        new(4),
+			},
+			{
+				Name:         "memoryMax",
+				Label:        "Memory max (MB)",
+				Description:  new("Sets memory.max, above which the processes are OOM killed. 0 leaves it unchanged."),
+				Type:         action_kit_api.ActionParameterTypeInteger,
+				DefaultValue: new("0"),
+				Advanced:     new(true),
+				Order:        new(5),
+			},
+			{
+				Name:        "ioMax",
+				Label:       "IO limit",
+				Description: new("Sets io.max for a device, e.g. '8:0 rbps=1048576 wbps=1048576'. Empty leaves it unchanged."),
+				Type:        action_kit_api.ActionParameterTypeString,
+				Advanced:    new(true),
+				Order:       new(6),
+			},
+			{
+				Name:         "pidsMax",
+				Label:        "Max tasks",
+				Description:  new("Sets pids.max, the number of processes and threads the cgroup may have. 0 leaves it unchanged."),
+				Type:         action_kit_api.ActionParameterTypeInteger,
+				DefaultValue: new("0"),
+				Advanced:     new(true),
+				Order:        new(7),
+			},
+		},
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("5s"),
+		}),
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+}
+
+func (a *throttleProcessAction) Prepare(_ context.Context, state *ThrottleProcessActionState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	if _, err := CheckTargetHostname(request.Target.Attributes); err != nil {
+		return nil, err
+	}
+
+	if version, err := cgroupVersion(); err != nil || version != "v2" {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  "Throttling processes requires cgroup v2",
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	selector, err := stopprocess.NewSelector(extutil.ToString(request.Config["matchMode"]), extutil.ToString(request.Config["process"]))
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  fmt.Sprintf("Invalid process selection: %s", err),
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	limits := map[string]string{}
+	if cpu := extutil.ToInt(request.Config["cpuLimit"]); cpu > 0 {
+		limits[cgroupCpuMax] = fmt.Sprintf("%d %d", cpu*cpuMaxPeriod/100, cpuMaxPeriod)
+	}
+	if high := extutil.ToInt64(request.Config["memoryHigh"]); high > 0 {
+		limits[cgroupMemoryHigh] = fmt.Sprintf("%d", high*1024*1024)
+	}
+	if limit := extutil.ToInt64(request.Config["memoryMax"]); limit > 0 {
+		limits[cgroupMemoryMax] = fmt.Sprintf("%d", limit*1024*1024)
+	}
+	if io := strings.TrimSpace(extutil.ToString(request.Config["ioMax"])); io != "" {
+		if len(strings.Fields(io)) < 2 {
+			return &action_kit_api.PrepareResult{
+				Error: new(action_kit_api.ActionKitError{
+					Title:  fmt.Sprintf("Invalid IO limit '%s', expected '<major>:<minor> <key>=<value>...'", io),
+					Status: extutil.Ptr(action_kit_api.Errored),
+				}),
+			}, nil
+		}
+		limits[cgroupIoMax] = io
+	}
+	if pids := extutil.ToInt(request.Config["pidsMax"]); pids > 0 {
+		limits[cgroupPidsMax] = fmt.Sprintf("%d", pids)
+	}
+	if len(limits) == 0 {
+		return &action_kit_api.PrepareResult{
+			Error: new(action_kit_api.ActionKitError{
+				Title:  "At least one limit is required",
+				Status: extutil.Ptr(action_kit_api.Errored),
+			}),
+		}, nil
+	}
+
+	state.ExecutionId = request.ExecutionId
+	state.ProcessFilter = selector.Value
+	state.MatchMode = selector.Mode
+	state.Limits = limits
+	return nil, nil
+}
+
+func (a *throttleProcessAction) Start(_ context.Context, state *ThrottleProcessActionState) (*action_kit_api.StartResult, error) {
+	selector, err := stopprocess.NewSelector(state.MatchMode, state.ProcessFilter)
+	if err != nil {
+		return nil, err
+	}
+	processes, err := findProcesses(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Pids = map[string][]int{}
+	for _, p := range processes {
+		if p.Cgroup == "" || p.Cgroup == "/" {
+			continue
+		}
+		if err := stopprocess.CheckCgroup(p.Cgroup); err != nil {
+			log.Debug().Err(err).Int("pid", p.Pid).Msg("Skipping process outside of the cgroup namespace")
+			continue
+		}
+		state.Pids[p.Cgroup] = append(state.Pids[p.Cgroup], p.Pid)
+	}
+	state.Cgroups = slices.Sorted(maps.Keys(state.Pids))
+	if len(state.Cgroups) == 0 {
+		return nil, fmt.Errorf("no cgroups of processes matching %s (%s) found", state.ProcessFilter, state.MatchMode)
+	}
+
+	state.PriorLimits = map[string]map[string]string{}
+	state.EnabledControllers = map[string][]string{}
+	for _, cgroup := range state.Cgroups {
+		procs, err := cgroupProcs(cgroup)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(procs, func(pid int) bool { return !slices.Contains(state.Pids[cgroup], pid) }) {
+			if err := a.prepareDelegated(state, cgroup); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		available, err := readCgroupFile(cgroup, "cgroup.controllers")
+		if err != nil {
+			return nil, err
+		}
+		state.PriorLimits[cgroup] = map[string]string{}
+		for _, file := range slices.Sorted(maps.Keys(state.Limits)) {
+			if controller := cgroupControllers[file]; !slices.Contains(strings.Fields(available), controller) {
+				return nil, fmt.Errorf("the %s controller isn't enabled for cgroup %s by its parent", controller, cgroup)
+			}
+			prior, err := readCgroupFile(cgroup, file)
+			if err != nil {
+				return nil, err
+			}
+			state.PriorLimits[cgroup][file] = prior
+		}
+	}
+
+	state.Applied = true
+	recordExecution(state.ExecutionId, throttleProcessActionID, state)
+
+	for _, cgroup := range state.Cgroups {
+		if err := a.limit(state, cgroup); err != nil {
+			if restoreErr := a.restore(state); restoreErr != nil {
+				return nil, errors.Join(err, restoreErr)
+			}
+			state.Applied = false
+			forgetExecution(state.ExecutionId)
+			return nil, err
+		}
+	}
+
+	limits := make([]string, 0, len(state.Limits))
+	for _, file := range slices.Sorted(maps.Keys(state.Limits)) {
+		limits = append(limits, fmt.Sprintf("%s=%s", file, state.Limits[file]))
+	}
+	throttled := make([]string, 0, len(state.Cgroups))
+	for _, cgroup := range state.Cgroups {
+		throttled = append(throttled, limitedCgroup(state, cgroup))
+	}
+	return &action_kit_api.StartResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Applied %s to %d processes in %s", strings.Join(limits, ", "), len(processes), strings.Join(throttled, ", ")),
+			},
+		}),
+	}, nil
+}
+
+// prepareDelegated checks whether the other processes of the cgroup may be moved into a sibling of the
+// limited child. This is refused for cgroups managed by systemd, as the unit breaks if it isn't restored.
+func (a *throttleProcessAction) prepareDelegated(state *ThrottleProcessActionState, cgroup string) error {
+	if !isDelegatedCgroup(cgroup) {
+		return fmt.Errorf("cgroup %s contains other processes than the matching ones and isn't delegated (Delegate=yes), so the matching processes can't be limited alone", cgroup)
+	}
+
+	protected, err := findProtectedProcesses(stopprocess.Selector{Mode: stopprocess.MatchCgroup, Value: cgroup})
+	if err != nil {
+		return err
+	}
+	if len(protected) > 0 {
+		return fmt.Errorf("cgroup %s contains the protected process %d (%s)", cgroup, protected[0].Pid, protected[0].Name)
+	}
+
+	enabled, err := readCgroupFile(cgroup, "cgroup.subtree_control")
+	if err != nil {
+		return err
+	}
+	for _, file := range slices.Sorted(maps.Keys(state.Limits)) {
+		controller := cgroupControllers[file]
+		if !slices.Contains(strings.Fields(enabled), controller) && !slices.Contains(state.EnabledControllers[cgroup], controller) {
+			state.EnabledControllers[cgroup] = append(state.EnabledControllers[cgroup], controller)
+		}
+	}
+	state.Delegated = append(state.Delegated, cgroup)
+	return nil
+}
+
+// limit applies the limits to a cgroup containing only matching processes. The matching processes of a
+// delegated cgroup are moved into the throttled child and the others into a sibling, the controllers are
+// enabled for the children and the limits are applied to the throttled one.
+func (a *throttleProcessAction) limit(state *ThrottleProcessActionState, cgroup string) error {
+	if !slices.Contains(state.Delegated, cgroup) {
+		for _, file := range slices.Sorted(maps.Keys(state.Limits)) {
+			if err := writeCgroupFile(cgroup, file, state.Limits[file]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	throttled := throttledCgroup(cgroup, state.ExecutionId)
+	if err := createCgroup(throttled); err != nil {
+		return err
+	}
+	for _, pid := range state.Pids[cgroup] {
+		if err := moveToCgroup(throttled, pid); err != nil {
+			return err
+		}
+	}
+
+	others, err := cgroupProcs(cgroup)
+	if err != nil {
+		return err
+	}
+	if len(others) > 0 {
+		unlimited := unlimitedCgroup(cgroup, state.ExecutionId)
+		if err := createCgroup(unlimited); err != nil {
+			return err
+		}
+		for _, pid := range others {
+			if err := moveToCgroup(unlimited, pid); err != nil {
+				return err
+			}
+		}
+	}
+
+	if controllers := state.EnabledControllers[cgroup]; len(controllers) > 0 {
+		if err := writeCgroupFile(cgroup, "cgroup.subtree_control", "+"+strings.Join(controllers, " +")); err != nil {
+			return err
+		}
+	}
+	for _, file := range slices.Sorted(maps.Keys(state.Limits)) {
+		if err := writeCgroupFile(throttled, file, state.Limits[file]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *throttleProcessAction) Status(_ context.Context, state *ThrottleProcessActionState) (*action_kit_api.StatusResult, error) {
+	now := time.Now()
+	var metrics []action_kit_api.Metric
+	for _, cgroup := range state.Cgroups {
+		throttled := limitedCgroup(state, cgroup)
+		for _, file := range slices.Sorted(maps.Keys(throttlingCounters)) {
+			stats, err := readCgroupStats(throttled, file)
+			if err != nil {
+				log.Debug().Err(err).Str("cgroup", throttled).Msg("Failed to read throttling counters")
+				continue
+			}
+			for _, counter := range throttlingCounters[file] {
+				metrics = append(metrics, action_kit_api.Metric{
+					Name: new("cgroup_throttling"),
+					Metric: map[string]string{
+						"cgroup":  throttled,
+						"counter": fmt.Sprintf("%s %s", file, counter),
+					},
+					Value:     float64(stats[counter]),
+					Timestamp: now,
+				})
+			}
+		}
+	}
+	return &action_kit_api.StatusResult{Completed: false, Metrics: &metrics}, nil
+}
+
+func (a *throttleProcessAction) Stop(_ context.Context, state *ThrottleProcessActionState) (*action_kit_api.StopResult, error) {
+	if !state.Applied {
+		return nil, nil
+	}
+
+	if err := a.restore(state); err != nil {
+		return nil, err
+	}
+	state.Applied = false
+	forgetExecution(state.ExecutionId)
+
+	return &action_kit_api.StopResult{
+		Messages: new([]action_kit_api.Message{
+			{
+				Level:   extutil.Ptr(action_kit_api.Info),
+				Message: fmt.Sprintf("Restored the limits of %s", strings.Join(state.Cgroups, ", ")),
+			},
+		}),
+	}, nil
+}
+
+// restore writes the prior limits back. For delegated cgroups it disables the controllers enabled for the
+// children, moves the processes back into their cgroup and removes the children. Cgroups which don't exist
+// (anymore) are skipped.
+func (a *throttleProcessAction) restore(state *ThrottleProcessActionState) error {
+	var errs error
+	for _, cgroup := range state.Cgroups {
+		if !slices.Contains(state.Delegated, cgroup) {
+			for _, file := range slices.Sorted(maps.Keys(state.PriorLimits[cgroup])) {
+				prior := priorLimit(file, state.Limits[file], state.PriorLimits[cgroup][file])
+				if err := writeCgroupFile(cgroup, file, prior); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					errs = errors.Join(errs, err)
+				}
+			}
+			continue
+		}
+
+		if controllers := state.EnabledControllers[cgroup]; len(controllers) > 0 {
+			if err := writeCgroupFile(cgroup, "cgroup.subtree_control", "-"+strings.Join(controllers, " -")); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = errors.Join(errs, err)
+			}
+		}
+		for _, child := range []string{throttledCgroup(cgroup, state.ExecutionId), unlimitedCgroup(cgroup, state.ExecutionId)} {
+			pids, err := cgroupProcs(child)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			} else if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			for _, pid := range pids {
+				if err := moveToCgroup(cgroup, pid); err != nil {
+					errs = errors.Join(errs, err)
+				}
+			}
+			if err := removeCgroup(child); err != nil {
+				errs = errors.Join(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+// priorLimit returns the value restoring a limited file. io.max lists a line per device and is written per
+// device, so the prior line of the limited device is restored, or no limit if it had none.
+func priorLimit(file, limit, prior string) string {
+	if file != cgroupIoMax {
+		return prior
+	}
+	device, _, _ := strings.Cut(limit, " ")
+	for _, line := range strings.Split(prior, "\n") {
+		if strings.HasPrefix(line, device+" ") {
+			return line
+		}
+	}
+	return device + " rbps=max wbps=max riops=max wiops=max"
+}
+
+// limitedCgroup is the cgroup the limits are applied to.
+func limitedCgroup(state *ThrottleProcessActionState, cgroup string) string {
+	if slices.Contains(state.Delegated, cgroup) {
+		return throttledCgroup(cgroup, state.ExecutionId)
+	}
+	return cgroup
+}
+
+func throttledCgroup(cgroup string, executionId uuid.UUID) string {
+	return fmt.Sprintf("%s/steadybit-throttled-%s", cgroup, executionId.String()[:8])
+}
+
+func unlimitedCgroup(cgroup string, executionId uuid.UUID) string {
+	return fmt.Sprintf("%s/steadybit-unlimited-%s", cgroup, executionId.String()[:8])
+}
+
+func cgroupProcs(cgroup string) ([]int, error) {
+	content, err := readCgroupFile(cgroup, "cgroup.procs")
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, line := range strings.Fields(content) {
+		if pid, err := strconv.Atoi(line); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// moveToCgroup moves the process with all of its threads. Processes which exited meanwhile are ignored.
+func moveToCgroup(cgroup string, pid int) error {
+	if err := writeCgroupFile(cgroup, "cgroup.procs", strconv.Itoa(pid)); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	return nil
+}
+
+var (
+	readCgroupFile    = stopprocess.ReadCgroupFile
+	writeCgroupFile   = stopprocess.WriteCgroupFile
+	readCgroupStats   = stopprocess.ReadCgroupStats
+	createCgroup      = stopprocess.CreateCgroup
+	removeCgroup      = stopprocess.RemoveCgroup
+	isDelegatedCgroup = stopprocess.IsDelegatedCgroup
+)
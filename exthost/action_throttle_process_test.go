@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-host/exthost/hostinfo"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCgroups struct {
+	files map[string]string
+	// procs are the processes per existing cgroup
+	procs     map[string][]int
+	delegated []string
+}
+
+func (f *fakeCgroups) install(t *testing.T, processes []stopprocess.Process) {
+	findProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) { return processes, nil }
+	findProtectedProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) { return nil, nil }
+	cgroupVersion = func() (string, error) { return "v2", nil }
+	isDelegatedCgroup = func(cgroup string) bool { return slices.Contains(f.delegated, cgroup) }
+	readCgroupFile = func(cgroup, file string) (string, error) {
+		if _, ok := f.procs[cgroup]; !ok {
+			return "", fmt.Errorf("failed to read %s/%s: %w", cgroup, file, fs.ErrNotExist)
+		}
+		if file == "cgroup.procs" {
+			var lines []string
+			for _, pid := range f.procs[cgroup] {
+				lines = append(lines, strconv.Itoa(pid))
+			}
+			return strings.Join(lines, "\n"), nil
+		}
+		return f.files[cgroup+"/"+file], nil
+	}
+	writeCgroupFile = func(cgroup, file, value string) error {
+		if _, ok := f.procs[cgroup]; !ok {
+			return fmt.Errorf("failed to write %s/%s: %w", cgroup, file, fs.ErrNotExist)
+		}
+		switch file {
+		case "cgroup.procs":
+			pid, _ := strconv.Atoi(value)
+			for c := range f.procs {
+				f.procs[c] = slices.DeleteFunc(f.procs[c], func(p int) bool { return p == pid })
+			}
+			f.procs[cgroup] = append(f.procs[cgroup], pid)
+		case "cgroup.subtree_control":
+			enabled := strings.Fields(f.files[cgroup+"/"+file])
+			for _, change := range strings.Fields(value) {
+				enabled = slices.DeleteFunc(enabled, func(c string) bool { return c == change[1:] })
+				if change[0] == '+' {
+					enabled = append(enabled, change[1:])
+				}
+			}
+			f.files[cgroup+"/"+file] = strings.Join(enabled, " ")
+		default:
+			f.files[cgroup+"/"+file] = value
+		}
+		return nil
+	}
+	readCgroupStats = func(cgroup, file string) (map[string]uint64, error) {
+		return map[string]uint64{"nr_throttled": 7, "throttled_usec": 2500, "high": 3}, nil
+	}
+	createCgroup = func(cgroup string) error {
+		if _, ok := f.procs[cgroup]; !ok {
+			f.procs[cgroup] = nil
+		}
+		return nil
+	}
+	removeCgroup = func(cgroup string) error {
+		if len(f.procs[cgroup]) > 0 {
+			return fmt.Errorf("cgroup %s is busy", cgroup)
+		}
+		delete(f.procs, cgroup)
+		return nil
+	}
+	t.Cleanup(func() {
+		findProcesses = stopprocess.FindProcesses
+		findProtectedProcesses = stopprocess.FindProtectedProcesses
+		cgroupVersion = hostinfo.CgroupVersion
+		readCgroupFile = stopprocess.ReadCgroupFile
+		writeCgroupFile = stopprocess.WriteCgroupFile
+		readCgroupStats = stopprocess.ReadCgroupStats
+		createCgroup = stopprocess.CreateCgroup
+		removeCgroup = stopprocess.RemoveCgroup
+		isDelegatedCgroup = stopprocess.IsDelegatedCgroup
+	})
+}
+
+func throttleProcessRequest(config map[string]any) action_kit_api.PrepareActionRequestBody {
+	return action_kit_api.PrepareActionRequestBody{
+		Config:      config,
+		ExecutionId: uuid.New(),
+		Target:      new(action_kit_api.Target{Attributes: map[string][]string{"host.hostname": {"myhostname"}}}),
+	}
+}
+
+func TestActionThrottleProcess(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeCgroups{
+		files: map[string]string{
+			"/system.slice/orders.service/cgroup.controllers": "cpu io memory pids",
+			"/system.slice/orders.service/cpu.max":            "max 100000",
+			"/system.slice/orders.service/memory.high":        "max",
+			"/system.slice/orders.service/io.max":             "8:16 rbps=2097152 wbps=max riops=max wiops=max",
+		},
+		procs: map[string][]int{"/system.slice/orders.service": {10, 11}},
+	}
+	fake.install(t, []stopprocess.Process{
+		{Pid: 10, Name: "orders", Cgroup: "/system.slice/orders.service"},
+		{Pid: 11, Name: "orders", Cgroup: "/system.slice/orders.service"},
+	})
+
+	action := &throttleProcessAction{}
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, throttleProcessRequest(map[string]any{
+		"duration":   "10000",
+		"process":    "orders",
+		"matchMode":  "name",
+		"cpuLimit":   50,
+		"memoryHigh": 256,
+		"ioMax":      "8:0 rbps=1048576",
+	}))
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	start, err := action.Start(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Empty(t, state.Delegated)
+	assert.Equal(t, "Applied cpu.max=50000 100000, io.max=8:0 rbps=1048576, memory.high=268435456 to 2 processes in /system.slice/orders.service", (*start.Messages)[0].Message)
+	assert.Equal(t, map[string][]int{"/system.slice/orders.service": {10, 11}}, fake.procs)
+	assert.Equal(t, "50000 100000", fake.files["/system.slice/orders.service/cpu.max"])
+	assert.Equal(t, "268435456", fake.files["/system.slice/orders.service/memory.high"])
+
+	status, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, "/system.slice/orders.service", (*status.Metrics)[0].Metric["cgroup"])
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.False(t, state.Applied)
+	assert.Equal(t, "max 100000", fake.files["/system.slice/orders.service/cpu.max"])
+	assert.Equal(t, "max", fake.files["/system.slice/orders.service/memory.high"])
+	assert.Equal(t, "8:0 rbps=max wbps=max riops=max wiops=max", fake.files["/system.slice/orders.service/io.max"])
+}
+
+func TestActionThrottleProcess_RequiresEnabledController(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeCgroups{
+		files: map[string]string{"/system.slice/orders.service/cgroup.controllers": "memory pids"},
+		procs: map[string][]int{"/system.slice/orders.service": {10}},
+	}
+	fake.install(t, []stopprocess.Process{{Pid: 10, Name: "orders", Cgroup: "/system.slice/orders.service"}})
+
+	action := &throttleProcessAction{}
+	state := action.NewEmptyState()
+	_, err := action.Prepare(context.Background(), &state, throttleProcessRequest(map[string]any{"duration": "10000", "process": "orders", "cpuLimit": 50}))
+	require.NoError(t, err)
+
+	_, err = action.Start(context.Background(), &state)
+	assert.EqualError(t, err, "the cpu controller isn't enabled for cgroup /system.slice/orders.service by its parent")
+	assert.False(t, state.Applied)
+}
+
+func TestActionThrottleProcess_RefusesSharedCgroupNotDelegated(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeCgroups{files: map[string]string{}, procs: map[string][]int{"/system.slice/orders.service": {10, 12}}}
+	fake.install(t, []stopprocess.Process{{Pid: 10, Name: "orders", Cgroup: "/system.slice/orders.service"}})
+
+	action := &throttleProcessAction{}
+	state := action.NewEmptyState()
+	_, err := action.Prepare(context.Background(), &state, throttleProcessRequest(map[string]any{"duration": "10000", "process": "orders", "pidsMax": 10}))
+	require.NoError(t, err)
+
+	_, err = action.Start(context.Background(), &state)
+	assert.EqualError(t, err, "cgroup /system.slice/orders.service contains other processes than the matching ones and isn't delegated (Delegate=yes), so the matching processes can't be limited alone")
+	assert.Equal(t, map[string][]int{"/system.slice/orders.service": {10, 12}}, fake.procs)
+}
+
+func TestActionThrottleProcess_DelegatedCgroup(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeCgroups{
+		files:     map[string]string{"/system.slice/orders.service/cgroup.subtree_control": "memory"},
+		procs:     map[string][]int{"/system.slice/orders.service": {10, 11, 12}},
+		delegated: []string{"/system.slice/orders.service"},
+	}
+	fake.install(t, []stopprocess.Process{
+		{Pid: 10, Name: "orders", Cgroup: "/system.slice/orders.service"},
+		{Pid: 11, Name: "orders", Cgroup: "/system.slice/orders.service"},
+	})
+
+	action := &throttleProcessAction{}
+	state := action.NewEmptyState()
+	request := throttleProcessRequest(map[string]any{
+		"duration":   "10000",
+		"process":    "orders",
+		"matchMode":  "name",
+		"cpuLimit":   50,
+		"memoryHigh": 256,
+		"ioMax":      "8:0 rbps=1048576",
+	})
+	request.ExecutionId = uuid.MustParse("0a1b2c3d-0000-0000-0000-000000000000")
+	result, err := action.Prepare(context.Background(), &state, request)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	assert.Equal(t, map[string]string{"cpu.max": "50000 100000", "memory.high": "268435456", "io.max": "8:0 rbps=1048576"}, state.Limits)
+
+	start, err := action.Start(context.Background(), &state)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/system.slice/orders.service"}, state.Cgroups)
+	assert.Equal(t, []string{"/system.slice/orders.service"}, state.Delegated)
+	assert.Equal(t, map[string][]string{"/system.slice/orders.service": {"cpu", "io"}}, state.EnabledControllers)
+	assert.Equal(t, "Applied cpu.max=50000 100000, io.max=8:0 rbps=1048576, memory.high=268435456 to 2 processes in /system.slice/orders.service/steadybit-throttled-0a1b2c3d", (*start.Messages)[0].Message)
+	assert.Equal(t, map[string][]int{
+		"/system.slice/orders.service":                              {},
+		"/system.slice/orders.service/steadybit-throttled-0a1b2c3d": {10, 11},
+		"/system.slice/orders.service/steadybit-unlimited-0a1b2c3d": {12},
+	}, fake.procs)
+	assert.Equal(t, "memory cpu io", fake.files["/system.slice/orders.service/cgroup.subtree_control"])
+	assert.Equal(t, "50000 100000", fake.files["/system.slice/orders.service/steadybit-throttled-0a1b2c3d/cpu.max"])
+	assert.NotContains(t, fake.files, "/system.slice/orders.service/cpu.max")
+
+	status, err := action.Status(context.Background(), &state)
+	require.NoError(t, err)
+	require.Len(t, *status.Metrics, 5)
+	assert.Equal(t, "cpu.stat nr_throttled", (*status.Metrics)[0].Metric["counter"])
+	assert.Equal(t, "/system.slice/orders.service/steadybit-throttled-0a1b2c3d", (*status.Metrics)[0].Metric["cgroup"])
+	assert.Equal(t, float64(7), (*status.Metrics)[0].Value)
+
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+	assert.False(t, state.Applied)
+	assert.Equal(t, map[string][]int{"/system.slice/orders.service": {10, 11, 12}}, fake.procs)
+	assert.Equal(t, "memory", fake.files["/system.slice/orders.service/cgroup.subtree_control"])
+
+	// the journal replay may stop it again
+	state.Applied = true
+	_, err = action.Stop(context.Background(), &state)
+	require.NoError(t, err)
+}
+
+func TestActionThrottleProcess_RequiresLimit(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeCgroups{files: map[string]string{}, procs: map[string][]int{}}
+	fake.install(t, nil)
+
+	action := &throttleProcessAction{}
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, throttleProcessRequest(map[string]any{"duration": "10000", "process": "orders"}))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "At least one limit is required", result.Error.Title)
+
+	cgroupVersion = func() (string, error) { return "v1", nil }
+	result, err = action.Prepare(context.Background(), &state, throttleProcessRequest(map[string]any{"duration": "10000", "process": "orders", "pidsMax": 10}))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "Throttling processes requires cgroup v2", result.Error.Title)
+}
+
+func TestActionThrottleProcess_RefusesProtectedCgroup(t *testing.T) {
+	osHostname = func() (string, error) { return "myhostname", nil }
+	fake := &fakeCgroups{files: map[string]string{}, procs: map[string][]int{"/init.scope": {1, 400}}, delegated: []string{"/init.scope"}}
+	fake.install(t, []stopprocess.Process{{Pid: 400, Name: "helper", Cgroup: "/init.scope"}})
+	findProtectedProcesses = func(stopprocess.Selector) ([]stopprocess.Process, error) {
+		return []stopprocess.Process{{Pid: 1, Name: "systemd"}}, nil
+	}
+
+	action := &throttleProcessAction{}
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, throttleProcessRequest(map[string]any{"duration": "10000", "process": "helper", "pidsMax": 10}))
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	_, err = action.Start(context.Background(), &state)
+	assert.EqualError(t, err, "cgroup /init.scope contains the protected process 1 (systemd)")
+	assert.Equal(t, map[string][]int{"/init.scope": {1, 400}}, fake.procs)
+}
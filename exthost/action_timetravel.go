@@ -6,6 +6,7 @@ package exthost
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
@@ -23,9 +24,12 @@ type timeTravelAction struct {
 }
 
 type TimeTravelActionState struct {
+	ExecutionId   uuid.UUID
 	DisableNtp    bool
 	Offset        time.Duration
 	OffsetApplied bool
+	// Reference is taken before the time is adjusted, so Stop can tell whether the time is still shifted
+	Reference timetravel.Reference
 }
 
 // Make sure action implements all required interfaces
@@ -35,7 +39,7 @@ var (
 )
 
 func NewTimetravelAction(r ociruntime.OciRuntime) action_kit_sdk.Action[TimeTravelActionState] {
-	return &timeTravelAction{runc: r}
+	return journaled[TimeTravelActionState](&timeTravelAction{runc: r})
 }
 
 func (a *timeTravelAction) NewEmptyState() TimeTravelActionState {
@@ -52,7 +56,7 @@ func (a *timeTravelAction) Describe() action_kit_api.ActionDescription {
 		Icon:        new(timeTravelIcon),
 		TargetSelection: new(action_kit_api.TargetSelection{
 			// The target type this action is for
-			TargetType: targetID,
+			TargetType: requireCapability(capabilitySysTime),
 			// You can provide a list of target templates to help the user select targets.
 			// A template can be used to pre-fill a selection
 			SelectionTemplates: &targetSelectionTemplates,
@@ -131,6 +135,7 @@ func (a *timeTravelAction) Prepare(_ context.Context, state *TimeTravelActionSta
 		}, nil
 	}
 	state.DisableNtp = extutil.ToBool(request.Config["disableNtp"])
+	state.ExecutionId = request.ExecutionId
 
 	return nil, nil
 }
@@ -139,6 +144,18 @@ func (a *timeTravelAction) Prepare(_ context.Context, state *TimeTravelActionSta
 // You can mutate the state here.
 // You can use the result to return messages/errors/metrics or artifacts
 func (a *timeTravelAction) Start(ctx context.Context, state *TimeTravelActionState) (*action_kit_api.StartResult, error) {
+	reference, err := timetravel.NewReference()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read the boot time")
+		return nil, err
+	}
+	state.Reference = reference
+
+	// Journal before applying, so even a partially applied time travel is reverted after a crash. Stop only
+	// adjusts the time back if it is still shifted.
+	state.OffsetApplied = true
+	recordExecution(state.ExecutionId, timeTravelActionID, state)
+
 	if state.DisableNtp {
 		log.Info().Msg("Blocking NTP traffic")
 		runner, err := a.runner(ctx)
@@ -158,8 +175,6 @@ func (a *timeTravelAction) Start(ctx context.Context, state *TimeTravelActionSta
 		log.Error().Err(err).Msg("Failed to adjust time")
 		return nil, err
 	}
-
-	state.OffsetApplied = true
 	return nil, nil
 }
 
@@ -188,11 +203,24 @@ func (a *timeTravelAction) Stop(ctx context.Context, state *TimeTravelActionStat
 		}
 	}
 
-	if err := timetravel.AdjustTime(state.Offset, true); err != nil {
+	// the time may already be back, e.g. if the execution was reverted from the journal before
+	shifted := true
+	if !state.Reference.Wall.IsZero() {
+		shift, err := state.Reference.Shift()
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to read the boot time")
+			return nil, err
+		}
+		shifted = shift >= state.Offset/2
+	}
+	if !shifted {
+		log.Info().Msg("Time is not shifted anymore, skipping revert")
+	} else if err := timetravel.AdjustTime(state.Offset, true); err != nil {
 		log.Error().Err(err).Msg("Failed to revert time adjustment")
 		return nil, err
 	}
 	state.OffsetApplied = false
+	forgetExecution(state.ExecutionId)
 	return nil, nil
 }
 
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/conntrack"
+	"github.com/steadybit/extension-host/exthost/cpufreq"
+	"github.com/steadybit/extension-host/exthost/hostinfo"
+	"github.com/steadybit/extension-host/exthost/shutdown"
+)
+
+// Actions require the capabilities they depend on in their TargetType, e.g. targetID+"(host.capability.tc)",
+// so the platform doesn't offer them for hosts where they can only fail.
+const (
+	capabilityStressNg   = "host.capability.stress_ng"
+	capabilityTc         = "host.capability.tc"
+	capabilityIptables   = "host.capability.iptables"
+	capabilityIp6tables  = "host.capability.ip6tables"
+	capabilityDig        = "host.capability.dig"
+	capabilityCpufreq    = "host.capability.cpufreq"
+	capabilitySysTime    = "host.capability.sys_time"
+	capabilityOciRuntime = "host.capability.oci_runtime"
+	capabilityShutdown   = "host.capability.shutdown"
+	capabilityConntrack  = "host.capability.conntrack"
+	capabilityNetAdmin   = "host.capability.net_admin"
+)
+
+// capabilityProbes return the attribute value and whether the capability is available. Unavailable
+// capabilities are omitted from the attributes.
+var capabilityProbes = map[string]func() (string, bool){
+	capabilityStressNg:  cachedProbe(func() (string, bool) { return "true", isStressNgInstalled() }),
+	capabilityTc:        executableProbe("tc"),
+	capabilityIptables:  executableProbe("iptables"),
+	capabilityIp6tables: executableProbe("ip6tables"),
+	capabilityDig:       executableProbe("dig"),
+	capabilityCpufreq:   func() (string, bool) { return "true", cpufreq.IsWritable() },
+	capabilitySysTime: func() (string, bool) {
+		ok, err := hostinfo.HasEffectiveCapability(hostinfo.CapSysTime)
+		return "true", err == nil && ok
+	},
+	capabilityOciRuntime: func() (string, bool) {
+		for _, runtime := range []string{"crun", "runc"} {
+			if _, err := exec.LookPath(runtime); err == nil {
+				return runtime, true
+			}
+		}
+		return "", false
+	},
+	capabilityShutdown: cachedProbe(func() (string, bool) {
+		if s := shutdown.Get(); s != nil {
+			return s.Name(), true
+		}
+		return "", false
+	}),
+	capabilityConntrack: func() (string, bool) {
+		_, err := os.Stat(conntrack.ProcConntrackMax)
+		return "true", err == nil
+	},
+	capabilityNetAdmin: func() (string, bool) {
+		ok, err := hostinfo.HasEffectiveCapability(hostinfo.CapNetAdmin)
+		return "true", err == nil && ok
+	},
+}
+
+var (
+	lastCapabilities   map[string]string
+	lastCapabilitiesMu sync.Mutex
+)
+
+// capabilityProbeRefreshInterval limits how often the probes starting processes are run. Their result
+// only changes if the host is reconfigured, so they don't need to run on every discovery refresh.
+const capabilityProbeRefreshInterval = 10 * time.Minute
+
+func cachedProbe(probe func() (string, bool)) func() (string, bool) {
+	var (
+		mu       sync.Mutex
+		probedAt time.Time
+		value    string
+		ok       bool
+	)
+	return func() (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if probedAt.IsZero() || time.Since(probedAt) >= capabilityProbeRefreshInterval {
+			value, ok = probe()
+			probedAt = time.Now()
+		}
+		return value, ok
+	}
+}
+
+func executableProbe(name string) func() (string, bool) {
+	return func() (string, bool) {
+		_, err := exec.LookPath(name)
+		return "true", err == nil
+	}
+}
+
+// ProbeCapabilities checks which attacks can be executed on this host and returns the available
+// capabilities as attributes. Changes compared to the previous probe are logged.
+func ProbeCapabilities() map[string][]string {
+	capabilities := map[string]string{}
+	for name, probe := range capabilityProbes {
+		if value, ok := probe(); ok {
+			capabilities[name] = value
+		}
+	}
+
+	lastCapabilitiesMu.Lock()
+	if !maps.Equal(capabilities, lastCapabilities) {
+		var missing []string
+		for name := range capabilityProbes {
+			if _, ok := capabilities[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		slices.Sort(missing)
+		log.Info().Any("capabilities", capabilities).Strs("missing", missing).Msg("Probed host capabilities")
+		lastCapabilities = capabilities
+	}
+	lastCapabilitiesMu.Unlock()
+
+	attributes := make(map[string][]string, len(capabilities))
+	for name, value := range capabilities {
+		attributes[name] = []string{value}
+	}
+	return attributes
+}
+
+func requireCapability(capabilities ...string) string {
+	return targetID + "(" + strings.Join(capabilities, ",") + ")"
+}
+
+// requireOciRuntime is used by the actions running a holder in a sidecar. With runc disabled the holder
+// runs as a child of the extension, so no runtime is needed.
+func requireOciRuntime() string {
+	if config.Config.DisableRunc {
+		return targetID
+	}
+	return requireCapability(capabilityOciRuntime)
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/steadybit/extension-host/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ProbeCapabilities(t *testing.T) {
+	original := capabilityProbes
+	capabilityProbes = map[string]func() (string, bool){
+		capabilityTc:         func() (string, bool) { return "true", true },
+		capabilityIptables:   func() (string, bool) { return "true", false },
+		capabilityOciRuntime: func() (string, bool) { return "crun", true },
+		capabilityShutdown:   func() (string, bool) { return "", false },
+	}
+	defer func() { capabilityProbes = original }()
+
+	attributes := ProbeCapabilities()
+
+	assert.Equal(t, map[string][]string{
+		capabilityTc:         {"true"},
+		capabilityOciRuntime: {"crun"},
+	}, attributes)
+	assert.True(t, maps.Equal(map[string]string{capabilityTc: "true", capabilityOciRuntime: "crun"}, lastCapabilities))
+}
+
+func Test_requireCapability(t *testing.T) {
+	assert.Equal(t, "com.steadybit.extension_host.host(host.capability.tc)", requireCapability(capabilityTc))
+	assert.Equal(t, requireCapability(capabilityStressNg), NewStressCpuAction(nil).Describe().TargetSelection.TargetType)
+	assert.Equal(t, requireCapability(capabilityCpufreq), NewCpuSpeedAction().Describe().TargetSelection.TargetType)
+	assert.Equal(t, "com.steadybit.extension_host.host(host.capability.iptables,host.capability.ip6tables,host.capability.dig)", NewNetworkBlackholeContainerAction(nil).Describe().TargetSelection.TargetType)
+	assert.Equal(t, requireCapability(capabilityIptables, capabilityIp6tables), NewNetworkBlockDnsContainerAction(nil).Describe().TargetSelection.TargetType)
+	assert.Equal(t, requireCapability(capabilityTc, capabilityDig), NewNetworkDelayContainerAction(nil).Describe().TargetSelection.TargetType)
+	assert.Equal(t, requireCapability(capabilityOciRuntime), NewNetworkDNSErrorInjectionAction(nil).Describe().TargetSelection.TargetType)
+	assert.Equal(t, requireCapability(capabilityNetAdmin), NewNetworkRouteAction().Describe().TargetSelection.TargetType)
+}
+
+func Test_cachedProbe(t *testing.T) {
+	calls := 0
+	probe := cachedProbe(func() (string, bool) {
+		calls++
+		return "systemctl", true
+	})
+
+	for range 3 {
+		value, ok := probe()
+		assert.Equal(t, "systemctl", value)
+		assert.True(t, ok)
+	}
+	assert.Equal(t, 1, calls)
+}
+
+func Test_requireOciRuntime(t *testing.T) {
+	defer func() { config.Config.DisableRunc = false }()
+
+	config.Config.DisableRunc = false
+	assert.Equal(t, requireCapability(capabilityOciRuntime), NewFillFdAction(nil).Describe().TargetSelection.TargetType)
+	assert.Equal(t, requireCapability(capabilityOciRuntime), NewNetworkLinkDownAction(nil).Describe().TargetSelection.TargetType)
+
+	config.Config.DisableRunc = true
+	assert.Equal(t, targetID, NewFillPidsAction(nil).Describe().TargetSelection.TargetType)
+	assert.Equal(t, targetID, NewNetworkExhaustPortsAction(nil).Describe().TargetSelection.TargetType)
+}
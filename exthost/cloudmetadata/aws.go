@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package cloudmetadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	awsTokenPath    = "/latest/api/token"
+	awsIdentityPath = "/latest/dynamic/instance-identity/document"
+	awsTagsPath     = "/latest/meta-data/tags/instance"
+	awsTokenTTL     = "60"
+)
+
+type awsIdentityDocument struct {
+	AccountId        string `json:"accountId"`
+	AvailabilityZone string `json:"availabilityZone"`
+	Region           string `json:"region"`
+	InstanceId       string `json:"instanceId"`
+	InstanceType     string `json:"instanceType"`
+}
+
+// fetchAWS uses the IMDSv2 session token flow. Instance tags are only served if the instance has
+// "tags in instance metadata" enabled; otherwise they are skipped.
+func (c *Client) fetchAWS(ctx context.Context) (*Metadata, error) {
+	token, err := c.get(ctx, http.MethodPut, awsTokenPath, map[string]string{
+		"X-aws-ec2-metadata-token-ttl-seconds": awsTokenTTL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("aws: failed to get token: %w", err)
+	}
+	header := map[string]string{"X-aws-ec2-metadata-token": string(token)}
+
+	body, err := c.get(ctx, http.MethodGet, awsIdentityPath, header)
+	if err != nil {
+		return nil, fmt.Errorf("aws: %w", err)
+	}
+
+	var doc awsIdentityDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("aws: failed to parse identity document: %w", err)
+	}
+
+	metadata := &Metadata{
+		Provider:     ProviderAWS,
+		Zone:         doc.AvailabilityZone,
+		Region:       doc.Region,
+		InstanceId:   doc.InstanceId,
+		InstanceType: doc.InstanceType,
+		AccountId:    doc.AccountId,
+	}
+
+	if keys, err := c.get(ctx, http.MethodGet, awsTagsPath, header); err == nil {
+		metadata.Tags = map[string]string{}
+		for _, key := range sortedLines(string(keys)) {
+			if value, err := c.get(ctx, http.MethodGet, awsTagsPath+"/"+url.PathEscape(key), header); err == nil {
+				metadata.Tags[key] = string(value)
+			}
+		}
+	}
+	return metadata, nil
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package cloudmetadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const azureInstancePath = "/metadata/instance?api-version=2021-02-01"
+
+var azureHeader = map[string]string{"Metadata": "true"}
+
+type azureInstance struct {
+	Compute struct {
+		Location       string `json:"location"`
+		Zone           string `json:"zone"`
+		VmId           string `json:"vmId"`
+		VmSize         string `json:"vmSize"`
+		SubscriptionId string `json:"subscriptionId"`
+		TagsList       []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"tagsList"`
+	} `json:"compute"`
+}
+
+func (c *Client) fetchAzure(ctx context.Context) (*Metadata, error) {
+	body, err := c.get(ctx, http.MethodGet, azureInstancePath, azureHeader)
+	if err != nil {
+		return nil, fmt.Errorf("azure: %w", err)
+	}
+
+	var instance azureInstance
+	if err := json.Unmarshal(body, &instance); err != nil {
+		return nil, fmt.Errorf("azure: failed to parse instance metadata: %w", err)
+	}
+
+	compute := instance.Compute
+	metadata := &Metadata{
+		Provider:     ProviderAzure,
+		Region:       compute.Location,
+		InstanceId:   compute.VmId,
+		InstanceType: compute.VmSize,
+		AccountId:    compute.SubscriptionId,
+	}
+	// Azure reports the zone as plain number; prefix it with the region like the well-known
+	// topology.kubernetes.io/zone label does (e.g. westeurope-1).
+	if compute.Zone != "" {
+		metadata.Zone = fmt.Sprintf("%s-%s", compute.Location, compute.Zone)
+	}
+	if len(compute.TagsList) > 0 {
+		metadata.Tags = map[string]string{}
+		for _, tag := range compute.TagsList {
+			metadata.Tags[tag.Name] = tag.Value
+		}
+	}
+	return metadata, nil
+}
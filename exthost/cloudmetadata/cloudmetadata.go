@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package cloudmetadata reads the instance metadata services (IMDS) of AWS, GCP and Azure. All three
+// providers serve their IMDS on the same link-local address, so a single base URL is probed with the
+// protocol of each provider.
+package cloudmetadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBaseURL = "http://169.254.169.254"
+	DefaultTimeout = 2 * time.Second
+
+	ProviderAWS   = "aws"
+	ProviderGCP   = "gcp"
+	ProviderAzure = "azure"
+
+	maxResponseSize = 1 << 20
+)
+
+var ErrNoCloudMetadata = errors.New("no cloud instance metadata available")
+
+// Metadata is the provider-independent subset of the instance metadata.
+type Metadata struct {
+	Provider     string
+	Zone         string
+	Region       string
+	InstanceId   string
+	InstanceType string
+	// AccountId is the AWS account id, the GCP project id or the Azure subscription id.
+	AccountId string
+	Tags      map[string]string
+}
+
+// Attributes maps the metadata to discovery attributes, prefixed with the provider, e.g. aws.zone.
+func (m *Metadata) Attributes() map[string][]string {
+	attributes := map[string][]string{}
+	add := func(key, value string) {
+		if value != "" {
+			attributes[m.Provider+"."+key] = []string{value}
+		}
+	}
+	add("zone", m.Zone)
+	add("region", m.Region)
+	add("instance.id", m.InstanceId)
+	add("instance.type", m.InstanceType)
+	add(accountAttribute[m.Provider], m.AccountId)
+	for key, value := range m.Tags {
+		add("tag."+key, value)
+	}
+	return attributes
+}
+
+var accountAttribute = map[string]string{
+	ProviderAWS:   "account",
+	ProviderGCP:   "project.id",
+	ProviderAzure: "subscription.id",
+}
+
+type Client struct {
+	baseURL string
+	timeout time.Duration
+	http    *http.Client
+}
+
+// NewClient creates a client for the IMDS at baseURL. Fetching is limited to timeout, so hosts
+// outside a cloud are not held up by the unreachable link-local address.
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		timeout: timeout,
+		http: &http.Client{
+			// IMDS must never be reached through a proxy
+			Transport: &http.Transport{Proxy: nil},
+		},
+	}
+}
+
+type probe func(ctx context.Context) (*Metadata, error)
+
+// Fetch probes all providers concurrently and returns the metadata of the first one responding.
+// ErrNoCloudMetadata is returned if none does.
+func (c *Client) Fetch(ctx context.Context) (*Metadata, error) {
+	probes := []probe{c.fetchAWS, c.fetchGCP, c.fetchAzure}
+
+	type result struct {
+		metadata *Metadata
+		err      error
+	}
+	results := make(chan result, len(probes))
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	for _, p := range probes {
+		go func() {
+			metadata, err := p(ctx)
+			results <- result{metadata, err}
+		}()
+	}
+
+	var errs []error
+	for range probes {
+		r := <-results
+		if r.err == nil {
+			return r.metadata, nil
+		}
+		errs = append(errs, r.err)
+	}
+	return nil, fmt.Errorf("%w: %w", ErrNoCloudMetadata, errors.Join(errs...))
+}
+
+func (c *Client) get(ctx context.Context, method, path string, header map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: unexpected status %d", method, path, res.StatusCode)
+	}
+	return body, nil
+}
+
+// lastSegment returns the part after the last slash, e.g. the zone of "projects/1/zones/europe-west1-b".
+func lastSegment(s string) string {
+	return s[strings.LastIndex(s, "/")+1:]
+}
+
+func sortedLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
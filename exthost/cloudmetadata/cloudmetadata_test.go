@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package cloudmetadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func awsHandler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds"))
+		_, _ = w.Write([]byte("session-token"))
+	})
+	requireToken := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-aws-ec2-metadata-token") != "session-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+		}
+	}
+	mux.HandleFunc("GET /latest/dynamic/instance-identity/document", requireToken(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"accountId":"123456789012","availabilityZone":"eu-central-1a","region":"eu-central-1","instanceId":"i-0abc","instanceType":"m5.large"}`))
+	}))
+	mux.HandleFunc("GET /latest/meta-data/tags/instance", requireToken(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("Name\nteam\n"))
+	}))
+	mux.HandleFunc("GET /latest/meta-data/tags/instance/{key}", requireToken(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(map[string]string{"Name": "web-1", "team": "shop"}[r.PathValue("key")]))
+	}))
+	return mux
+}
+
+func gcpHandler() http.Handler {
+	mux := http.NewServeMux()
+	requireFlavor := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Metadata-Flavor") != "Google" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next(w, r)
+		}
+	}
+	mux.HandleFunc("GET /computeMetadata/v1/instance/", requireFlavor(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":4520031799277581759,"zone":"projects/9876/zones/europe-west1-b","machineType":"projects/9876/machineTypes/e2-medium","tags":["http-server"]}`))
+	}))
+	mux.HandleFunc("GET /computeMetadata/v1/project/project-id", requireFlavor(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("my-project"))
+	}))
+	return mux
+}
+
+func azureHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metadata/instance", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" || r.URL.Query().Get("api-version") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"compute":{"location":"westeurope","zone":"2","vmId":"02aab8a4-74ef-476e-8182-f6d2ba4166a6","vmSize":"Standard_D2s_v3","subscriptionId":"8d10da13-8125-4ba9-a717-bf7490507b3d","tagsList":[{"name":"env","value":"prod"}]}}`))
+	})
+	return mux
+}
+
+func TestClient_Fetch(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.Handler
+		want    *Metadata
+		attrs   map[string][]string
+	}{
+		{
+			name:    "aws",
+			handler: awsHandler(t),
+			want: &Metadata{
+				Provider:     ProviderAWS,
+				Zone:         "eu-central-1a",
+				Region:       "eu-central-1",
+				InstanceId:   "i-0abc",
+				InstanceType: "m5.large",
+				AccountId:    "123456789012",
+				Tags:         map[string]string{"Name": "web-1", "team": "shop"},
+			},
+			attrs: map[string][]string{
+				"aws.zone":          {"eu-central-1a"},
+				"aws.region":        {"eu-central-1"},
+				"aws.instance.id":   {"i-0abc"},
+				"aws.instance.type": {"m5.large"},
+				"aws.account":       {"123456789012"},
+				"aws.tag.Name":      {"web-1"},
+				"aws.tag.team":      {"shop"},
+			},
+		},
+		{
+			name:    "gcp",
+			handler: gcpHandler(),
+			want: &Metadata{
+				Provider:     ProviderGCP,
+				Zone:         "europe-west1-b",
+				Region:       "europe-west1",
+				InstanceId:   "4520031799277581759",
+				InstanceType: "e2-medium",
+				AccountId:    "my-project",
+				Tags:         map[string]string{"http-server": "true"},
+			},
+			attrs: map[string][]string{
+				"gcp.zone":            {"europe-west1-b"},
+				"gcp.region":          {"europe-west1"},
+				"gcp.instance.id":     {"4520031799277581759"},
+				"gcp.instance.type":   {"e2-medium"},
+				"gcp.project.id":      {"my-project"},
+				"gcp.tag.http-server": {"true"},
+			},
+		},
+		{
+			name:    "azure",
+			handler: azureHandler(),
+			want: &Metadata{
+				Provider:     ProviderAzure,
+				Zone:         "westeurope-2",
+				Region:       "westeurope",
+				InstanceId:   "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+				InstanceType: "Standard_D2s_v3",
+				AccountId:    "8d10da13-8125-4ba9-a717-bf7490507b3d",
+				Tags:         map[string]string{"env": "prod"},
+			},
+			attrs: map[string][]string{
+				"azure.zone":            {"westeurope-2"},
+				"azure.region":          {"westeurope"},
+				"azure.instance.id":     {"02aab8a4-74ef-476e-8182-f6d2ba4166a6"},
+				"azure.instance.type":   {"Standard_D2s_v3"},
+				"azure.subscription.id": {"8d10da13-8125-4ba9-a717-bf7490507b3d"},
+				"azure.tag.env":         {"prod"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			metadata, err := NewClient(server.URL, DefaultTimeout).Fetch(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, metadata)
+			assert.Equal(t, tt.attrs, metadata.Attributes())
+		})
+	}
+}
+
+func TestClient_FetchAwsWithoutInstanceTags(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /latest/api/token", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("token"))
+	})
+	mux.HandleFunc("GET /latest/dynamic/instance-identity/document", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"availabilityZone":"us-east-1a","region":"us-east-1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	metadata, err := NewClient(server.URL, DefaultTimeout).Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1a", metadata.Zone)
+	assert.Nil(t, metadata.Tags)
+}
+
+func TestClient_FetchNoCloud(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := NewClient(server.URL, DefaultTimeout).Fetch(context.Background())
+	assert.ErrorIs(t, err, ErrNoCloudMetadata)
+}
+
+func TestClient_FetchTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := NewClient(server.URL, 100*time.Millisecond).Fetch(context.Background())
+	assert.ErrorIs(t, err, ErrNoCloudMetadata)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package cloudmetadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	gcpInstancePath  = "/computeMetadata/v1/instance/?recursive=true"
+	gcpProjectIdPath = "/computeMetadata/v1/project/project-id"
+)
+
+var gcpHeader = map[string]string{"Metadata-Flavor": "Google"}
+
+type gcpInstance struct {
+	Id          json.Number `json:"id"`
+	Zone        string      `json:"zone"`
+	MachineType string      `json:"machineType"`
+	// Tags are the network tags of the instance. The metadata server doesn't expose labels.
+	Tags []string `json:"tags"`
+}
+
+func (c *Client) fetchGCP(ctx context.Context) (*Metadata, error) {
+	body, err := c.get(ctx, http.MethodGet, gcpInstancePath, gcpHeader)
+	if err != nil {
+		return nil, fmt.Errorf("gcp: %w", err)
+	}
+
+	var instance gcpInstance
+	if err := json.Unmarshal(body, &instance); err != nil {
+		return nil, fmt.Errorf("gcp: failed to parse instance metadata: %w", err)
+	}
+
+	projectId, err := c.get(ctx, http.MethodGet, gcpProjectIdPath, gcpHeader)
+	if err != nil {
+		return nil, fmt.Errorf("gcp: %w", err)
+	}
+
+	zone := lastSegment(instance.Zone)
+	metadata := &Metadata{
+		Provider:     ProviderGCP,
+		Zone:         zone,
+		InstanceId:   instance.Id.String(),
+		InstanceType: lastSegment(instance.MachineType),
+		AccountId:    string(projectId),
+	}
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		metadata.Region = zone[:i]
+	}
+	if len(instance.Tags) > 0 {
+		metadata.Tags = map[string]string{}
+		for _, tag := range instance.Tags {
+			metadata.Tags[tag] = "true"
+		}
+	}
+	return metadata, nil
+}
@@ -27,7 +27,17 @@ const (
 	stressIOIcon     = "data:image/svg+xml,%3Csvg%20width%3D%2224%22%20height%3D%2224%22%20viewBox%3D%220%200%2024%2024%22%20fill%3D%22none%22%20xmlns%3D%22http%3A%2F%2Fwww.w3.org%2F2000%2Fsvg%22%3E%0A%3Cpath%20d%3D%22M18.375%2017.625C18.3008%2017.625%2018.2283%2017.647%2018.1667%2017.6882C18.105%2017.7294%2018.0569%2017.788%2018.0285%2017.8565C18.0002%2017.925%2017.9927%2018.0004%2018.0072%2018.0732C18.0217%2018.1459%2018.0574%2018.2127%2018.1098%2018.2652C18.1623%2018.3176%2018.2291%2018.3533%2018.3018%2018.3678C18.3746%2018.3823%2018.45%2018.3748%2018.5185%2018.3465C18.587%2018.3181%2018.6456%2018.27%2018.6868%2018.2083C18.728%2018.1467%2018.75%2018.0742%2018.75%2018C18.75%2017.9005%2018.7105%2017.8052%2018.6402%2017.7348C18.5698%2017.6645%2018.4745%2017.625%2018.375%2017.625Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M15%2017.625C14.9258%2017.625%2014.8533%2017.647%2014.7917%2017.6882C14.73%2017.7294%2014.6819%2017.788%2014.6535%2017.8565C14.6252%2017.925%2014.6177%2018.0004%2014.6322%2018.0732C14.6467%2018.1459%2014.6824%2018.2127%2014.7348%2018.2652C14.7873%2018.3176%2014.8541%2018.3533%2014.9268%2018.3678C14.9996%2018.3823%2015.075%2018.3748%2015.1435%2018.3465C15.212%2018.3181%2015.2706%2018.27%2015.3118%2018.2083C15.353%2018.1467%2015.375%2018.0742%2015.375%2018C15.375%2017.9005%2015.3355%2017.8052%2015.2652%2017.7348C15.1948%2017.6645%2015.0995%2017.625%2015%2017.625Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M14.375%2017.0646C14.56%2016.941%2014.7775%2016.875%2015%2016.875C15.2984%2016.875%2015.5845%2016.9935%2015.7955%2017.2045C16.0065%2017.4155%2016.125%2017.7016%2016.125%2018C16.125%2018.2225%2016.059%2018.44%2015.9354%2018.625C15.8118%2018.81%2015.6361%2018.9542%2015.4305%2019.0394C15.225%2019.1245%2014.9988%2019.1468%2014.7805%2019.1034C14.5623%2019.06%2014.3618%2018.9528%2014.2045%2018.7955C14.0472%2018.6382%2013.94%2018.4377%2013.8966%2018.2195C13.8532%2018.0012%2013.8755%2017.775%2013.9606%2017.5695C14.0458%2017.3639%2014.19%2017.1882%2014.375%2017.0646ZM15.1435%2018.3465C15.1661%2018.3371%2015.1878%2018.3255%2015.2083%2018.3118C15.2495%2018.2843%2015.2846%2018.2491%2015.3118%2018.2083C15.3254%2018.188%2015.337%2018.1663%2015.3465%2018.1435C15.3654%2018.0978%2015.375%2018.049%2015.375%2018C15.375%2017.9756%2015.3726%2017.951%2015.3678%2017.9268C15.3533%2017.8541%2015.3176%2017.7873%2015.2652%2017.7348C15.2127%2017.6824%2015.1459%2017.6467%2015.0732%2017.6322C15.0489%2017.6274%2015.0244%2017.625%2015%2017.625C14.951%2017.625%2014.9022%2017.6346%2014.8565%2017.6535C14.8337%2017.663%2014.812%2017.6746%2014.7917%2017.6882C14.7509%2017.7154%2014.7157%2017.7505%2014.6882%2017.7917C14.6745%2017.8122%2014.6629%2017.8339%2014.6535%2017.8565C14.6348%2017.9018%2014.625%2017.9505%2014.625%2018C14.625%2018.0247%2014.6274%2018.0492%2014.6322%2018.0732C14.6467%2018.1459%2014.6824%2018.2127%2014.7348%2018.2652C14.7873%2018.3176%2014.8541%2018.3533%2014.9268%2018.3678C14.9508%2018.3726%2014.9753%2018.375%2015%2018.375C15.0495%2018.375%2015.0982%2018.3652%2015.1435%2018.3465Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M5.25%2014.25C4.25544%2014.25%203.30161%2014.6451%202.59835%2015.3484C1.89509%2016.0516%201.5%2017.0054%201.5%2018C1.5%2018.9946%201.89509%2019.9484%202.59835%2020.6516C3.30161%2021.3549%204.25544%2021.75%205.25%2021.75H18.75C19.7446%2021.75%2020.6984%2021.3549%2021.4016%2020.6516C22.1049%2019.9484%2022.5%2018.9946%2022.5%2018C22.5%2017.0054%2022.1049%2016.0516%2021.4016%2015.3484C20.6984%2014.6451%2019.7446%2014.25%2018.75%2014.25H5.25ZM1.53769%2014.2877C2.52226%2013.3031%203.85761%2012.75%205.25%2012.75H18.75C20.1424%2012.75%2021.4777%2013.3031%2022.4623%2014.2877C23.4469%2015.2723%2024%2016.6076%2024%2018C24%2019.3924%2023.4469%2020.7277%2022.4623%2021.7123C21.4777%2022.6969%2020.1424%2023.25%2018.75%2023.25H5.25C3.85761%2023.25%202.52226%2022.6969%201.53769%2021.7123C0.553123%2020.7277%200%2019.3924%200%2018C0%2016.6076%200.553123%2015.2723%201.53769%2014.2877Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M6.87806%200.75C6.87804%200.75%206.87808%200.75%206.87806%200.75H17.123C17.9685%200.750211%2018.7894%201.03617%2019.4519%201.56146C20.1145%202.08673%2020.5801%202.82048%2020.7732%203.64364C20.7732%203.6436%2020.7732%203.64368%2020.7732%203.64364L23.8612%2016.8016C23.9558%2017.2049%2023.7056%2017.6085%2023.3024%2017.7032C22.8991%2017.7978%2022.4955%2017.5476%2022.4008%2017.1444L19.3128%203.98636C19.197%203.49244%2018.9176%203.05205%2018.5201%202.73688C18.1226%202.42174%2017.6303%202.25017%2017.123%202.25C17.1229%202.25%2017.1231%202.25%2017.123%202.25H6.878C6.37055%202.24996%205.87792%202.42145%205.48022%202.73664C5.08253%203.05183%204.80306%203.4922%204.68719%203.98625L1.59916%2017.1444C1.50452%2017.5476%201.1009%2017.7978%200.697641%2017.7032C0.294384%2017.6085%200.0441994%2017.2049%200.138838%2016.8016L3.22681%203.64375C3.2268%203.64379%203.22682%203.64371%203.22681%203.64375C3.41994%202.82038%203.88574%202.08637%204.54854%201.56107C5.21135%201.03577%206.03233%200.749943%206.87806%200.75Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M4.5%2018C4.5%2017.5858%204.83579%2017.25%205.25%2017.25H9C9.41421%2017.25%209.75%2017.5858%209.75%2018C9.75%2018.4142%209.41421%2018.75%209%2018.75H5.25C4.83579%2018.75%204.5%2018.4142%204.5%2018Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3C%2Fsvg%3E%0A"
 	stressMemoryIcon = "data:image/svg+xml,%3Csvg%20width%3D%2224%22%20height%3D%2224%22%20viewBox%3D%220%200%2024%2024%22%20fill%3D%22none%22%20xmlns%3D%22http%3A%2F%2Fwww.w3.org%2F2000%2Fsvg%22%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M17.1063%201.49823C16.9943%201.49823%2016.8834%201.52037%2016.7799%201.56338C16.6765%201.6064%2016.5826%201.66943%2016.5036%201.74886L16.5019%201.75054L10.5432%207.70453L10.5609%207.78931C10.6379%208.16975%2010.6196%208.56331%2010.5077%208.93498C10.3958%209.30665%2010.1938%209.64491%209.91967%209.91966C9.6455%2010.1944%209.30767%2010.3971%208.93624%2010.5098C8.56481%2010.6225%208.17129%2010.6416%207.79069%2010.5654L7.78392%2010.5641L7.70419%2010.5473L1.75019%2016.5023L1.74867%2016.5038C1.66924%2016.5828%201.60621%2016.6767%201.5632%2016.7801C1.52019%2016.8836%201.49805%2016.9945%201.49805%2017.1065C1.49805%2017.2185%201.52019%2017.3294%201.5632%2017.4329C1.60621%2017.5363%201.66924%2017.6302%201.74867%2017.7092L1.75015%2017.7107L6.29061%2022.2511C6.3696%2022.3306%206.46351%2022.3936%206.56695%2022.4366C6.67038%2022.4796%206.78129%2022.5018%206.89331%2022.5018C7.00534%2022.5018%207.11625%2022.4796%207.21968%2022.4366C7.32312%2022.3936%207.41703%2022.3306%207.49602%2022.2511L7.49748%2022.2497L22.2495%207.49767L22.251%207.4962C22.3304%207.41721%2022.3934%207.3233%2022.4364%207.21987C22.4794%207.11644%2022.5016%207.00552%2022.5016%206.8935C22.5016%206.78147%2022.4794%206.67056%2022.4364%206.56713C22.3934%206.4637%2022.3304%206.36978%2022.251%206.29079L22.2495%206.28933L17.7105%201.75033L17.709%201.74886C17.63%201.66943%2017.5361%201.60639%2017.4327%201.56338C17.3292%201.52037%2017.2183%201.49823%2017.1063%201.49823ZM16.204%200.178361C16.49%200.0594469%2016.7966%20-0.00177002%2017.1063%20-0.00177002C17.416%20-0.00177002%2017.7227%200.0594468%2018.0086%200.178361C18.2942%200.297124%2018.5536%200.4711%2018.7718%200.690304L18.7726%200.691138L23.3087%205.2272L23.3094%205.22795C23.5287%205.44618%2023.7027%205.70555%2023.8214%205.99119C23.9404%206.27715%2024.0016%206.5838%2024.0016%206.8935C24.0016%207.2032%2023.9404%207.50984%2023.8214%207.79581C23.7027%208.08145%2023.5287%208.34082%2023.3094%208.55905L23.3087%208.55979L8.55961%2023.3089L8.55886%2023.3096C8.34063%2023.5289%208.08126%2023.7029%207.79563%2023.8216C7.50966%2023.9405%207.20301%2024.0018%206.89331%2024.0018C6.58362%2024.0018%206.27697%2023.9405%205.991%2023.8216C5.70537%2023.7029%205.446%2023.5289%205.22777%2023.3096L5.22702%2023.3089L0.690955%2018.7728L0.690121%2018.772C0.470917%2018.5538%200.296941%2018.2944%200.178177%2018.0088C0.0592636%2017.7228%20-0.00195312%2017.4162%20-0.00195312%2017.1065C-0.00195312%2016.7968%200.0592638%2016.4901%200.178177%2016.2042C0.296919%2015.9186%200.470851%2015.6593%200.689999%2015.4412L0.690955%2015.4402L6.93044%209.19971C7.1095%209.02062%207.36684%208.94399%207.6147%208.99595L8.08778%209.09513C8.22508%209.12212%208.36692%209.115%208.50084%209.07437C8.6357%209.03347%208.75835%208.95987%208.85789%208.86012C8.95742%208.76037%209.03076%208.63756%209.07138%208.50263C9.11179%208.36839%209.11857%208.22629%209.09113%208.08884L8.99177%207.61488C8.93979%207.36694%209.01649%207.10952%209.1957%206.93045L15.44%200.691138L15.4411%200.690074C15.6592%200.470977%2015.9185%200.297082%2016.204%200.178361Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M7.49725%2015.4419C7.79001%2015.1489%208.26489%2015.1487%208.55791%2015.4414L9.69291%2016.5754C9.83364%2016.716%209.91275%2016.9068%209.91281%2017.1058C9.91288%2017.3047%209.8339%2017.4955%209.69326%2017.6362L7.42426%2019.9062C7.28362%2020.0469%207.09284%2020.126%206.8939%2020.126C6.69496%2020.126%206.50416%2020.047%206.36348%2019.9063L5.22848%2018.7713C4.93559%2018.4784%204.93559%2018.0036%205.22848%2017.7107C5.52138%2017.4178%205.99625%2017.4178%206.28914%2017.7107L6.8937%2018.3152L8.10204%2017.1063L7.49772%2016.5026C7.2047%2016.2098%207.20449%2015.7349%207.49725%2015.4419Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M17.7105%205.22867C18.0034%204.93577%2018.4782%204.93577%2018.7711%205.22867L19.9061%206.36367C20.0468%206.50434%2020.1258%206.69514%2020.1258%206.89408C20.1258%207.09302%2020.0467%207.2838%2019.906%207.42444L17.636%209.69344C17.4953%209.83409%2017.3045%209.91306%2017.1056%209.913C16.9066%209.91293%2016.7159%209.83383%2016.5753%209.69309L15.4413%208.55809C15.1485%208.26507%2015.1487%207.7902%2015.4417%207.49743C15.7347%207.20467%2016.2096%207.20488%2016.5024%207.4979L17.1062%208.10222L18.315%206.89388L17.7105%206.28933C17.4176%205.99643%2017.4176%205.52156%2017.7105%205.22867Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M13.1715%209.76767C13.4644%209.47477%2013.9392%209.47477%2014.2321%209.76767L15.3671%2010.9027C15.5078%2011.0433%2015.5868%2011.2341%2015.5868%2011.433C15.5868%2011.6319%2015.5078%2011.8227%2015.3671%2011.9633L11.9631%2015.3673C11.8225%2015.508%2011.6317%2015.587%2011.4328%2015.587C11.2339%2015.587%2011.0431%2015.508%2010.9025%2015.3673L9.76748%2014.2323C9.47459%2013.9394%209.47459%2013.4646%209.76748%2013.1717C10.0604%2012.8788%2010.5353%2012.8788%2010.8281%2013.1717L11.4328%2013.7763L13.7762%2011.433L13.1715%2010.8283C12.8786%2010.5354%2012.8786%2010.0606%2013.1715%209.76767Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M1.82472%2014.3064C2.11774%2014.0137%202.59261%2014.0139%202.88538%2014.3069L4.01938%2015.4419C4.31214%2015.7349%204.31193%2016.2098%204.01891%2016.5026C3.72589%2016.7953%203.25101%2016.7951%202.95825%2016.5021L1.82425%2015.3671C1.53149%2015.0741%201.5317%2014.5992%201.82472%2014.3064Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M4.09348%2012.0367C4.38638%2011.7438%204.86125%2011.7438%205.15414%2012.0367L6.28914%2013.1717C6.58204%2013.4646%206.58204%2013.9394%206.28914%2014.2323C5.99625%2014.5252%205.52138%2014.5252%205.22848%2014.2323L4.09348%2013.0973C3.80059%2012.8044%203.80059%2012.3296%204.09348%2012.0367Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M12.0365%204.09367C12.3294%203.80077%2012.8043%203.80077%2013.0971%204.09367L14.2321%205.22867C14.525%205.52156%2014.525%205.99643%2014.2321%206.28933C13.9392%206.58222%2013.4644%206.58222%2013.1715%206.28933L12.0365%205.15433C11.7436%204.86143%2011.7436%204.38656%2012.0365%204.09367Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M14.3063%201.8249C14.599%201.53188%2015.0739%201.53167%2015.3669%201.82443L16.5019%202.95843C16.7949%203.2512%2016.7951%203.72607%2016.5024%204.01909C16.2096%204.31212%2015.7347%204.31232%2015.4417%204.01956L14.3067%202.88556C14.0137%202.5928%2014.0135%202.11792%2014.3063%201.8249Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3C%2Fsvg%3E%0A"
 
-	stopProcessActionID = BaseActionID + ".stop-process"
+	stopProcessActionID       = BaseActionID + ".stop-process"
+	stopProcessTargetActionID = BaseActionID + ".stop-process-target"
+
+	freezeProcessActionID   = BaseActionID + ".freeze-process"
+	signalProcessActionID   = BaseActionID + ".signal-process"
+	throttleProcessActionID = BaseActionID + ".throttle-process"
+	rlimitProcessActionID   = BaseActionID + ".rlimit-process"
+
+	processTargetID     = "com.steadybit.extension_host.process"
+	systemdUnitTargetID = "com.steadybit.extension_host.systemd-unit"
+	systemdUnitIcon     = "data:image/svg+xml,%3Csvg%20width%3D%2224%22%20height%3D%2224%22%20viewBox%3D%220%200%2024%2024%22%20fill%3D%22none%22%20xmlns%3D%22http%3A%2F%2Fwww.w3.org%2F2000%2Fsvg%22%3E%3Cpath%20fill%3D%22currentColor%22%20d%3D%22M4.5%203A1.5%201.5%200%200%200%203%204.5v4A1.5%201.5%200%200%200%204.5%2010h15A1.5%201.5%200%200%200%2021%208.5v-4A1.5%201.5%200%200%200%2019.5%203h-15Zm0%201.5h15v4h-15v-4ZM4.5%2014A1.5%201.5%200%200%200%203%2015.5v4A1.5%201.5%200%200%200%204.5%2021h15a1.5%201.5%200%200%200%201.5-1.5v-4a1.5%201.5%200%200%200-1.5-1.5h-15Zm0%201.5h15v4h-15v-4ZM6.5%205.75a.75.75%200%201%200%200%201.5.75.75%200%200%200%200-1.5Zm0%2011a.75.75%200%201%200%200%201.5.75.75%200%200%200%200-1.5ZM9%206.5h3M9%2017.5h3%22%2F%3E%3C%2Fsvg%3E"
 	stopProcessIcon     = "data:image/svg+xml,%3Csvg%20width%3D%2222%22%20height%3D%2222%22%20viewBox%3D%220%200%2022%2022%22%20fill%3D%22none%22%20xmlns%3D%22http%3A%2F%2Fwww.w3.org%2F2000%2Fsvg%22%3E%0A%3Cpath%20d%3D%22M9%208C8.44772%208%208%208.44772%208%209V13C8%2013.5523%208.44772%2014%209%2014H13C13.5523%2014%2014%2013.5523%2014%2013V9C14%208.44772%2013.5523%208%2013%208H9Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M11%200.250015C5.06312%200.250015%200.25%205.06314%200.25%2011C0.25%2016.9369%205.06312%2021.75%2011%2021.75C16.9369%2021.75%2021.75%2016.9369%2021.75%2011C21.75%205.06314%2016.9369%200.250015%2011%200.250015ZM1.75%2011C1.75%205.89156%205.89155%201.75002%2011%201.75002C16.1085%201.75002%2020.25%205.89156%2020.25%2011C20.25%2016.1085%2016.1085%2020.25%2011%2020.25C5.89155%2020.25%201.75%2016.1085%201.75%2011Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3C%2Fsvg%3E%0A"
 	tcpResetIcon        = "data:image/svg+xml,%3Csvg%20xmlns%3D%22http%3A%2F%2Fwww.w3.org%2F2000%2Fsvg%22%20width%3D%2224%22%20height%3D%2224%22%20viewBox%3D%220%200%2024%2024%22%20fill%3D%22none%22%3E%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M4.56406%204.58943C4.73426%204.4757%204.93437%204.415%205.13907%204.415C5.41357%204.415%205.67683%204.52404%205.87093%204.71814C6.06503%204.91224%206.17407%205.1755%206.17407%205.45C6.17407%205.6547%206.11337%205.85481%205.99965%206.02501C5.88592%206.19522%205.72427%206.32788%205.53515%206.40622C5.34603%206.48455%205.13793%206.50505%204.93716%206.46511C4.73638%206.42518%204.55196%206.3266%204.40722%206.18186C4.26247%206.03711%204.1639%205.85269%204.12396%205.65192C4.08403%205.45115%204.10452%205.24304%204.18286%205.05392C4.2612%204.8648%204.39385%204.70316%204.56406%204.58943Z%22%20fill%3D%22currentColor%22%2F%3E%3Cpath%20d%3D%22M9.28%205.45C9.28%205.06892%209.58892%204.76%209.97%204.76H11.35C11.7311%204.76%2012.04%205.06892%2012.04%205.45C12.04%205.83108%2011.7311%206.14%2011.35%206.14H9.97C9.58892%206.14%209.28%205.83108%209.28%205.45Z%22%20fill%3D%22currentColor%22%2F%3E%3Cpath%20d%3D%22M14.11%204.76C13.7289%204.76%2013.42%205.06892%2013.42%205.45C13.42%205.83108%2013.7289%206.14%2014.11%206.14H15.49C15.8711%206.14%2016.18%205.83108%2016.18%205.45C16.18%205.06892%2015.8711%204.76%2015.49%204.76H14.11Z%22%20fill%3D%22currentColor%22%2F%3E%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M17.5601%208.20991C17.6899%208.11255%2017.8134%208.00559%2017.9295%207.88952C18.5765%207.24252%2018.94%206.365%2018.94%205.45C18.94%204.535%2018.5765%203.65748%2017.9295%203.01048C17.2825%202.36348%2016.405%202%2015.49%202H4.45C3.535%202%202.65748%202.36348%202.01048%203.01048C1.36348%203.65748%201%204.535%201%205.45C1%206.365%201.36348%207.24252%202.01048%207.88952C2.12659%208.00563%202.25013%208.11261%202.37998%208.21C2.25013%208.30739%202.12659%208.41437%202.01048%208.53048C1.36348%209.17748%201%2010.055%201%2010.97C1%2011.885%201.36348%2012.7625%202.01048%2013.4095C2.65748%2014.0565%203.535%2014.42%204.45%2014.42H7.9V18.56H5.14C4.75892%2018.56%204.45%2018.8689%204.45%2019.25C4.45%2019.6311%204.75892%2019.94%205.14%2019.94H8.59C8.97108%2019.94%209.28%2019.6311%209.28%2019.25V14.42H12.0391C12.4202%2014.42%2012.7291%2014.1111%2012.7291%2013.73C12.7291%2013.3489%2012.4202%2013.04%2012.0391%2013.04H4.45C3.901%2013.04%203.37449%2012.8219%202.98629%2012.4337C2.59809%2012.0455%202.38%2011.519%202.38%2010.97C2.38%2010.421%202.59809%209.89449%202.98629%209.50629C3.37449%209.11809%203.901%208.9%204.45%208.9H15.49C15.8496%208.89997%2016.2031%208.99361%2016.5155%209.17171C16.8279%209.34981%2017.0885%209.60622%2017.2716%209.91568C17.4657%2010.2436%2017.8889%2010.3521%2018.2169%2010.158C18.5448%209.96394%2018.6533%209.54074%2018.4592%209.2128C18.2276%208.82147%2017.9217%208.48105%2017.5601%208.20991ZM4.45%203.38C3.901%203.38%203.37449%203.59809%202.98629%203.98629C2.59809%204.37449%202.38%204.901%202.38%205.45C2.38%205.999%202.59809%206.52551%202.98629%206.91371C3.37449%207.30191%203.901%207.52%204.45%207.52H15.4899C16.0389%207.52%2016.5655%207.30191%2016.9537%206.91371C17.3419%206.52551%2017.56%205.999%2017.56%205.45C17.56%204.901%2017.3419%204.37449%2016.9537%203.98629C16.5655%203.59809%2016.039%203.38%2015.49%203.38H4.45Z%22%20fill%3D%22currentColor%22%2F%3E%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M4.56406%2010.1094C4.73426%209.9957%204.93437%209.935%205.13907%209.935C5.41357%209.935%205.67683%2010.044%205.87093%2010.2381C6.06503%2010.4322%206.17407%2010.6955%206.17407%2010.97C6.17407%2011.1747%206.11337%2011.3748%205.99965%2011.545C5.88592%2011.7152%205.72427%2011.8479%205.53515%2011.9262C5.34603%2012.0046%205.13792%2012.025%204.93716%2011.9851C4.73639%2011.9452%204.55197%2011.8466%204.40722%2011.7019C4.26247%2011.5571%204.1639%2011.3727%204.12396%2011.1719C4.08403%2010.9711%204.10452%2010.763%204.18286%2010.5739C4.2612%2010.3848%204.39385%2010.2232%204.56406%2010.1094Z%22%20fill%3D%22currentColor%22%2F%3E%3Cpath%20d%3D%22M9.97%2010.28C9.58892%2010.28%209.28%2010.5889%209.28%2010.97C9.28%2011.3511%209.58892%2011.66%209.97%2011.66H11.35C11.7311%2011.66%2012.04%2011.3511%2012.04%2010.97C12.04%2010.5889%2011.7311%2010.28%2011.35%2010.28H9.97Z%22%20fill%3D%22currentColor%22%2F%3E%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M1.68998%2018.2744C1.86019%2018.1607%202.0603%2018.1%202.265%2018.1C2.5395%2018.1%202.80275%2018.209%202.99686%2018.4031C3.19096%2018.5972%203.3%2018.8605%203.3%2019.135C3.3%2019.3397%203.2393%2019.5398%203.12557%2019.71C3.01184%2019.8802%202.8502%2020.0129%202.66108%2020.0912C2.47195%2020.1696%202.26385%2020.19%202.06308%2020.1501C1.86232%2020.1102%201.67789%2020.0116%201.53314%2019.8669C1.3884%2019.7221%201.28982%2019.5377%201.24989%2019.3369C1.20995%2019.1361%201.23045%2018.928%201.30878%2018.7389C1.38712%2018.5498%201.51978%2018.3882%201.68998%2018.2744Z%22%20fill%3D%22currentColor%22%2F%3E%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M18.005%2012.8207C15.8422%2012.8207%2014.0889%2014.5741%2014.0889%2016.7369C14.0889%2018.8997%2015.8422%2020.653%2018.005%2020.653C20.1678%2020.653%2021.9211%2018.8997%2021.9211%2016.7369C21.9211%2014.5741%2020.1678%2012.8207%2018.005%2012.8207ZM12.97%2016.7369C12.97%2013.9561%2015.2243%2011.7019%2018.005%2011.7019C20.7858%2011.7019%2023.04%2013.9561%2023.04%2016.7369C23.04%2019.5176%2020.7858%2021.7719%2018.005%2021.7719C15.2243%2021.7719%2012.97%2019.5176%2012.97%2016.7369Z%22%20fill%3D%22currentColor%22%2F%3E%3Cpath%20d%3D%22M17.9912%2017.0834C17.6099%2017.0834%2017.3008%2016.7744%2017.3008%2016.3931V14.3219C17.3008%2013.9407%2017.6099%2013.6316%2017.9912%2013.6316C18.3725%2013.6316%2018.6815%2013.9407%2018.6815%2014.3219V16.3931C18.6815%2016.7744%2018.3725%2017.0834%2017.9912%2017.0834Z%22%20fill%3D%22currentColor%22%2F%3E%3Cpath%20d%3D%22M16.9556%2018.8094C16.9556%2018.2375%2017.4192%2017.7738%2017.9912%2017.7738C18.5631%2017.7738%2019.0267%2018.2375%2019.0267%2018.8094C19.0267%2019.3813%2018.5631%2019.8449%2017.9912%2019.8449C17.4192%2019.8449%2016.9556%2019.3813%2016.9556%2018.8094Z%22%20fill%3D%22currentColor%22%2F%3E%3C%2Fsvg%3E"
 	blackHoleIcon       = "data:image/svg+xml,%3Csvg%20width%3D%2224%22%20height%3D%2218%22%20viewBox%3D%220%200%2024%2018%22%20fill%3D%22none%22%20xmlns%3D%22http%3A%2F%2Fwww.w3.org%2F2000%2Fsvg%22%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M1.96299%201.55709C2.24324%201.441%202.56583%201.50517%202.78033%201.71967L4.06066%203H6.75C7.16421%203%207.5%203.33579%207.5%203.75C7.5%204.16421%207.16421%204.5%206.75%204.5H4.06066L2.78033%205.78033C2.56583%205.99483%202.24324%206.059%201.96299%205.94291C1.68273%205.82682%201.5%205.55335%201.5%205.25V4.5H0.75C0.335786%204.5%200%204.16421%200%203.75C0%203.33579%200.335786%203%200.75%203H1.5V2.25C1.5%201.94665%201.68273%201.67318%201.96299%201.55709ZM14.267%205.09123C14.3417%204.7462%2014.647%204.5%2015%204.5H16.5C16.6275%204.5%2016.7528%204.53248%2016.8642%204.59438L23.6012%208.33712C23.6195%208.34685%2023.6374%208.35732%2023.6548%208.36849C23.704%208.39998%2023.7498%208.4374%2023.791%208.48033C23.8285%208.51937%2023.8614%208.56204%2023.8892%208.60743C23.9322%208.67737%2023.9641%208.75492%2023.9824%208.83759C23.9888%208.86671%2023.9926%208.88844%2023.9955%208.91775C24.0037%208.9996%2024.0005%209.08352%2023.9824%209.16241C23.9641%209.24508%2023.9322%209.32263%2023.8892%209.39257C23.8614%209.43796%2023.8285%209.48063%2023.791%209.51967C23.7498%209.5626%2023.704%209.60002%2023.6548%209.63151C23.6374%209.64268%2023.6195%209.65315%2023.6012%209.66288L16.8642%2013.4056C16.7528%2013.4675%2016.6275%2013.5%2016.5%2013.5H15C14.647%2013.5%2014.3417%2013.2538%2014.267%2012.9088C14.1923%2012.5637%2014.3683%2012.2133%2014.6896%2012.0672L19.7875%209.75H11.25C10.8358%209.75%2010.5%209.41421%2010.5%209C10.5%208.58579%2010.8358%208.25%2011.25%208.25H19.7875L14.6896%205.93278C14.3683%205.78669%2014.1923%205.43625%2014.267%205.09123ZM3.46299%206.80709C3.74324%206.69101%204.06583%206.75517%204.28033%206.96967L5.56066%208.25H6C6.41421%208.25%206.75%208.58579%206.75%209C6.75%209.41421%206.41421%209.75%206%209.75H5.56066L4.28033%2011.0303C4.06583%2011.2448%203.74324%2011.309%203.46299%2011.1929C3.18273%2011.0768%203%2010.8033%203%2010.5V9.75H0.75C0.335786%209.75%200%209.41421%200%209C0%208.58579%200.335786%208.25%200.75%208.25H3V7.5C3%207.19665%203.18273%206.92318%203.46299%206.80709ZM1.96299%2012.0571C2.24324%2011.941%202.56583%2012.0052%202.78033%2012.2197L4.06066%2013.5H6.75C7.16421%2013.5%207.5%2013.8358%207.5%2014.25C7.5%2014.6642%207.16421%2015%206.75%2015H4.06066L2.78033%2016.2803C2.56583%2016.4948%202.24324%2016.559%201.96299%2016.4429C1.68273%2016.3268%201.5%2016.0533%201.5%2015.75V15H0.75C0.335786%2015%200%2014.6642%200%2014.25C0%2013.8358%200.335786%2013.5%200.75%2013.5H1.5V12.75C1.5%2012.4467%201.68273%2012.1732%201.96299%2012.0571Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M10.8582%202.0271C12.2166%201.19193%2013.7799%200.749779%2015.3745%200.749779C16.9691%200.749779%2018.5324%201.19193%2019.8908%202.0271L19.9001%202.03282C21.8129%203.24755%2023.1708%205.16617%2023.6806%207.37395C24.1903%209.58172%2023.8109%2011.9014%2022.6243%2013.8318C21.4378%2015.7621%2019.5393%2017.148%2017.3393%2017.6901C15.1392%2018.2322%2012.8142%2017.8868%2010.8667%2016.7286C10.5106%2016.5169%2010.3937%2016.0567%2010.6054%2015.7007C10.8171%2015.3446%2011.2773%2015.2277%2011.6333%2015.4394C13.2422%2016.3961%2015.1629%2016.6814%2016.9804%2016.2337C18.7979%2015.7859%2020.3662%2014.6409%2021.3465%2013.0463C22.3267%2011.4516%2022.6401%209.53527%2022.219%207.7114C21.7983%205.88932%2020.6782%204.3057%2019.1005%203.30205C17.9795%202.61401%2016.6899%202.24978%2015.3745%202.24978C14.0573%202.24978%2012.7659%202.61502%2011.6438%203.3049C11.291%203.52185%2010.829%203.41167%2010.6121%203.05881C10.3952%202.70596%2010.5053%202.24404%2010.8582%202.0271Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M9.55202%206.73421C9.22595%207.27773%209%208.07701%209%209C9%209.92299%209.22595%2010.7223%209.55202%2011.2658C9.88701%2011.8242%2010.2454%2012%2010.5%2012C10.7546%2012%2011.113%2011.8242%2011.448%2011.2658C11.7741%2010.7223%2012%209.92299%2012%209C12%208.07701%2011.7741%207.27773%2011.448%206.73421C11.113%206.17582%2010.7546%206%2010.5%206C10.2454%206%209.88701%206.17582%209.55202%206.73421ZM8.26573%205.96254C8.74499%205.16368%209.51158%204.5%2010.5%204.5C11.4884%204.5%2012.255%205.16368%2012.7343%205.96254C13.2224%206.77627%2013.5%207.85199%2013.5%209C13.5%2010.148%2013.2224%2011.2237%2012.7343%2012.0375C12.255%2012.8363%2011.4884%2013.5%2010.5%2013.5C9.51158%2013.5%208.74499%2012.8363%208.26573%2012.0375C7.77755%2011.2237%207.5%2010.148%207.5%209C7.5%207.85199%207.77755%206.77627%208.26573%205.96254Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3C%2Fsvg%3E%0A"
@@ -51,6 +61,22 @@ var (
 		},
 	}
 
+	systemdUnitSelectionTemplates = []action_kit_api.TargetSelectionTemplate{
+		{
+			Label:       "systemd service name",
+			Description: new("Find systemd service by unit name."),
+			Query:       "systemd.unit.name=\"\"",
+		},
+	}
+
+	processSelectionTemplates = []action_kit_api.TargetSelectionTemplate{
+		{
+			Label:       "process name",
+			Description: new("Find process by name."),
+			Query:       "process.name=\"\"",
+		},
+	}
+
 	osHostname = func() (string, error) {
 		hostname := config.Config.Hostname
 		if hostname == "" {
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package conntrack fills or flushes the connection tracking table of the host via ctnetlink. The
+// synthetic entries are held by the `conntrack` subcommand of the extension, which is started directly or
+// as runc sidecar in the host network namespace.
+package conntrack
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/steadybit/action-kit/go/action_kit_commons/network"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/extension-host/exthost/holder"
+)
+
+type Mode string
+
+const (
+	// ModeFill adds synthetic entries until the table is filled up to the usage
+	ModeFill Mode = "fill"
+	// ModeFlush deletes the entries matching the filter
+	ModeFlush Mode = "flush"
+)
+
+type Opts struct {
+	Mode Mode
+	// Usage is the share of nf_conntrack_max to fill up to
+	Usage int
+	// Include and Exclude select the entries to flush, matched against both ends of the original direction
+	Include []network.NetWithPortRange
+	Exclude []network.NetWithPortRange
+}
+
+func (o Opts) Args() []string {
+	args := []string{"conntrack", "-mode", string(o.Mode)}
+	switch o.Mode {
+	case ModeFill:
+		args = append(args, "-usage", strconv.Itoa(o.Usage))
+	case ModeFlush:
+		include, _ := json.Marshal(o.Include)
+		exclude, _ := json.Marshal(o.Exclude)
+		args = append(args, "-include", string(include), "-exclude", string(exclude))
+	}
+	return args
+}
+
+func NewConntrackProcess(opts Opts) (holder.Holder, error) {
+	return holder.NewProcess(opts.Args())
+}
+
+func NewConntrackRunc(ctx context.Context, r ociruntime.OciRuntime, sidecar holder.SidecarOpts, opts Opts) (holder.Holder, error) {
+	return holder.NewRunc(ctx, r, sidecar, opts.Args(), "CAP_NET_ADMIN")
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package conntrack
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/steadybit/action-kit/go/action_kit_commons/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+const statNfConntrack = `entries  clashres found new invalid ignore delete chainlength insert insert_failed drop early_drop icmp_error  expect_new expect_create expect_delete search_restart
+000003e8  00000000 00000000 00000000 00000000 00000a4b 00000000 00000000 00000000 00000000 00000000 00000000 00000000  00000000 00000000 00000000 00000000
+000003e8  00000000 00000000 00000000 00000000 00000b12 00000000 00000000 00000000 00000000 00000000 00000000 00000000  00000000 00000000 00000000 00000000
+`
+
+func TestReadUsage(t *testing.T) {
+	dir := t.TempDir()
+	ProcConntrackMax = filepath.Join(dir, "nf_conntrack_max")
+	defer func() { ProcConntrackMax = "/proc/sys/net/netfilter/nf_conntrack_max" }()
+	require.NoError(t, os.WriteFile(ProcConntrackMax, []byte("262144\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "stat"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stat", "nf_conntrack"), []byte(statNfConntrack), 0644))
+
+	usage, err := ReadUsage(dir)
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Count: 1000, Max: 262144}, usage)
+}
+
+func TestOptsArgs(t *testing.T) {
+	assert.Equal(t, []string{"conntrack", "-mode", "fill", "-usage", "90"}, Opts{Mode: ModeFill, Usage: 90}.Args())
+
+	args := Opts{Mode: ModeFlush, Include: []network.NetWithPortRange{{Net: ipNet("10.0.0.0/8"), PortRange: network.PortRange{From: 80, To: 80}}}}.Args()
+	require.Len(t, args, 7)
+	assert.Equal(t, []string{"conntrack", "-mode", "flush", "-include"}, args[:4])
+	assert.Equal(t, "-exclude", args[5])
+}
+
+func TestCreateMessageRoundTrip(t *testing.T) {
+	for _, tuple := range []Tuple{
+		syntheticTuple(123456),
+		{Src: net.ParseIP("fd00::1"), Dst: net.ParseIP("fd00::2"), Proto: unix.IPPROTO_TCP, SrcPort: 40000, DstPort: 443},
+	} {
+		msgs, err := syscall.ParseNetlinkMessage(createMessage(1, tuple, ipsSeenReply|ipsAssured, entryTimeout))
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+		assert.Equal(t, uint16(unix.NFNL_SUBSYS_CTNETLINK<<8|ipctnlMsgCtNew), msgs[0].Header.Type)
+
+		parsed, ok := parseTuple(msgs[0].Data)
+		require.True(t, ok)
+		assert.True(t, tuple.Src.Equal(parsed.Src))
+		assert.True(t, tuple.Dst.Equal(parsed.Dst))
+		assert.Equal(t, tuple.Proto, parsed.Proto)
+		assert.Equal(t, tuple.SrcPort, parsed.SrcPort)
+		assert.Equal(t, tuple.DstPort, parsed.DstPort)
+	}
+}
+
+func TestSyntheticTuplesAreDistinct(t *testing.T) {
+	seen := map[string]struct{}{}
+	for i := range 3 * syntheticPortCount {
+		tuple := syntheticTuple(i)
+		key := fmt.Sprintf("%s:%d", tuple.Src, tuple.SrcPort)
+		_, duplicate := seen[key]
+		require.False(t, duplicate, "tuple %d", i)
+		seen[key] = struct{}{}
+	}
+	assert.Equal(t, "198.18.0.2", syntheticTuple(2*syntheticPortCount).Src.String())
+}
+
+func TestMatches(t *testing.T) {
+	nets := []network.NetWithPortRange{
+		{Net: ipNet("10.0.0.0/8"), PortRange: network.PortRange{From: 80, To: 80}},
+		{Net: ipNet("192.168.1.10/32"), PortRange: network.PortRangeAny},
+	}
+	tuple := func(src string, sport uint16, dst string, dport uint16) Tuple {
+		return Tuple{Src: net.ParseIP(src), SrcPort: sport, Dst: net.ParseIP(dst), DstPort: dport, Proto: unix.IPPROTO_TCP}
+	}
+
+	assert.True(t, matches(tuple("172.16.0.1", 40000, "10.1.2.3", 80), nets))
+	assert.True(t, matches(tuple("10.1.2.3", 80, "172.16.0.1", 40000), nets))
+	assert.False(t, matches(tuple("172.16.0.1", 40000, "10.1.2.3", 443), nets))
+	assert.True(t, matches(tuple("192.168.1.10", 22, "172.16.0.1", 40000), nets))
+	assert.False(t, matches(tuple("172.16.0.1", 40000, "192.168.1.11", 22), nets))
+}
+
+func ipNet(cidr string) net.IPNet {
+	_, n, _ := net.ParseCIDR(cidr)
+	return *n
+}
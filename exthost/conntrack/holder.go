@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package conntrack
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_commons/network"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	batchSize = 256
+	// entryTimeout lets the entries expire on their own if the holder is killed, they are refreshed
+	// long before
+	entryTimeout    = 120
+	refreshInterval = 30 * time.Second
+)
+
+var (
+	// synthetic entries use the benchmarking range (RFC 2544), which isn't routed
+	syntheticSrc = binary.BigEndian.Uint32(net.IPv4(198, 18, 0, 0).To4())
+	syntheticDst = net.IPv4(198, 19, 0, 1).To4()
+)
+
+const (
+	syntheticPortFrom  = 10000
+	syntheticPortCount = 50000
+)
+
+// syntheticTuple returns a distinct udp tuple for each index.
+func syntheticTuple(i int) Tuple {
+	return Tuple{
+		Src:     binary.BigEndian.AppendUint32(nil, syntheticSrc+uint32(i/syntheticPortCount)),
+		Dst:     syntheticDst,
+		Proto:   unix.IPPROTO_UDP,
+		SrcPort: uint16(syntheticPortFrom + i%syntheticPortCount),
+		DstPort: 9,
+	}
+}
+
+// Run implements the `conntrack` subcommand. In fill mode it holds the entries until it is terminated and
+// deletes them, in flush mode it deletes the matching entries and waits for termination. Returns the exit
+// code.
+func Run(args []string) int {
+	var include, exclude string
+	flags := flag.NewFlagSet("conntrack", flag.ContinueOnError)
+	mode := flags.String("mode", string(ModeFill), "fill or flush")
+	usage := flags.Int("usage", 90, "share of nf_conntrack_max to fill up to")
+	flags.StringVar(&include, "include", "[]", "json encoded networks to flush")
+	flags.StringVar(&exclude, "exclude", "[]", "json encoded networks to keep")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	terminated := make(chan os.Signal, 1)
+	signal.Notify(terminated, syscall.SIGTERM, syscall.SIGINT)
+
+	c, err := dial()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer func() { _ = c.close() }()
+
+	switch Mode(*mode) {
+	case ModeFill:
+		return runFill(c, *usage, terminated)
+	case ModeFlush:
+		var opts Opts
+		if err := errors.Join(json.Unmarshal([]byte(include), &opts.Include), json.Unmarshal([]byte(exclude), &opts.Exclude)); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "invalid filter: %s\n", err)
+			return 2
+		}
+		deleted, err := flush(c, opts.Include, opts.Exclude)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to flush: %s\n", err)
+			return 1
+		}
+		fmt.Printf("deleted %d entries\n", deleted)
+		<-terminated
+		return 0
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "unknown mode %s\n", *mode)
+		return 2
+	}
+}
+
+func runFill(c *conn, usage int, terminated <-chan os.Signal) int {
+	current, err := ReadUsage("/proc/self/net")
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to read the conntrack usage: %s\n", err)
+		return 1
+	}
+
+	entries, err := fill(c, current.Max*usage/100-current.Count)
+	defer func() {
+		if err := release(c, entries); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to delete the entries: %s\n", err)
+		}
+	}()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "stopped at %d entries: %s\n", len(entries), err)
+		return 1
+	}
+	fmt.Printf("holding %d entries\n", len(entries))
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-terminated:
+			return 0
+		case <-ticker.C:
+			if err := refresh(c, entries); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "failed to refresh the entries: %s\n", err)
+			}
+		}
+	}
+}
+
+// fill creates count synthetic entries. A full table isn't an error, that's what the attack is about.
+func fill(c *conn, count int) ([]Tuple, error) {
+	// assured entries aren't early dropped when the table is full. Recent kernels mark new entries as
+	// confirmed before applying the status and refuse to clear the bit, older ones refuse to set it.
+	status := uint32(ipsConfirmed | ipsSeenReply | ipsAssured)
+
+	var entries []Tuple
+	for i := 0; i < count; i += batchSize {
+		batch := make([]Tuple, 0, batchSize)
+		for j := i; j < min(i+batchSize, count); j++ {
+			batch = append(batch, syntheticTuple(j))
+		}
+		errs, err := c.execute(batch, func(seq uint32, t Tuple) []byte { return createMessage(seq, t, status, entryTimeout) })
+		if err != nil {
+			return entries, err
+		}
+		if len(entries) == 0 && errors.Is(errs[0], unix.EBUSY) && status&ipsConfirmed != 0 {
+			status &^= ipsConfirmed
+			i -= batchSize
+			continue
+		}
+		full := false
+		for k, err := range errs {
+			switch {
+			// left over by a holder which was killed
+			case err == nil, errors.Is(err, unix.EEXIST):
+				entries = append(entries, batch[k])
+			case errors.Is(err, unix.ENOMEM), errors.Is(err, unix.ENOSPC):
+				full = true
+			default:
+				return entries, err
+			}
+		}
+		if full {
+			break
+		}
+	}
+	return entries, nil
+}
+
+func refresh(c *conn, entries []Tuple) error {
+	return forEachBatch(entries, func(batch []Tuple) error {
+		_, err := c.execute(batch, func(seq uint32, t Tuple) []byte { return refreshMessage(seq, t, entryTimeout) })
+		return err
+	})
+}
+
+func release(c *conn, entries []Tuple) error {
+	return forEachBatch(entries, func(batch []Tuple) error {
+		_, err := c.execute(batch, deleteMessage)
+		return err
+	})
+}
+
+// flush deletes the entries with either end matching an include and no end matching an exclude.
+func flush(c *conn, include, exclude []network.NetWithPortRange) (int, error) {
+	entries, err := c.dump()
+	if err != nil {
+		return 0, err
+	}
+
+	var matching []Tuple
+	for _, t := range entries {
+		if matches(t, include) && !matches(t, exclude) {
+			matching = append(matching, t)
+		}
+	}
+
+	deleted := 0
+	err = forEachBatch(matching, func(batch []Tuple) error {
+		errs, err := c.execute(batch, deleteMessage)
+		if err != nil {
+			return err
+		}
+		for _, err := range errs {
+			// entries may have expired since the dump
+			if err == nil {
+				deleted++
+			} else if !errors.Is(err, unix.ENOENT) {
+				return err
+			}
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// matches checks whether the source or destination of the tuple is in one of the networks.
+func matches(t Tuple, nets []network.NetWithPortRange) bool {
+	for _, n := range nets {
+		if n.Net.Contains(t.Src) && n.PortRange.From <= t.SrcPort && t.SrcPort <= n.PortRange.To {
+			return true
+		}
+		if n.Net.Contains(t.Dst) && n.PortRange.From <= t.DstPort && t.DstPort <= n.PortRange.To {
+			return true
+		}
+	}
+	return false
+}
+
+func forEachBatch(tuples []Tuple, f func([]Tuple) error) error {
+	for i := 0; i < len(tuples); i += batchSize {
+		if err := f(tuples[i:min(i+batchSize, len(tuples))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package conntrack
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// ctnetlink message and attribute types, see include/uapi/linux/netfilter/nfnetlink_conntrack.h
+const (
+	ipctnlMsgCtNew    = 0
+	ipctnlMsgCtGet    = 1
+	ipctnlMsgCtDelete = 2
+
+	ctaTupleOrig  = 1
+	ctaTupleReply = 2
+	ctaStatus     = 3
+	ctaTimeout    = 7
+
+	ctaTupleIp    = 1
+	ctaTupleProto = 2
+
+	ctaIpV4Src = 1
+	ctaIpV4Dst = 2
+	ctaIpV6Src = 3
+	ctaIpV6Dst = 4
+
+	ctaProtoNum     = 1
+	ctaProtoSrcPort = 2
+	ctaProtoDstPort = 3
+
+	ipsSeenReply = 1 << 1
+	ipsAssured   = 1 << 2
+	ipsConfirmed = 1 << 3
+
+	nfgenmsgLen = 4
+)
+
+// Tuple is the original direction of a conntrack entry.
+type Tuple struct {
+	Src, Dst         net.IP
+	Proto            uint8
+	SrcPort, DstPort uint16
+}
+
+func (t Tuple) reverse() Tuple {
+	return Tuple{Src: t.Dst, Dst: t.Src, Proto: t.Proto, SrcPort: t.DstPort, DstPort: t.SrcPort}
+}
+
+func (t Tuple) family() uint8 {
+	if t.Src.To4() != nil {
+		return unix.AF_INET
+	}
+	return unix.AF_INET6
+}
+
+type attrs []byte
+
+func (a *attrs) add(typ uint16, value []byte) {
+	header := make([]byte, unix.SizeofNlAttr)
+	binary.NativeEndian.PutUint16(header[0:], uint16(unix.SizeofNlAttr+len(value)))
+	binary.NativeEndian.PutUint16(header[2:], typ)
+	*a = append(*a, header...)
+	*a = append(*a, value...)
+	for len(*a)%unix.NLA_ALIGNTO != 0 {
+		*a = append(*a, 0)
+	}
+}
+
+func (a *attrs) nested(typ uint16, f func(*attrs)) {
+	var inner attrs
+	f(&inner)
+	a.add(typ|unix.NLA_F_NESTED, inner)
+}
+
+func (a *attrs) tuple(typ uint16, t Tuple) {
+	a.nested(typ, func(a *attrs) {
+		a.nested(ctaTupleIp, func(a *attrs) {
+			if ip := t.Src.To4(); ip != nil {
+				a.add(ctaIpV4Src, ip)
+				a.add(ctaIpV4Dst, t.Dst.To4())
+			} else {
+				a.add(ctaIpV6Src, t.Src.To16())
+				a.add(ctaIpV6Dst, t.Dst.To16())
+			}
+		})
+		a.nested(ctaTupleProto, func(a *attrs) {
+			a.add(ctaProtoNum, []byte{t.Proto})
+			a.add(ctaProtoSrcPort, binary.BigEndian.AppendUint16(nil, t.SrcPort))
+			a.add(ctaProtoDstPort, binary.BigEndian.AppendUint16(nil, t.DstPort))
+		})
+	})
+}
+
+func message(typ uint16, flags uint16, seq uint32, family uint8, body attrs) []byte {
+	b := make([]byte, unix.NLMSG_HDRLEN+nfgenmsgLen, unix.NLMSG_HDRLEN+nfgenmsgLen+len(body))
+	binary.NativeEndian.PutUint16(b[4:], unix.NFNL_SUBSYS_CTNETLINK<<8|typ)
+	binary.NativeEndian.PutUint16(b[6:], flags)
+	binary.NativeEndian.PutUint32(b[8:], seq)
+	b[unix.NLMSG_HDRLEN] = family
+	b[unix.NLMSG_HDRLEN+1] = unix.NFNETLINK_V0
+	b = append(b, body...)
+	binary.NativeEndian.PutUint32(b[0:], uint32(len(b)))
+	return b
+}
+
+// createMessage creates an entry with the given status, see fill.
+func createMessage(seq uint32, t Tuple, status uint32, timeout uint32) []byte {
+	var body attrs
+	body.tuple(ctaTupleOrig, t)
+	body.tuple(ctaTupleReply, t.reverse())
+	body.add(ctaStatus, binary.BigEndian.AppendUint32(nil, status))
+	body.add(ctaTimeout, binary.BigEndian.AppendUint32(nil, timeout))
+	return message(ipctnlMsgCtNew, unix.NLM_F_REQUEST|unix.NLM_F_ACK|unix.NLM_F_CREATE|unix.NLM_F_EXCL, seq, t.family(), body)
+}
+
+// refreshMessage resets the timeout of an existing entry.
+func refreshMessage(seq uint32, t Tuple, timeout uint32) []byte {
+	var body attrs
+	body.tuple(ctaTupleOrig, t)
+	body.add(ctaTimeout, binary.BigEndian.AppendUint32(nil, timeout))
+	return message(ipctnlMsgCtNew, unix.NLM_F_REQUEST|unix.NLM_F_ACK, seq, t.family(), body)
+}
+
+func deleteMessage(seq uint32, t Tuple) []byte {
+	var body attrs
+	body.tuple(ctaTupleOrig, t)
+	return message(ipctnlMsgCtDelete, unix.NLM_F_REQUEST|unix.NLM_F_ACK, seq, t.family(), body)
+}
+
+func dumpMessage(seq uint32) []byte {
+	return message(ipctnlMsgCtGet, unix.NLM_F_REQUEST|unix.NLM_F_DUMP, seq, unix.AF_UNSPEC, nil)
+}
+
+func parseAttrs(b []byte) map[uint16][]byte {
+	result := map[uint16][]byte{}
+	for len(b) >= unix.SizeofNlAttr {
+		length := int(binary.NativeEndian.Uint16(b[0:]))
+		if length < unix.SizeofNlAttr || length > len(b) {
+			break
+		}
+		result[binary.NativeEndian.Uint16(b[2:])&^(unix.NLA_F_NESTED|unix.NLA_F_NET_BYTEORDER)] = b[unix.SizeofNlAttr:length]
+		aligned := (length + unix.NLA_ALIGNTO - 1) &^ (unix.NLA_ALIGNTO - 1)
+		if aligned > len(b) {
+			break
+		}
+		b = b[aligned:]
+	}
+	return result
+}
+
+// parseTuple reads the original direction from the payload of a conntrack message.
+func parseTuple(data []byte) (Tuple, bool) {
+	if len(data) < nfgenmsgLen {
+		return Tuple{}, false
+	}
+	orig, ok := parseAttrs(data[nfgenmsgLen:])[ctaTupleOrig]
+	if !ok {
+		return Tuple{}, false
+	}
+	tuple := parseAttrs(orig)
+	ip := parseAttrs(tuple[ctaTupleIp])
+	proto := parseAttrs(tuple[ctaTupleProto])
+
+	var t Tuple
+	if src, ok := ip[ctaIpV4Src]; ok {
+		t.Src, t.Dst = net.IP(src), net.IP(ip[ctaIpV4Dst])
+	} else if src, ok := ip[ctaIpV6Src]; ok {
+		t.Src, t.Dst = net.IP(src), net.IP(ip[ctaIpV6Dst])
+	} else {
+		return Tuple{}, false
+	}
+	if num := proto[ctaProtoNum]; len(num) == 1 {
+		t.Proto = num[0]
+	}
+	if port := proto[ctaProtoSrcPort]; len(port) == 2 {
+		t.SrcPort = binary.BigEndian.Uint16(port)
+	}
+	if port := proto[ctaProtoDstPort]; len(port) == 2 {
+		t.DstPort = binary.BigEndian.Uint16(port)
+	}
+	return t, true
+}
+
+type conn struct {
+	fd  int
+	seq uint32
+	buf []byte
+}
+
+func dial() (*conn, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_NETFILTER)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ctnetlink socket: %w", err)
+	}
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("failed to bind ctnetlink socket: %w", err)
+	}
+	// dumps of busy tables are large
+	_ = unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_RCVBUF, 4<<20)
+	return &conn{fd: fd, buf: make([]byte, 1<<16)}, nil
+}
+
+func (c *conn) close() error {
+	return unix.Close(c.fd)
+}
+
+func (c *conn) nextSeq() uint32 {
+	c.seq++
+	return c.seq
+}
+
+func (c *conn) receive() ([]syscall.NetlinkMessage, error) {
+	n, _, err := unix.Recvfrom(c.fd, c.buf, 0)
+	if err != nil {
+		return nil, err
+	}
+	return syscall.ParseNetlinkMessage(c.buf[:n])
+}
+
+// execute sends the tuples using the given message constructor in a single batch and returns the error
+// acknowledged for each of them.
+func (c *conn) execute(tuples []Tuple, build func(seq uint32, t Tuple) []byte) ([]error, error) {
+	first := c.seq + 1
+	var batch []byte
+	for _, t := range tuples {
+		batch = append(batch, build(c.nextSeq(), t)...)
+	}
+	if err := unix.Sendto(c.fd, batch, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(tuples))
+	for acked := 0; acked < len(tuples); {
+		msgs, err := c.receive()
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			if msg.Header.Type != unix.NLMSG_ERROR || len(msg.Data) < 4 {
+				continue
+			}
+			i := int(msg.Header.Seq - first)
+			if i < 0 || i >= len(tuples) {
+				continue
+			}
+			if errno := -int32(binary.NativeEndian.Uint32(msg.Data)); errno != 0 {
+				errs[i] = syscall.Errno(errno)
+			}
+			acked++
+		}
+	}
+	return errs, nil
+}
+
+// dump lists the original direction of all entries.
+func (c *conn) dump() ([]Tuple, error) {
+	seq := c.nextSeq()
+	if err := unix.Sendto(c.fd, dumpMessage(seq), 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return nil, err
+	}
+
+	var tuples []Tuple
+	for {
+		msgs, err := c.receive()
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			if msg.Header.Seq != seq {
+				continue
+			}
+			switch msg.Header.Type {
+			case unix.NLMSG_DONE:
+				return tuples, nil
+			case unix.NLMSG_ERROR:
+				if len(msg.Data) >= 4 {
+					if errno := -int32(binary.NativeEndian.Uint32(msg.Data)); errno != 0 {
+						return nil, syscall.Errno(errno)
+					}
+				}
+				return nil, errors.New("unexpected ctnetlink error")
+			default:
+				if t, ok := parseTuple(msg.Data); ok {
+					tuples = append(tuples, t)
+				}
+			}
+		}
+	}
+}
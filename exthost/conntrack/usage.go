@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package conntrack
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ProcConntrackMax is global, while the count is per network namespace
+var ProcConntrackMax = "/proc/sys/net/netfilter/nf_conntrack_max"
+
+type Usage struct {
+	Count int
+	Max   int
+}
+
+func (u Usage) Percent() float64 {
+	if u.Max == 0 {
+		return 0
+	}
+	return float64(u.Count) * 100 / float64(u.Max)
+}
+
+// ReadUsage reads the number of entries of the network namespace of the given /proc/<pid>/net directory
+// from stat/nf_conntrack, whose first column is the total count repeated for every cpu.
+func ReadUsage(procNet string) (Usage, error) {
+	content, err := os.ReadFile(ProcConntrackMax)
+	if err != nil {
+		return Usage{}, err
+	}
+	maxEntries, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return Usage{}, err
+	}
+
+	file := filepath.Join(procNet, "stat", "nf_conntrack")
+	f, err := os.Open(file)
+	if err != nil {
+		return Usage{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return Usage{}, err
+		}
+		return Usage{}, fmt.Errorf("unexpected content of %s", file)
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) == 0 {
+		return Usage{}, fmt.Errorf("unexpected content of %s", file)
+	}
+	count, err := strconv.ParseInt(fields[0], 16, 64)
+	if err != nil {
+		return Usage{}, err
+	}
+	return Usage{Count: int(count), Max: maxEntries}, nil
+}
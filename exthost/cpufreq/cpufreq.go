@@ -116,6 +116,23 @@ func SetCPUFrequencyLimits(min, max uint64) error {
 	return nil
 }
 
+// IsWritable reports whether the scaling limits can be changed, e.g. sysfs isn't mounted read-only.
+func IsWritable() bool {
+	cpus, err := listCpuDirs()
+	if err != nil {
+		return false
+	}
+
+	for _, file := range []string{scalingMinFile, scalingMaxFile} {
+		f, err := os.OpenFile(filepath.Join(cpus[0], "cpufreq", file), os.O_WRONLY, 0)
+		if err != nil {
+			return false
+		}
+		_ = f.Close()
+	}
+	return true
+}
+
 func listCpuDirs() ([]string, error) {
 	cpus, err := filepath.Glob(filepath.Join(cpuBasePath, cpuGlob))
 	if err != nil {
@@ -207,3 +207,19 @@ func TestSetCPUFrequencyLimits(t *testing.T) {
 		})
 	}
 }
+
+func TestIsWritable(t *testing.T) {
+	fakeCpuDirectory(t)
+	assert.False(t, IsWritable())
+
+	fakeCpuFile(t, path.Join("cpu0", "cpufreq", "scaling_min_freq"), "800000")
+	assert.False(t, IsWritable())
+
+	fakeCpuFile(t, path.Join("cpu0", "cpufreq", "scaling_max_freq"), "3400000")
+	assert.True(t, IsWritable())
+
+	require.NoError(t, os.Chmod(path.Join(cpuBasePath, "cpu0", "cpufreq", "scaling_max_freq"), 0444))
+	if os.Geteuid() != 0 {
+		assert.False(t, IsWritable())
+	}
+}
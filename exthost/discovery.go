@@ -6,20 +6,39 @@ package exthost
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/elastic/go-sysinfo"
+	"github.com/elastic/go-sysinfo/types"
 	"github.com/rs/zerolog/log"
 	networkutils "github.com/steadybit/action-kit/go/action_kit_commons/network"
 	"github.com/steadybit/discovery-kit/go/discovery_kit_api"
 	"github.com/steadybit/discovery-kit/go/discovery_kit_commons"
 	"github.com/steadybit/discovery-kit/go/discovery_kit_sdk"
 	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/cloudmetadata"
 	"github.com/steadybit/extension-host/exthost/cpufreq"
+	"github.com/steadybit/extension-host/exthost/hostinfo"
 	"github.com/steadybit/extension-kit/extbuild"
 )
 
 type hostDiscovery struct {
+	cloudMetadata cloudMetadataCache
+}
+
+// cloudMetadataRefreshInterval limits how often the instance metadata service is asked. Apart from the
+// tags the metadata doesn't change, and hosts outside a cloud would wait for the timeout on every refresh.
+const cloudMetadataRefreshInterval = 10 * time.Minute
+
+const mib = 1024 * 1024
+
+type cloudMetadataCache struct {
+	mu         sync.Mutex
+	fetching   bool
+	fetchedAt  time.Time
+	attributes map[string][]string
 }
 
 var (
@@ -124,6 +143,97 @@ func (d *hostDiscovery) DescribeAttributes() []discovery_kit_api.AttributeDescri
 				Other: "OS Versions",
 			},
 		},
+		{
+			Attribute: "aws.zone",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "AWS Zone",
+				Other: "AWS Zones",
+			},
+		}, {
+			Attribute: "aws.region",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "AWS Region",
+				Other: "AWS Regions",
+			},
+		}, {
+			Attribute: "aws.instance.id",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "AWS Instance ID",
+				Other: "AWS Instance IDs",
+			},
+		}, {
+			Attribute: "aws.instance.type",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "AWS Instance Type",
+				Other: "AWS Instance Types",
+			},
+		}, {
+			Attribute: "aws.account",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "AWS Account",
+				Other: "AWS Accounts",
+			},
+		}, {
+			Attribute: "gcp.zone",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "GCP Zone",
+				Other: "GCP Zones",
+			},
+		}, {
+			Attribute: "gcp.region",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "GCP Region",
+				Other: "GCP Regions",
+			},
+		}, {
+			Attribute: "gcp.instance.id",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "GCP Instance ID",
+				Other: "GCP Instance IDs",
+			},
+		}, {
+			Attribute: "gcp.instance.type",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "GCP Instance Type",
+				Other: "GCP Instance Types",
+			},
+		}, {
+			Attribute: "gcp.project.id",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "GCP Project ID",
+				Other: "GCP Project IDs",
+			},
+		}, {
+			Attribute: "azure.zone",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Azure Zone",
+				Other: "Azure Zones",
+			},
+		}, {
+			Attribute: "azure.region",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Azure Region",
+				Other: "Azure Regions",
+			},
+		}, {
+			Attribute: "azure.instance.id",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Azure Instance ID",
+				Other: "Azure Instance IDs",
+			},
+		}, {
+			Attribute: "azure.instance.type",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Azure Instance Type",
+				Other: "Azure Instance Types",
+			},
+		}, {
+			Attribute: "azure.subscription.id",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Azure Subscription ID",
+				Other: "Azure Subscription IDs",
+			},
+		},
 		{
 			Attribute: "host.cpu.min_freq",
 			Label: discovery_kit_api.PluralLabel{
@@ -136,6 +246,84 @@ func (d *hostDiscovery) DescribeAttributes() []discovery_kit_api.AttributeDescri
 				One:   "Maximum CPU Frequency (MHz)",
 				Other: "Maximum CPU Frequencies (MHz)",
 			},
+		}, {
+			Attribute: capabilityOciRuntime,
+			Label: discovery_kit_api.PluralLabel{
+				One:   "OCI Runtime",
+				Other: "OCI Runtimes",
+			},
+		}, {
+			Attribute: capabilityShutdown,
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Shutdown Method",
+				Other: "Shutdown Methods",
+			},
+		}, {
+			Attribute: "host.kernel.release",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Kernel Release",
+				Other: "Kernel Releases",
+			},
+		}, {
+			Attribute: "host.arch",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Architecture",
+				Other: "Architectures",
+			},
+		}, {
+			Attribute: "host.cpu.model",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "CPU Model",
+				Other: "CPU Models",
+			},
+		}, {
+			Attribute: "host.cpu.cores",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "CPU Cores",
+				Other: "CPU Cores",
+			},
+		}, {
+			Attribute: "host.cpu.threads",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "CPU Threads",
+				Other: "CPU Threads",
+			},
+		}, {
+			Attribute: "host.memory.total",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Total Memory (MiB)",
+				Other: "Total Memory (MiB)",
+			},
+		}, {
+			Attribute: "host.swap.total",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Total Swap (MiB)",
+				Other: "Total Swap (MiB)",
+			},
+		}, {
+			Attribute: "host.boot_time",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Boot Time",
+				Other: "Boot Times",
+			},
+		}, {
+			Attribute: "host.cgroup.version",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Cgroup Version",
+				Other: "Cgroup Versions",
+			},
+		}, {
+			Attribute: "host.virtualization",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Virtualization",
+				Other: "Virtualizations",
+			},
+		}, {
+			Attribute: "host.machine_id",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Machine ID",
+				Other: "Machine IDs",
+			},
 		},
 	}
 }
@@ -177,10 +365,18 @@ func (d *hostDiscovery) DiscoverTargets(ctx context.Context) ([]discovery_kit_ap
 		} else {
 			target.Attributes["host.domainname"] = []string{host.Info().Hostname}
 		}
+
+		addHostInfoAttributes(target.Attributes, host)
 	} else {
 		log.Error().Err(err).Msg("Failed to get host info")
 	}
 
+	addHardwareAttributes(ctx, target.Attributes)
+
+	for key, value := range d.cloudMetadata.get(ctx) {
+		target.Attributes[key] = value
+	}
+
 	// Get CPU frequency info
 	minFreq, maxFreq, err := cpufreq.GetCPUFrequencyInfo()
 	if err == nil {
@@ -190,6 +386,10 @@ func (d *hostDiscovery) DiscoverTargets(ctx context.Context) ([]discovery_kit_ap
 		log.Trace().Err(err).Msg("Failed to get CPU frequency info")
 	}
 
+	for key, value := range ProbeCapabilities() {
+		target.Attributes[key] = value
+	}
+
 	for key, value := range getEnvironmentVariables() {
 		target.Attributes["host.env."+key] = []string{value}
 	}
@@ -200,3 +400,81 @@ func (d *hostDiscovery) DiscoverTargets(ctx context.Context) ([]discovery_kit_ap
 	targets := []discovery_kit_api.Target{target}
 	return discovery_kit_commons.ApplyAttributeExcludes(targets, config.Config.DiscoveryAttributesExcludesHost), nil
 }
+
+func (c *cloudMetadataCache) get(ctx context.Context) map[string][]string {
+	if config.Config.CloudMetadataUrl == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	if c.fetching || (!c.fetchedAt.IsZero() && time.Since(c.fetchedAt) < cloudMetadataRefreshInterval) {
+		// while another call is fetching, the previous attributes are served instead of waiting for it
+		attributes := c.attributes
+		c.mu.Unlock()
+		return attributes
+	}
+	c.fetching = true
+	c.mu.Unlock()
+
+	var attributes map[string][]string
+	metadata, err := cloudmetadata.NewClient(config.Config.CloudMetadataUrl, cloudmetadata.DefaultTimeout).Fetch(ctx)
+	if err != nil {
+		log.Debug().Err(err).Msg("No cloud instance metadata available")
+	} else {
+		attributes = metadata.Attributes()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetching = false
+	c.attributes = attributes
+	c.fetchedAt = time.Now()
+	return attributes
+}
+
+func addHostInfoAttributes(attributes map[string][]string, host types.Host) {
+	info := host.Info()
+	if info.KernelVersion != "" {
+		attributes["host.kernel.release"] = []string{info.KernelVersion}
+	}
+	if arch := info.NativeArchitecture; arch != "" {
+		attributes["host.arch"] = []string{hostinfo.NormalizeArchitecture(arch)}
+	}
+	if !info.BootTime.IsZero() {
+		attributes["host.boot_time"] = []string{info.BootTime.UTC().Format(time.RFC3339)}
+	}
+	if info.UniqueID != "" {
+		attributes["host.machine_id"] = []string{info.UniqueID}
+	}
+
+	if memory, err := host.Memory(); err == nil {
+		attributes["host.memory.total"] = []string{strconv.FormatUint(memory.Total/mib, 10)}
+		attributes["host.swap.total"] = []string{strconv.FormatUint(memory.VirtualTotal/mib, 10)}
+	} else {
+		log.Trace().Err(err).Msg("Failed to get memory info")
+	}
+}
+
+func addHardwareAttributes(ctx context.Context, attributes map[string][]string) {
+	if cpu, err := hostinfo.ReadCPUInfo(); err == nil {
+		if cpu.Model != "" {
+			attributes["host.cpu.model"] = []string{cpu.Model}
+		}
+		attributes["host.cpu.cores"] = []string{strconv.Itoa(cpu.Cores)}
+		attributes["host.cpu.threads"] = []string{strconv.Itoa(cpu.Threads)}
+	} else {
+		log.Trace().Err(err).Msg("Failed to get CPU info")
+	}
+
+	if version, err := hostinfo.CgroupVersion(); err == nil {
+		attributes["host.cgroup.version"] = []string{version}
+	} else {
+		log.Trace().Err(err).Msg("Failed to get cgroup version")
+	}
+
+	if virt, err := hostinfo.Virtualization(ctx); err == nil {
+		attributes["host.virtualization"] = []string{virt}
+	} else {
+		log.Trace().Err(err).Msg("Failed to detect virtualization")
+	}
+}
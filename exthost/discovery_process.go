@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/discovery-kit/go/discovery_kit_api"
+	"github.com/steadybit/discovery-kit/go/discovery_kit_commons"
+	"github.com/steadybit/discovery-kit/go/discovery_kit_sdk"
+	"github.com/steadybit/extension-host/config"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/steadybit/extension-kit/extbuild"
+)
+
+type processDiscovery struct {
+}
+
+var (
+	_ discovery_kit_sdk.TargetDescriber    = (*processDiscovery)(nil)
+	_ discovery_kit_sdk.AttributeDescriber = (*processDiscovery)(nil)
+)
+
+func NewProcessDiscovery() discovery_kit_sdk.TargetDiscovery {
+	discovery := &processDiscovery{}
+	return discovery_kit_sdk.NewCachedTargetDiscovery(discovery,
+		discovery_kit_sdk.WithRefreshTargetsNow(),
+		discovery_kit_sdk.WithRefreshTargetsInterval(context.Background(), 30*time.Second),
+	)
+}
+
+func (d *processDiscovery) Describe() discovery_kit_api.DiscoveryDescription {
+	return discovery_kit_api.DiscoveryDescription{
+		Id: processTargetID,
+		Discover: discovery_kit_api.DescribingEndpointReferenceWithCallInterval{
+			CallInterval: new("30s"),
+		},
+	}
+}
+
+func (d *processDiscovery) DescribeTarget() discovery_kit_api.TargetDescription {
+	return discovery_kit_api.TargetDescription{
+		Id:      processTargetID,
+		Version: extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:    new(stopProcessIcon),
+
+		// Labels used in the UI
+		Label: discovery_kit_api.PluralLabel{One: "Process", Other: "Processes"},
+
+		// Category for the targets to appear in
+		Category: new("basic"),
+
+		// Specify attributes shown in table columns and to be used for sorting
+		Table: discovery_kit_api.Table{
+			Columns: []discovery_kit_api.Column{
+				{Attribute: "process.name"},
+				{Attribute: "process.pid"},
+				{Attribute: "host.hostname"},
+				{Attribute: "process.user"},
+			},
+			OrderBy: []discovery_kit_api.OrderBy{
+				{
+					Attribute: "process.name",
+					Direction: "ASC",
+				},
+			},
+		},
+	}
+}
+
+func (d *processDiscovery) DescribeAttributes() []discovery_kit_api.AttributeDescription {
+	return []discovery_kit_api.AttributeDescription{
+		{
+			Attribute: "process.pid",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "PID",
+				Other: "PIDs",
+			},
+		}, {
+			Attribute: "process.ppid",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Parent PID",
+				Other: "Parent PIDs",
+			},
+		}, {
+			Attribute: "process.name",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Process Name",
+				Other: "Process Names",
+			},
+		}, {
+			Attribute: "process.executable",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Executable",
+				Other: "Executables",
+			},
+		}, {
+			Attribute: "process.cmdline",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Command Line",
+				Other: "Command Lines",
+			},
+		}, {
+			Attribute: "process.user",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "User",
+				Other: "Users",
+			},
+		}, {
+			Attribute: "process.cgroup",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Control Group",
+				Other: "Control Groups",
+			},
+		}, {
+			Attribute: "process.systemd.unit",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Systemd Unit",
+				Other: "Systemd Units",
+			},
+		}, {
+			Attribute: "process.container.id",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Container ID",
+				Other: "Container IDs",
+			},
+		}, {
+			Attribute: "process.listening_port",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Listening Port",
+				Other: "Listening Ports",
+			},
+		},
+	}
+}
+
+func (d *processDiscovery) DiscoverTargets(_ context.Context) ([]discovery_kit_api.Target, error) {
+	hostname, _ := osHostname()
+
+	processes, err := listProcesses()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to list processes")
+		return []discovery_kit_api.Target{}, nil
+	}
+
+	startedBefore := time.Now().Add(-config.Config.DiscoveryProcessMinAge)
+	targets := make([]discovery_kit_api.Target, 0, len(processes))
+	for _, p := range processes {
+		if p.KernelThread || p.StartedAt.After(startedBefore) {
+			continue
+		}
+
+		pid := strconv.Itoa(p.Pid)
+		attributes := map[string][]string{
+			"host.hostname": {hostname},
+			"process.pid":   {pid},
+			"process.ppid":  {strconv.Itoa(p.PPid)},
+			"process.name":  {p.Name},
+		}
+		if p.Executable != "" {
+			attributes["process.executable"] = []string{p.Executable}
+		}
+		if config.Config.DiscoveryProcessCmdline && len(p.Cmdline) > 0 {
+			attributes["process.cmdline"] = []string{strings.Join(redactCmdline(p.Cmdline), " ")}
+		}
+		if p.User != "" {
+			attributes["process.user"] = []string{p.User}
+		}
+		if p.Cgroup != "" {
+			attributes["process.cgroup"] = []string{p.Cgroup}
+		}
+		if p.SystemdUnit != "" {
+			attributes["process.systemd.unit"] = []string{p.SystemdUnit}
+		}
+		if p.ContainerId != "" {
+			attributes["process.container.id"] = []string{p.ContainerId}
+		}
+		for _, port := range p.ListeningPorts {
+			attributes["process.listening_port"] = append(attributes["process.listening_port"], strconv.Itoa(port))
+		}
+		for key, value := range getLabels() {
+			attributes["host.label."+key] = []string{value}
+		}
+
+		targets = append(targets, discovery_kit_api.Target{
+			Id:         fmt.Sprintf("%s/%s", hostname, pid),
+			TargetType: processTargetID,
+			Label:      fmt.Sprintf("%s (%s)", p.Name, pid),
+			Attributes: attributes,
+		})
+	}
+	return discovery_kit_commons.ApplyAttributeExcludes(targets, config.Config.DiscoveryAttributesExcludesProcess), nil
+}
+
+var listProcesses = stopprocess.ListProcesses
+
+const redactedValue = "***"
+
+var (
+	secretArgument   = regexp.MustCompile(`(?i)(pass|pwd|secret|token|key|credential|auth)`)
+	credentialsInUrl = regexp.MustCompile(`(://[^:/@\s]+):[^@/\s]+@`)
+)
+
+// redactCmdline replaces the values of arguments looking like secrets, e.g. --password=x, --token x or
+// credentials in URLs, as the command line is sent to the platform.
+func redactCmdline(cmdline []string) []string {
+	redacted := make([]string, len(cmdline))
+	for i, arg := range cmdline {
+		if i > 0 && isSecretFlag(cmdline[i-1]) {
+			redacted[i] = redactedValue
+			continue
+		}
+		if name, _, ok := strings.Cut(arg, "="); ok && secretArgument.MatchString(name) {
+			redacted[i] = name + "=" + redactedValue
+			continue
+		}
+		redacted[i] = credentialsInUrl.ReplaceAllString(arg, "${1}:"+redactedValue+"@")
+	}
+	return redacted
+}
+
+// isSecretFlag tells whether the argument is a flag named like a secret, which takes its value from the
+// next argument.
+func isSecretFlag(arg string) bool {
+	return strings.HasPrefix(arg, "-") && !strings.Contains(arg, "=") && secretArgument.MatchString(arg)
+}
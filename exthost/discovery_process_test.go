@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steadybit/extension-host/config"
+	stopprocess "github.com/steadybit/extension-host/exthost/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiscoverProcesses(t *testing.T) {
+	osHostname = func() (string, error) { return "myhost", nil }
+	config.Config.DiscoveryProcessMinAge = time.Minute
+	longAgo := time.Now().Add(-time.Hour)
+	listProcesses = func() ([]stopprocess.Process, error) {
+		return []stopprocess.Process{
+			{Pid: 2, Name: "kthreadd", KernelThread: true, StartedAt: longAgo},
+			{
+				Pid:            812,
+				PPid:           1,
+				Name:           "nginx",
+				Executable:     "/usr/sbin/nginx",
+				Cmdline:        []string{"nginx: master process", "-g", "daemon off;"},
+				User:           "root",
+				Cgroup:         "/system.slice/nginx.service",
+				SystemdUnit:    "nginx.service",
+				StartedAt:      longAgo,
+				ListeningPorts: []int{80, 443},
+			},
+			{Pid: 4711, Name: "sleep", StartedAt: time.Now()},
+		}, nil
+	}
+	defer func() { listProcesses = stopprocess.ListProcesses }()
+
+	targets, err := (&processDiscovery{}).DiscoverTargets(context.Background())
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+
+	nginx := targets[0]
+	assert.Equal(t, "myhost/812", nginx.Id)
+	assert.Equal(t, "nginx (812)", nginx.Label)
+	assert.Equal(t, processTargetID, nginx.TargetType)
+	assert.Equal(t, []string{"myhost"}, nginx.Attributes["host.hostname"])
+	assert.Equal(t, []string{"812"}, nginx.Attributes["process.pid"])
+	assert.Equal(t, []string{"1"}, nginx.Attributes["process.ppid"])
+	assert.Equal(t, []string{"/usr/sbin/nginx"}, nginx.Attributes["process.executable"])
+	assert.Equal(t, []string{"root"}, nginx.Attributes["process.user"])
+	assert.Equal(t, []string{"nginx.service"}, nginx.Attributes["process.systemd.unit"])
+	assert.Equal(t, []string{"80", "443"}, nginx.Attributes["process.listening_port"])
+	assert.NotContains(t, nginx.Attributes, "process.container.id")
+	assert.NotContains(t, nginx.Attributes, "process.cmdline")
+
+	config.Config.DiscoveryProcessCmdline = true
+	defer func() { config.Config.DiscoveryProcessCmdline = false }()
+	targets, err = (&processDiscovery{}).DiscoverTargets(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nginx: master process -g daemon off;"}, targets[0].Attributes["process.cmdline"])
+}
+
+func Test_redactCmdline(t *testing.T) {
+	assert.Equal(t,
+		[]string{"app", "--password=***", "--db-token", "***", "-v", "--url=postgres://app:***@db:5432/app", "API_KEY=***", "--port", "8080"},
+		redactCmdline([]string{"app", "--password=hunter2", "--db-token", "abc", "-v", "--url=postgres://app:hunter2@db:5432/app", "API_KEY=xyz", "--port", "8080"}),
+	)
+}
+
+func Test_DiscoverProcesses_Failing(t *testing.T) {
+	listProcesses = func() ([]stopprocess.Process, error) {
+		return nil, errors.New("permission denied")
+	}
+	defer func() { listProcesses = stopprocess.ListProcesses }()
+
+	targets, err := (&processDiscovery{}).DiscoverTargets(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, targets)
+}
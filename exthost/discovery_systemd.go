@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/discovery-kit/go/discovery_kit_api"
+	"github.com/steadybit/discovery-kit/go/discovery_kit_commons"
+	"github.com/steadybit/discovery-kit/go/discovery_kit_sdk"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/systemd"
+	"github.com/steadybit/extension-kit/extbuild"
+)
+
+type systemdServiceDiscovery struct {
+}
+
+var (
+	_ discovery_kit_sdk.TargetDescriber    = (*systemdServiceDiscovery)(nil)
+	_ discovery_kit_sdk.AttributeDescriber = (*systemdServiceDiscovery)(nil)
+)
+
+func NewSystemdServiceDiscovery() discovery_kit_sdk.TargetDiscovery {
+	discovery := &systemdServiceDiscovery{}
+	return discovery_kit_sdk.NewCachedTargetDiscovery(discovery,
+		discovery_kit_sdk.WithRefreshTargetsNow(),
+		discovery_kit_sdk.WithRefreshTargetsInterval(context.Background(), 30*time.Second),
+	)
+}
+
+func (d *systemdServiceDiscovery) Describe() discovery_kit_api.DiscoveryDescription {
+	return discovery_kit_api.DiscoveryDescription{
+		Id: systemdUnitTargetID,
+		Discover: discovery_kit_api.DescribingEndpointReferenceWithCallInterval{
+			CallInterval: new("30s"),
+		},
+	}
+}
+
+func (d *systemdServiceDiscovery) DescribeTarget() discovery_kit_api.TargetDescription {
+	return discovery_kit_api.TargetDescription{
+		Id:      systemdUnitTargetID,
+		Version: extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:    new(systemdUnitIcon),
+
+		// Labels used in the UI
+		Label: discovery_kit_api.PluralLabel{One: "Systemd Service", Other: "Systemd Services"},
+
+		// Category for the targets to appear in
+		Category: new("basic"),
+
+		// Specify attributes shown in table columns and to be used for sorting
+		Table: discovery_kit_api.Table{
+			Columns: []discovery_kit_api.Column{
+				{Attribute: "systemd.unit.name"},
+				{Attribute: "host.hostname"},
+				{Attribute: "systemd.unit.active_state"},
+			},
+			OrderBy: []discovery_kit_api.OrderBy{
+				{
+					Attribute: "systemd.unit.name",
+					Direction: "ASC",
+				},
+			},
+		},
+	}
+}
+
+func (d *systemdServiceDiscovery) DescribeAttributes() []discovery_kit_api.AttributeDescription {
+	return []discovery_kit_api.AttributeDescription{
+		{
+			Attribute: "systemd.unit.name",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Systemd Unit",
+				Other: "Systemd Units",
+			},
+		}, {
+			Attribute: "systemd.unit.description",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Systemd Unit Description",
+				Other: "Systemd Unit Descriptions",
+			},
+		}, {
+			Attribute: "systemd.unit.active_state",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Active State",
+				Other: "Active States",
+			},
+		}, {
+			Attribute: "systemd.unit.sub_state",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Sub State",
+				Other: "Sub States",
+			},
+		}, {
+			Attribute: "systemd.unit.main_pid",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Main PID",
+				Other: "Main PIDs",
+			},
+		}, {
+			Attribute: "systemd.unit.cgroup",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Control Group",
+				Other: "Control Groups",
+			},
+		}, {
+			Attribute: "systemd.unit.restart",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Restart Policy",
+				Other: "Restart Policies",
+			},
+		}, {
+			Attribute: "systemd.unit.unit_file_state",
+			Label: discovery_kit_api.PluralLabel{
+				One:   "Unit File State",
+				Other: "Unit File States",
+			},
+		},
+	}
+}
+
+func (d *systemdServiceDiscovery) DiscoverTargets(ctx context.Context) ([]discovery_kit_api.Target, error) {
+	hostname, _ := osHostname()
+
+	units, err := listSystemdServices(ctx)
+	if err != nil {
+		// hosts without systemd (or containers without access to it) simply have no services
+		log.Debug().Err(err).Msg("Failed to list systemd services")
+		return []discovery_kit_api.Target{}, nil
+	}
+
+	targets := make([]discovery_kit_api.Target, 0, len(units))
+	for _, unit := range units {
+		attributes := map[string][]string{
+			"host.hostname":                {hostname},
+			"systemd.unit.name":            {unit.Name},
+			"systemd.unit.active_state":    {unit.ActiveState},
+			"systemd.unit.sub_state":       {unit.SubState},
+			"systemd.unit.restart":         {unit.Restart},
+			"systemd.unit.description":     {unit.Description},
+			"systemd.unit.unit_file_state": {unit.UnitFileState},
+		}
+		if unit.MainPID > 0 {
+			attributes["systemd.unit.main_pid"] = []string{strconv.Itoa(unit.MainPID)}
+		}
+		if unit.ControlGroup != "" {
+			attributes["systemd.unit.cgroup"] = []string{unit.ControlGroup}
+		}
+		for key, value := range getLabels() {
+			attributes["host.label."+key] = []string{value}
+		}
+
+		targets = append(targets, discovery_kit_api.Target{
+			Id:         fmt.Sprintf("%s/%s", hostname, unit.Name),
+			TargetType: systemdUnitTargetID,
+			Label:      unit.Name,
+			Attributes: attributes,
+		})
+	}
+	return discovery_kit_commons.ApplyAttributeExcludes(targets, config.Config.DiscoveryAttributesExcludesSystemd), nil
+}
+
+var listSystemdServices = systemd.ListServices
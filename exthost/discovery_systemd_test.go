@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steadybit/extension-host/exthost/systemd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiscoverSystemdServices(t *testing.T) {
+	osHostname = func() (string, error) { return "myhost", nil }
+	listSystemdServices = func(context.Context) ([]systemd.Unit, error) {
+		return []systemd.Unit{
+			{Name: "cron.service", ActiveState: "active", SubState: "running", MainPID: 612, ControlGroup: "/system.slice/cron.service", Restart: "on-failure", UnitFileState: "enabled"},
+			{Name: "backup.service", ActiveState: "inactive", SubState: "dead", Restart: "no", UnitFileState: "static"},
+		}, nil
+	}
+	defer func() { listSystemdServices = systemd.ListServices }()
+
+	targets, err := (&systemdServiceDiscovery{}).DiscoverTargets(context.Background())
+	require.NoError(t, err)
+	require.Len(t, targets, 2)
+
+	cron := targets[0]
+	assert.Equal(t, "myhost/cron.service", cron.Id)
+	assert.Equal(t, "cron.service", cron.Label)
+	assert.Equal(t, systemdUnitTargetID, cron.TargetType)
+	assert.Equal(t, []string{"myhost"}, cron.Attributes["host.hostname"])
+	assert.Equal(t, []string{"cron.service"}, cron.Attributes["systemd.unit.name"])
+	assert.Equal(t, []string{"active"}, cron.Attributes["systemd.unit.active_state"])
+	assert.Equal(t, []string{"612"}, cron.Attributes["systemd.unit.main_pid"])
+	assert.Equal(t, []string{"/system.slice/cron.service"}, cron.Attributes["systemd.unit.cgroup"])
+	assert.Equal(t, []string{"on-failure"}, cron.Attributes["systemd.unit.restart"])
+
+	backup := targets[1]
+	assert.NotContains(t, backup.Attributes, "systemd.unit.main_pid")
+	assert.NotContains(t, backup.Attributes, "systemd.unit.cgroup")
+}
+
+func Test_DiscoverSystemdServices_WithoutSystemd(t *testing.T) {
+	listSystemdServices = func(context.Context) ([]systemd.Unit, error) {
+		return nil, errors.New("systemctl: executable file not found in $PATH")
+	}
+	defer func() { listSystemdServices = systemd.ListServices }()
+
+	targets, err := (&systemdServiceDiscovery{}).DiscoverTargets(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, targets)
+}
@@ -5,6 +5,9 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/extension-host/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 )
@@ -33,8 +36,41 @@ func Test_DiscoverTargets(t *testing.T) {
 	assert.NotEmpty(t, attributes["host.os.family"])
 	assert.NotEmpty(t, attributes["host.os.manufacturer"])
 	assert.NotEmpty(t, attributes["host.os.version"])
+	assert.NotEmpty(t, attributes["host.kernel.release"])
+	assert.NotEmpty(t, attributes["host.arch"])
+	assert.NotEmpty(t, attributes["host.cpu.threads"])
+	assert.NotEmpty(t, attributes["host.memory.total"])
+	assert.NotEmpty(t, attributes["host.boot_time"])
 	assert.Equal(t, attributes["host.label.foo"], []string{"Bar"})
 	assert.Equal(t, attributes["host.env.myenvvar"], []string{"MyEnvVarValue"})
 	assert.Equal(t, attributes["host.env.myenvvar2"], []string{"MyEnvVarValue2"})
 	assert.Equal(t, attributes["host.env.myenvvar3"], []string{"MyEnvVarValue3"})
 }
+
+func Test_DiscoverTargets_CloudMetadata(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metadata/instance", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"compute":{"location":"westeurope","zone":"1","vmId":"vm-1","vmSize":"Standard_B2s","subscriptionId":"sub-1"}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	config.Config.CloudMetadataUrl = server.URL
+	defer func() { config.Config.CloudMetadataUrl = "" }()
+
+	targets, err := (&hostDiscovery{}).DiscoverTargets(context.Background())
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, []string{"westeurope-1"}, targets[0].Attributes["azure.zone"])
+	assert.Equal(t, []string{"westeurope"}, targets[0].Attributes["azure.region"])
+	assert.Equal(t, []string{"vm-1"}, targets[0].Attributes["azure.instance.id"])
+	assert.Equal(t, []string{"sub-1"}, targets[0].Attributes["azure.subscription.id"])
+}
+
+func Test_CloudMetadataCache_ServesPreviousAttributesWhileFetching(t *testing.T) {
+	config.Config.CloudMetadataUrl = "http://127.0.0.1:1"
+	defer func() { config.Config.CloudMetadataUrl = "" }()
+
+	cache := cloudMetadataCache{fetching: true, attributes: map[string][]string{"aws.zone": {"eu-central-1a"}}}
+
+	assert.Equal(t, map[string][]string{"aws.zone": {"eu-central-1a"}}, cache.get(context.Background()))
+}
@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost/journal"
+)
+
+// reverter reverts the faults of an execution using only the state that was recorded for it.
+type reverter func(ctx context.Context, state json.RawMessage) error
+
+type activeExecution struct {
+	ActionId  string
+	State     json.RawMessage
+	StartedAt time.Time
+}
+
+// RevertReport is the outcome of reverting a single execution.
+type RevertReport struct {
+	ExecutionId uuid.UUID `json:"executionId"`
+	ActionId    string    `json:"actionId"`
+	StartedAt   time.Time `json:"startedAt"`
+	Reverted    bool      `json:"reverted"`
+	Error       string    `json:"error,omitempty"`
+}
+
+var (
+	executionJournal = sync.OnceValue(func() *journal.Journal {
+		return journal.New(config.Config.JournalDir)
+	})
+	reverters        sync.Map // action id -> reverter
+	activeExecutions sync.Map // execution id -> activeExecution
+	// revertedFromJournal holds the executions of a previous run which were reverted from the journal. The
+	// agent may still call Status and Stop for them, which the action_kit_sdk of this run knows nothing about.
+	revertedFromJournal sync.Map // execution id -> time reverted
+)
+
+// journaled registers the Stop of the action as reverter for journal entries left behind by a previous run.
+// Stop must therefore work with the recorded state alone and must be idempotent, as the agent may still
+// stop such an execution after it was reverted.
+func journaled[T any](a action_kit_sdk.ActionWithStop[T]) action_kit_sdk.ActionWithStop[T] {
+	return tracked(a)
+}
+
+// tracked registers the Stop of the action as reverter for its active executions. RevertAllExecutions uses
+// it to find out why an execution couldn't be stopped.
+func tracked[T any](a action_kit_sdk.ActionWithStop[T]) action_kit_sdk.ActionWithStop[T] {
+	registerReverter(a.Describe().Id, func(ctx context.Context, raw json.RawMessage) error {
+		state := a.NewEmptyState()
+		if err := json.Unmarshal(raw, &state); err != nil {
+			return err
+		}
+		result, err := a.Stop(ctx, &state)
+		if err == nil && result != nil && result.Error != nil {
+			return errors.New(result.Error.Title)
+		}
+		return err
+	})
+	return a
+}
+
+func registerReverter(actionId string, revert reverter) {
+	reverters.Store(actionId, revert)
+}
+
+// recordExecution tracks the execution as active and writes its revert information to the journal. A
+// failing journal must not fail the attack, so errors are only logged.
+func recordExecution(executionId uuid.UUID, actionId string, state any) {
+	trackExecution(executionId, actionId, state)
+	if err := executionJournal().Record(executionId, actionId, state); err != nil {
+		log.Warn().Err(err).Str("executionId", executionId.String()).Msg("failed to record execution in journal")
+	}
+}
+
+// trackExecution tracks the execution as active without journaling it. Used by actions whose effects end
+// together with the extension process, e.g. the stress and the process stoppers.
+func trackExecution(executionId uuid.UUID, actionId string, state any) {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		log.Warn().Err(err).Str("executionId", executionId.String()).Msg("failed to serialize execution state")
+		return
+	}
+
+	startedAt := time.Now()
+	if previous, ok := activeExecutions.Load(executionId); ok {
+		startedAt = previous.(activeExecution).StartedAt
+	}
+	activeExecutions.Store(executionId, activeExecution{ActionId: actionId, State: raw, StartedAt: startedAt})
+}
+
+func forgetExecution(executionId uuid.UUID) {
+	activeExecutions.Delete(executionId)
+	if err := executionJournal().Remove(executionId); err != nil {
+		log.Warn().Err(err).Str("executionId", executionId.String()).Msg("failed to remove execution from journal")
+	}
+}
+
+// revertedOnStartup reports whether the execution was left behind by a previous run and has been reverted
+// from the journal since.
+func revertedOnStartup(executionId uuid.UUID) bool {
+	_, ok := revertedFromJournal.Load(executionId)
+	return ok
+}
+
+// RevertJournaledExecutions reverts all executions which are still in the journal, e.g. because the
+// extension crashed during an attack. Entries which can't be reverted are kept, so they are retried on
+// the next start and by RevertAllExecutions.
+func RevertJournaledExecutions(ctx context.Context) {
+	entries, err := executionJournal().Entries()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read journal")
+		return
+	}
+
+	for _, entry := range entries {
+		logger := log.With().Str("actionId", entry.ActionId).Str("executionId", entry.ExecutionId.String()).Logger()
+		logger.Warn().Time("startedAt", entry.CreatedAt).Msg("reverting execution left behind by previous run")
+
+		if err := revertEntry(ctx, entry); err != nil {
+			logger.Error().Err(err).Msg("failed to revert journaled execution")
+		}
+	}
+}
+
+// RevertAllExecutions is the emergency stop. Every active execution is stopped through the action_kit_sdk,
+// which tells the agent that it was stopped by the extension. An execution counts as reverted once its Stop
+// forgot it. The sdk doesn't pass on the error of a failed Stop, so Stop is repeated from the tracked state
+// to report it. Journal entries which are left afterward, e.g. of a previous run, are reverted from the
+// journal. The outcome is reported for every execution.
+func RevertAllExecutions(ctx context.Context) []RevertReport {
+	var candidates []RevertReport
+	activeExecutions.Range(func(key, value any) bool {
+		execution := value.(activeExecution)
+		candidates = append(candidates, RevertReport{ExecutionId: key.(uuid.UUID), ActionId: execution.ActionId, StartedAt: execution.StartedAt})
+		return true
+	})
+	sort.Slice(candidates, func(i, k int) bool {
+		return candidates[i].StartedAt.Before(candidates[k].StartedAt)
+	})
+
+	reports := make([]RevertReport, 0, len(candidates))
+	for _, report := range candidates {
+		logger := log.With().Str("actionId", report.ActionId).Str("executionId", report.ExecutionId.String()).Logger()
+		logger.Warn().Msg("stopping execution on request")
+
+		action_kit_sdk.StopAction(ctx, report.ExecutionId, "revert-all requested")
+		if value, active := activeExecutions.Load(report.ExecutionId); active {
+			execution := value.(activeExecution)
+			if err := revertExecution(ctx, report.ExecutionId, execution.ActionId, execution.State); err != nil {
+				logger.Error().Err(err).Msg("failed to stop execution")
+				report.Error = err.Error()
+			}
+		}
+		report.Reverted = report.Error == ""
+		reports = append(reports, report)
+	}
+
+	entries, err := executionJournal().Entries()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read journal")
+	}
+	for _, entry := range entries {
+		if slices.ContainsFunc(reports, func(r RevertReport) bool { return r.ExecutionId == entry.ExecutionId }) {
+			continue
+		}
+
+		report := RevertReport{ExecutionId: entry.ExecutionId, ActionId: entry.ActionId, StartedAt: entry.CreatedAt}
+		log.Warn().Str("actionId", entry.ActionId).Str("executionId", entry.ExecutionId.String()).Msg("reverting journaled execution on request")
+		if err := revertEntry(ctx, entry); err != nil {
+			log.Error().Err(err).Str("executionId", entry.ExecutionId.String()).Msg("failed to revert execution")
+			report.Error = err.Error()
+		}
+		report.Reverted = report.Error == ""
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+func revertEntry(ctx context.Context, entry journal.Entry) error {
+	if err := revertExecution(ctx, entry.ExecutionId, entry.ActionId, entry.State); err != nil {
+		return err
+	}
+	revertedFromJournal.Store(entry.ExecutionId, time.Now())
+	return nil
+}
+
+func revertExecution(ctx context.Context, executionId uuid.UUID, actionId string, state json.RawMessage) error {
+	revert, ok := reverters.Load(actionId)
+	if !ok {
+		return fmt.Errorf("no reverter registered for action %s", actionId)
+	}
+
+	if err := revert.(reverter)(ctx, state); err != nil {
+		return err
+	}
+	forgetExecution(executionId)
+	return nil
+}
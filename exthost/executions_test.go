@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/extension-host/exthost/journal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRevertState struct {
+	Value string
+}
+
+func useTestJournal(t *testing.T) *journal.Journal {
+	j := journal.New(t.TempDir())
+	saved := executionJournal
+	executionJournal = func() *journal.Journal { return j }
+	t.Cleanup(func() {
+		executionJournal = saved
+		activeExecutions.Clear()
+	})
+	return j
+}
+
+func TestRevertAllExecutions(t *testing.T) {
+	j := useTestJournal(t)
+	var reverted []string
+	registerReverter("test.revert-ok", func(_ context.Context, raw json.RawMessage) error {
+		var state testRevertState
+		require.NoError(t, json.Unmarshal(raw, &state))
+		reverted = append(reverted, state.Value)
+		return nil
+	})
+	registerReverter("test.revert-fail", func(_ context.Context, _ json.RawMessage) error {
+		return errors.New("device busy")
+	})
+
+	ok, tracked, failing, unknown, previousRun := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	recordExecution(ok, "test.revert-ok", testRevertState{Value: "first"})
+	time.Sleep(time.Millisecond)
+	trackExecution(tracked, "test.revert-ok", testRevertState{Value: "not journaled"})
+	time.Sleep(time.Millisecond)
+	recordExecution(failing, "test.revert-fail", testRevertState{})
+	time.Sleep(time.Millisecond)
+	recordExecution(unknown, "test.unknown", testRevertState{})
+	require.NoError(t, j.Record(previousRun, "test.revert-ok", testRevertState{Value: "previous run"}))
+
+	reports := RevertAllExecutions(context.Background())
+	require.Len(t, reports, 5)
+
+	assert.Equal(t, ok, reports[0].ExecutionId)
+	assert.Equal(t, "test.revert-ok", reports[0].ActionId)
+	assert.True(t, reports[0].Reverted)
+	assert.Empty(t, reports[0].Error)
+
+	assert.Equal(t, tracked, reports[1].ExecutionId)
+	assert.True(t, reports[1].Reverted)
+
+	assert.Equal(t, failing, reports[2].ExecutionId)
+	assert.False(t, reports[2].Reverted)
+	assert.Equal(t, "device busy", reports[2].Error)
+
+	assert.Equal(t, unknown, reports[3].ExecutionId)
+	assert.False(t, reports[3].Reverted)
+	assert.Contains(t, reports[3].Error, "no reverter registered")
+
+	assert.Equal(t, previousRun, reports[4].ExecutionId)
+	assert.True(t, reports[4].Reverted)
+
+	assert.Equal(t, []string{"first", "not journaled", "previous run"}, reverted)
+	assert.False(t, revertedOnStartup(ok))
+	assert.True(t, revertedOnStartup(previousRun))
+
+	// failed executions are kept and retried on the next request
+	reports = RevertAllExecutions(context.Background())
+	require.Len(t, reports, 2)
+	assert.Equal(t, failing, reports[0].ExecutionId)
+	assert.False(t, reports[0].Reverted)
+	assert.Equal(t, []string{"first", "not journaled", "previous run"}, reverted)
+}
+
+func TestRevertAllExecutionsStopsTrackedAction(t *testing.T) {
+	useTestJournal(t)
+	executionId := uuid.New()
+	a := tracked[StopProcessActionState](&stopProcessAction{targetType: targetID})
+	trackExecution(executionId, a.Describe().Id, StopProcessActionState{ExecutionId: executionId})
+
+	reports := RevertAllExecutions(context.Background())
+	require.Len(t, reports, 1)
+	assert.True(t, reports[0].Reverted)
+	_, active := activeExecutions.Load(executionId)
+	assert.False(t, active)
+}
+
+func TestRevertJournaledExecutions(t *testing.T) {
+	j := useTestJournal(t)
+	var reverted []string
+	registerReverter("test.journaled", func(_ context.Context, raw json.RawMessage) error {
+		var state testRevertState
+		require.NoError(t, json.Unmarshal(raw, &state))
+		reverted = append(reverted, state.Value)
+		return nil
+	})
+
+	executionId := uuid.New()
+	recordExecution(executionId, "test.journaled", testRevertState{Value: "left behind"})
+
+	RevertJournaledExecutions(context.Background())
+	assert.Equal(t, []string{"left behind"}, reverted)
+	assert.True(t, revertedOnStartup(executionId))
+
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestForgetExecution(t *testing.T) {
+	j := useTestJournal(t)
+	executionId := uuid.New()
+	recordExecution(executionId, "test.forget", testRevertState{})
+	forgetExecution(executionId)
+
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.False(t, revertedOnStartup(executionId))
+}
@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package fdfill exhausts file handles. The handles are held by the `fd-fill` subcommand of the
+// extension, which is started directly or as runc sidecar like stress-ng.
+package fdfill
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/extension-host/exthost/holder"
+)
+
+type Opts struct {
+	// Count is the number of file handles to open, 0 opens handles until MaxUsage is reached
+	Count int
+	// MaxUsage stops opening handles once this percentage of fs.file-max is allocated, 0 means no limit
+	MaxUsage int
+}
+
+func (o Opts) Args() []string {
+	return []string{"fd-fill", "-count", strconv.Itoa(o.Count), "-max-usage", strconv.Itoa(o.MaxUsage)}
+}
+
+func NewFdfillProcess(opts Opts) (holder.Holder, error) {
+	return holder.NewProcess(opts.Args())
+}
+
+func NewFdfillRunc(ctx context.Context, r ociruntime.OciRuntime, sidecar holder.SidecarOpts, opts Opts) (holder.Holder, error) {
+	// raising RLIMIT_NOFILE up to fs.nr_open
+	return holder.NewRunc(ctx, r, sidecar, opts.Args(), "CAP_SYS_RESOURCE")
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package fdfill
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/steadybit/extension-host/exthost/holder"
+)
+
+var procFileNr = "/proc/sys/fs/file-nr"
+
+// usageCheckInterval is the number of handles opened between two checks of fs.file-nr.
+const usageCheckInterval = 1000
+
+type FileNr struct {
+	Allocated uint64
+	Max       uint64
+}
+
+// Usage is the allocated share of fs.file-max in percent.
+func (n FileNr) Usage() float64 {
+	if n.Max == 0 {
+		return 0
+	}
+	return float64(n.Allocated) * 100 / float64(n.Max)
+}
+
+// ReadFileNr reads the system-wide file handle usage from /proc/sys/fs/file-nr.
+func ReadFileNr() (FileNr, error) {
+	content, err := os.ReadFile(procFileNr)
+	if err != nil {
+		return FileNr{}, err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) != 3 {
+		return FileNr{}, fmt.Errorf("unexpected content of %s: %s", procFileNr, content)
+	}
+	allocated, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return FileNr{}, err
+	}
+	maximum, err := strconv.ParseUint(fields[2], 10, 64)
+	if err != nil {
+		return FileNr{}, err
+	}
+	return FileNr{Allocated: allocated, Max: maximum}, nil
+}
+
+// Run implements the `fd-fill` subcommand. It opens file handles, holds them until it is terminated and
+// returns the exit code.
+func Run(args []string) int {
+	flags := flag.NewFlagSet("fd-fill", flag.ContinueOnError)
+	count := flags.Int("count", 0, "number of file handles to open, 0 for no limit")
+	maxUsage := flags.Int("max-usage", 0, "stop opening handles at this percentage of fs.file-max, 0 for no limit")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *count <= 0 && *maxUsage <= 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "either -count or -max-usage is required")
+		return 2
+	}
+
+	terminated := make(chan os.Signal, 1)
+	signal.Notify(terminated, syscall.SIGTERM, syscall.SIGINT)
+
+	holder.RaiseNofileLimit()
+	fds, limit, err := fill(*count, *maxUsage)
+	defer release(fds)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to open file handles: %s\n", err)
+		return 1
+	}
+	if limit != nil {
+		fmt.Printf("%s %d file handles, %s: %s\n", holdingPrefix, len(fds), notReached, limit)
+	} else {
+		fmt.Printf("%s %d file handles\n", holdingPrefix, len(fds))
+	}
+
+	<-terminated
+	return 0
+}
+
+// fill opens handles to /dev/null until the count or the usage is reached. Hitting the per-process or
+// system-wide limit isn't an error, that's what the attack is about, but it is returned as limit as the
+// target wasn't reached. A single process can't hold more than fs.nr_open handles, so a usage of a huge
+// fs.file-max (e.g. LONG_MAX set by systemd) is out of reach.
+func fill(count, maxUsage int) (fds []int, limit error, err error) {
+	for count <= 0 || len(fds) < count {
+		if maxUsage > 0 && len(fds)%usageCheckInterval == 0 {
+			if nr, err := ReadFileNr(); err != nil {
+				return fds, nil, err
+			} else if nr.Usage() >= float64(maxUsage) {
+				break
+			}
+		}
+		fd, err := syscall.Open(os.DevNull, syscall.O_RDONLY|syscall.O_CLOEXEC, 0)
+		if errors.Is(err, syscall.EMFILE) || errors.Is(err, syscall.ENFILE) {
+			return fds, err, nil
+		} else if err != nil {
+			return fds, nil, err
+		}
+		fds = append(fds, fd)
+	}
+	return fds, nil, nil
+}
+
+const (
+	holdingPrefix = "holding"
+	notReached    = "target not reached"
+)
+
+// ParseOutput reads the stdout of the `fd-fill` subcommand. done is true once it stopped opening handles,
+// reached is false if it hit a limit before the count or usage was reached.
+func ParseOutput(output string) (line string, done bool, reached bool) {
+	for l := range strings.Lines(output) {
+		if strings.HasPrefix(l, holdingPrefix) {
+			line = strings.TrimSpace(l)
+			return line, true, !strings.Contains(line, notReached)
+		}
+	}
+	return "", false, false
+}
+
+func release(fds []int) {
+	for _, fd := range fds {
+		_ = syscall.Close(fd)
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package fdfill
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFileNr(t *testing.T) {
+	procFileNr = filepath.Join(t.TempDir(), "file-nr")
+	defer func() { procFileNr = "/proc/sys/fs/file-nr" }()
+
+	require.NoError(t, os.WriteFile(procFileNr, []byte("8000\t0\t10000\n"), 0644))
+	nr, err := ReadFileNr()
+	require.NoError(t, err)
+	assert.Equal(t, FileNr{Allocated: 8000, Max: 10000}, nr)
+	assert.Equal(t, 80.0, nr.Usage())
+
+	require.NoError(t, os.WriteFile(procFileNr, []byte("8000\n"), 0644))
+	_, err = ReadFileNr()
+	assert.ErrorContains(t, err, "unexpected content")
+}
+
+func TestFill(t *testing.T) {
+	fds, limit, err := fill(10, 0)
+	require.NoError(t, err)
+	defer release(fds)
+	assert.NoError(t, limit)
+	assert.Len(t, fds, 10)
+}
+
+func TestFillStopsAtMaxUsage(t *testing.T) {
+	procFileNr = filepath.Join(t.TempDir(), "file-nr")
+	defer func() { procFileNr = "/proc/sys/fs/file-nr" }()
+	require.NoError(t, os.WriteFile(procFileNr, []byte("9500\t0\t10000\n"), 0644))
+
+	fds, limit, err := fill(0, 90)
+	require.NoError(t, err)
+	defer release(fds)
+	assert.NoError(t, limit)
+	assert.Empty(t, fds)
+}
+
+func TestFillReturnsLimit(t *testing.T) {
+	var original syscall.Rlimit
+	require.NoError(t, syscall.Getrlimit(syscall.RLIMIT_NOFILE, &original))
+	defer func() { _ = syscall.Setrlimit(syscall.RLIMIT_NOFILE, &original) }()
+	require.NoError(t, syscall.Setrlimit(syscall.RLIMIT_NOFILE, &syscall.Rlimit{Cur: 64, Max: original.Max}))
+
+	fds, limit, err := fill(1000, 0)
+	require.NoError(t, err)
+	defer release(fds)
+	assert.ErrorIs(t, limit, syscall.EMFILE)
+	assert.Less(t, len(fds), 64)
+}
+
+func TestParseOutput(t *testing.T) {
+	line, done, _ := ParseOutput("")
+	assert.False(t, done)
+	assert.Empty(t, line)
+
+	line, done, reached := ParseOutput("holding 5000 file handles\n")
+	assert.True(t, done)
+	assert.True(t, reached)
+	assert.Equal(t, "holding 5000 file handles", line)
+
+	line, done, reached = ParseOutput("holding 1048560 file handles, target not reached: too many open files\n")
+	assert.True(t, done)
+	assert.False(t, reached)
+	assert.Equal(t, "holding 1048560 file handles, target not reached: too many open files", line)
+}
+
+func TestOptsArgs(t *testing.T) {
+	assert.Equal(t, []string{"fd-fill", "-count", "5000", "-max-usage", "80"}, Opts{Count: 5000, MaxUsage: 80}.Args())
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package holder runs a subcommand of the extension which holds on to a resource until it is
+// terminated, either as child process or as runc sidecar in the namespaces of the target process.
+package holder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/action-kit/go/action_kit_commons/utils"
+)
+
+type SidecarOpts struct {
+	TargetProcess ociruntime.LinuxProcessInfo
+	Id            string
+}
+
+type Holder interface {
+	Start() error
+	Stop() error
+	Exited() (bool, error)
+	Args() []string
+	// Output is what the holder printed to stdout so far.
+	Output() string
+}
+
+type holder struct {
+	cmd     *exec.Cmd
+	args    []string
+	stdout  syncBuffer
+	stderr  bytes.Buffer
+	done    chan struct{}
+	err     error
+	cleanup func() error
+	once    sync.Once
+}
+
+// NewProcess runs the extension with the given args as child process.
+func NewProcess(args []string) (Holder, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	h := &holder{args: args, done: make(chan struct{})}
+	h.cmd = exec.Command(executable, args...)
+	h.cmd.Stdout = &h.stdout
+	h.cmd.Stderr = &h.stderr
+	return h, nil
+}
+
+// NewRunc runs the extension with the given args in a runc sidecar in the namespaces and cgroup of the
+// target process, so the extension itself isn't affected by the exhausted resource.
+func NewRunc(ctx context.Context, r ociruntime.OciRuntime, sidecar SidecarOpts, args []string, capabilities ...string) (Holder, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := r.Create(ctx, utils.SidecarImagePath(), sidecar.Id, sidecar.TargetProcess.Pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare bundle: %w", err)
+	}
+	if err := bundle.EditSpec(
+		ociruntime.WithHostname(sidecar.Id),
+		ociruntime.WithAnnotations(map[string]string{"com.steadybit.sidecar": "true"}),
+		ociruntime.WithProcessArgs(append([]string{executable}, args...)...),
+		ociruntime.WithNamespaces(sidecar.TargetProcess.Namespaces),
+		ociruntime.WithCgroupPath(sidecar.TargetProcess.CGroupPath, sidecar.Id),
+		ociruntime.WithCapabilities(capabilities...),
+	); err != nil {
+		return nil, errors.Join(err, bundle.Remove())
+	}
+
+	cmd, err := r.RunCommand(ctx, bundle)
+	if err != nil {
+		return nil, errors.Join(err, bundle.Remove())
+	}
+
+	h := &holder{cmd: cmd, args: args, done: make(chan struct{})}
+	h.cmd.Stdout = &h.stdout
+	h.cmd.Stderr = &h.stderr
+	h.cleanup = func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return errors.Join(r.Delete(ctx, sidecar.Id, true), bundle.Remove())
+	}
+	return h, nil
+}
+
+// DeleteSidecar deletes the runc sidecar of a holder whose handle was lost, e.g. because the extension was
+// restarted meanwhile. This terminates the holder, which releases everything it holds. A sidecar which
+// doesn't exist (anymore) is not an error.
+func DeleteSidecar(ctx context.Context, r ociruntime.OciRuntime, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := r.Delete(ctx, id, true); err != nil && !isNotExist(err) {
+		return fmt.Errorf("failed to delete sidecar %s: %w", id, err)
+	}
+	return nil
+}
+
+func isNotExist(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "does not exist") || strings.Contains(msg, "no such file or directory") || strings.Contains(msg, "not found")
+}
+
+func (h *holder) Start() error {
+	log.Info().Strs("args", h.args).Msg("Starting holder")
+	if err := h.cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", h.args[0], err)
+	}
+	go func() {
+		err := h.cmd.Wait()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && h.stderr.Len() > 0 {
+			exitErr.Stderr = h.stderr.Bytes()
+		}
+		h.err = err
+		close(h.done)
+	}()
+	return nil
+}
+
+// Stop terminates the holder, which releases everything it holds.
+func (h *holder) Stop() error {
+	var err error
+	h.once.Do(func() {
+		if h.cmd.Process != nil {
+			if signalErr := h.cmd.Process.Signal(syscall.SIGTERM); signalErr == nil {
+				select {
+				case <-h.done:
+				case <-time.After(10 * time.Second):
+					_ = h.cmd.Process.Kill()
+					<-h.done
+				}
+			}
+		}
+		if h.cleanup != nil {
+			err = h.cleanup()
+		}
+	})
+	return err
+}
+
+func (h *holder) Exited() (bool, error) {
+	select {
+	case <-h.done:
+		return true, h.err
+	default:
+		return false, nil
+	}
+}
+
+func (h *holder) Args() []string {
+	return h.args
+}
+
+func (h *holder) Output() string {
+	return h.stdout.String()
+}
+
+// syncBuffer is written by the goroutine copying the holder's stdout while Output is read.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// SetOomScoreAdj sets the oom_score_adj of the calling holder process, which its children inherit.
+func SetOomScoreAdj(adj int) error {
+	return os.WriteFile("/proc/self/oom_score_adj", []byte(fmt.Sprintf("%d", adj)), 0644)
+}
+
+// RaiseNofileLimit lifts the soft RLIMIT_NOFILE of the calling holder process to the hard limit, and both
+// to fs.nr_open if CAP_SYS_RESOURCE is available.
+func RaiseNofileLimit() {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return
+	}
+	if content, err := os.ReadFile("/proc/sys/fs/nr_open"); err == nil {
+		if nrOpen, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64); err == nil && nrOpen > limit.Max {
+			if syscall.Setrlimit(syscall.RLIMIT_NOFILE, &syscall.Rlimit{Cur: nrOpen, Max: nrOpen}) == nil {
+				return
+			}
+		}
+	}
+	limit.Cur = limit.Max
+	_ = syscall.Setrlimit(syscall.RLIMIT_NOFILE, &limit)
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package holder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRuntime struct {
+	ociruntime.OciRuntime
+	deleted []string
+	err     error
+}
+
+func (r *fakeRuntime) Delete(_ context.Context, id string, _ bool) error {
+	r.deleted = append(r.deleted, id)
+	return r.err
+}
+
+func TestDeleteSidecar(t *testing.T) {
+	r := &fakeRuntime{}
+	assert.NoError(t, DeleteSidecar(context.Background(), r, "a1b2-host"))
+	assert.Equal(t, []string{"a1b2-host"}, r.deleted)
+
+	r.err = errors.New("container `a1b2-host` does not exist")
+	assert.NoError(t, DeleteSidecar(context.Background(), r, "a1b2-host"))
+
+	r.err = errors.New("device or resource busy")
+	assert.ErrorContains(t, DeleteSidecar(context.Background(), r, "a1b2-host"), "failed to delete sidecar a1b2-host")
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package hostinfo reads hardware and kernel properties which go-sysinfo doesn't provide. Every lookup
+// returns an error if its source is unreadable, so callers can skip the attribute.
+package hostinfo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	procPath = "/proc"
+	sysPath  = "/sys"
+
+	detectVirt = func(ctx context.Context) (string, error) {
+		out, err := exec.CommandContext(ctx, "systemd-detect-virt", "--vm").Output()
+		// systemd-detect-virt exits with 1 and prints "none" when no virtualization was found
+		if value := strings.TrimSpace(string(out)); value != "" {
+			return value, nil
+		}
+		return "", err
+	}
+)
+
+type CPUInfo struct {
+	Model   string
+	Cores   int
+	Threads int
+}
+
+// ReadCPUInfo parses /proc/cpuinfo. Cores are counted as distinct (physical id, core id) pairs; if the
+// kernel doesn't report the topology (e.g. on most arm64 hosts), cores equal threads.
+func ReadCPUInfo() (CPUInfo, error) {
+	data, err := os.ReadFile(filepath.Join(procPath, "cpuinfo"))
+	if err != nil {
+		return CPUInfo{}, err
+	}
+
+	var info CPUInfo
+	cores := map[string]struct{}{}
+	physicalId := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "processor":
+			info.Threads++
+		case "model name", "Model", "cpu model":
+			if info.Model == "" {
+				info.Model = value
+			}
+		case "physical id":
+			physicalId = value
+		case "core id":
+			cores[physicalId+"/"+value] = struct{}{}
+		}
+	}
+
+	if info.Threads == 0 {
+		return CPUInfo{}, errors.New("no processors found in cpuinfo")
+	}
+	info.Cores = len(cores)
+	if info.Cores == 0 {
+		info.Cores = info.Threads
+	}
+	return info, nil
+}
+
+// CgroupVersion returns "v2" for the unified hierarchy and "v1" for the legacy one.
+func CgroupVersion() (string, error) {
+	root := filepath.Join(sysPath, "fs", "cgroup")
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		return "v2", nil
+	}
+	if _, err := os.Stat(root); err != nil {
+		return "", err
+	}
+	return "v1", nil
+}
+
+// dmiVendors maps DMI vendor/product substrings to the names used by systemd-detect-virt.
+var dmiVendors = []struct{ match, virt string }{
+	{"QEMU", "qemu"},
+	{"KVM", "kvm"},
+	{"Amazon EC2", "amazon"},
+	{"Google Compute Engine", "google"},
+	{"VMware", "vmware"},
+	{"VirtualBox", "oracle"},
+	{"innotek", "oracle"},
+	{"Xen", "xen"},
+	{"Parallels", "parallels"},
+	{"Virtual Machine", "microsoft"},
+}
+
+// Virtualization returns the hypervisor type, "none" on bare metal. systemd-detect-virt is asked first,
+// the DMI strings of /sys/class/dmi/id are the fallback if it isn't installed.
+func Virtualization(ctx context.Context) (string, error) {
+	if virt, err := detectVirt(ctx); err == nil {
+		return virt, nil
+	}
+
+	var dmi []string
+	for _, file := range []string{"sys_vendor", "product_name", "bios_vendor"} {
+		if data, err := os.ReadFile(filepath.Join(sysPath, "class", "dmi", "id", file)); err == nil {
+			dmi = append(dmi, strings.TrimSpace(string(data)))
+		}
+	}
+	if len(dmi) == 0 {
+		return "", errors.New("neither systemd-detect-virt nor DMI information available")
+	}
+
+	joined := strings.Join(dmi, " ")
+	for _, vendor := range dmiVendors {
+		if strings.Contains(joined, vendor.match) {
+			return vendor.virt, nil
+		}
+	}
+	return "none", nil
+}
+
+// NormalizeArchitecture maps the kernel's machine name to the names used by Go and container images,
+// e.g. x86_64 to amd64 and aarch64 to arm64.
+func NormalizeArchitecture(machine string) string {
+	switch machine {
+	case "x86_64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	case "i386", "i486", "i586", "i686":
+		return "386"
+	default:
+		return machine
+	}
+}
+
+// CapNetAdmin and CapSysTime are the bits of CAP_NET_ADMIN and CAP_SYS_TIME in the capability sets.
+const (
+	CapNetAdmin = 12
+	CapSysTime  = 25
+)
+
+// HasEffectiveCapability reports whether the extension's process holds the capability in its effective set.
+func HasEffectiveCapability(capability uint) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(procPath, "self", "status"))
+	if err != nil {
+		return false, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "CapEff:"); ok {
+			set, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+			if err != nil {
+				return false, fmt.Errorf("failed to parse CapEff: %w", err)
+			}
+			return set&(1<<capability) != 0, nil
+		}
+	}
+	return false, errors.New("no CapEff in process status")
+}
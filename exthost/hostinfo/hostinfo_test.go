@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package hostinfo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const x86CpuInfo = `processor	: 0
+model name	: Intel(R) Xeon(R) Platinum 8259CL CPU @ 2.50GHz
+physical id	: 0
+core id		: 0
+
+processor	: 1
+model name	: Intel(R) Xeon(R) Platinum 8259CL CPU @ 2.50GHz
+physical id	: 0
+core id		: 1
+
+processor	: 2
+model name	: Intel(R) Xeon(R) Platinum 8259CL CPU @ 2.50GHz
+physical id	: 0
+core id		: 0
+
+processor	: 3
+model name	: Intel(R) Xeon(R) Platinum 8259CL CPU @ 2.50GHz
+physical id	: 0
+core id		: 1
+`
+
+const armCpuInfo = `processor	: 0
+BogoMIPS	: 243.75
+CPU part	: 0xd0c
+
+processor	: 1
+BogoMIPS	: 243.75
+CPU part	: 0xd0c
+`
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestReadCPUInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		cpuinfo string
+		want    CPUInfo
+		wantErr bool
+	}{
+		{name: "x86 with hyperthreading", cpuinfo: x86CpuInfo, want: CPUInfo{Model: "Intel(R) Xeon(R) Platinum 8259CL CPU @ 2.50GHz", Cores: 2, Threads: 4}},
+		{name: "arm without topology", cpuinfo: armCpuInfo, want: CPUInfo{Cores: 2, Threads: 2}},
+		{name: "empty", cpuinfo: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			procPath = t.TempDir()
+			writeFile(t, filepath.Join(procPath, "cpuinfo"), tt.cpuinfo)
+
+			info, err := ReadCPUInfo()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, info)
+		})
+	}
+}
+
+func TestReadCPUInfo_Unreadable(t *testing.T) {
+	procPath = t.TempDir()
+	_, err := ReadCPUInfo()
+	assert.Error(t, err)
+}
+
+func TestCgroupVersion(t *testing.T) {
+	sysPath = t.TempDir()
+	_, err := CgroupVersion()
+	assert.Error(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(sysPath, "fs", "cgroup", "memory"), 0755))
+	version, err := CgroupVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "v1", version)
+
+	writeFile(t, filepath.Join(sysPath, "fs", "cgroup", "cgroup.controllers"), "cpu memory io")
+	version, err = CgroupVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "v2", version)
+}
+
+func TestVirtualization(t *testing.T) {
+	previous := detectVirt
+	defer func() { detectVirt = previous }()
+
+	detectVirt = func(context.Context) (string, error) { return "kvm", nil }
+	virt, err := Virtualization(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "kvm", virt)
+
+	detectVirt = func(context.Context) (string, error) { return "", errors.New("not found") }
+	sysPath = t.TempDir()
+	_, err = Virtualization(context.Background())
+	assert.Error(t, err)
+
+	writeFile(t, filepath.Join(sysPath, "class", "dmi", "id", "sys_vendor"), "Microsoft Corporation\n")
+	writeFile(t, filepath.Join(sysPath, "class", "dmi", "id", "product_name"), "Virtual Machine\n")
+	virt, err = Virtualization(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "microsoft", virt)
+
+	writeFile(t, filepath.Join(sysPath, "class", "dmi", "id", "sys_vendor"), "Dell Inc.\n")
+	writeFile(t, filepath.Join(sysPath, "class", "dmi", "id", "product_name"), "PowerEdge R640\n")
+	virt, err = Virtualization(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "none", virt)
+}
+
+func TestNormalizeArchitecture(t *testing.T) {
+	assert.Equal(t, "amd64", NormalizeArchitecture("x86_64"))
+	assert.Equal(t, "arm64", NormalizeArchitecture("aarch64"))
+	assert.Equal(t, "386", NormalizeArchitecture("i686"))
+	assert.Equal(t, "s390x", NormalizeArchitecture("s390x"))
+}
+
+func TestHasEffectiveCapability(t *testing.T) {
+	procPath = t.TempDir()
+	_, err := HasEffectiveCapability(CapSysTime)
+	assert.Error(t, err)
+
+	writeFile(t, filepath.Join(procPath, "self", "status"), "Name:\textension\nCapInh:\t0000000000000000\nCapEff:\t00000000a82425fb\n")
+	ok, err := HasEffectiveCapability(CapSysTime)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	writeFile(t, filepath.Join(procPath, "self", "status"), "CapEff:\t000001ffffffffff\n")
+	ok, err = HasEffectiveCapability(CapSysTime)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package ifb redirects the incoming traffic of network interfaces to ifb devices. Traffic shaping only
+// applies to outgoing packets, but the redirected packets leave the ifb device as outgoing ones, so the
+// faults applied to the ifb device affect the incoming traffic of the interface. Marked outgoing packets
+// can be redirected as well, to apply the faults only to them.
+package ifb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_commons/network/netfault"
+)
+
+var sysModuleIfb = "/sys/module/ifb"
+
+// Redirect redirects the incoming traffic of Interface to Device, or the outgoing packets carrying the Mark
+// bits if set.
+type Redirect struct {
+	Interface string
+	Device    string
+	Mark      uint32 `json:",omitempty"`
+}
+
+// Available tells whether the ifb module is loaded or built into the kernel.
+func Available() error {
+	if _, err := os.Stat(sysModuleIfb); err != nil {
+		return errors.New("the ifb kernel module is not loaded, load it with 'modprobe ifb'")
+	}
+	return nil
+}
+
+// Redirects returns the redirects of the interfaces to devices named after the execution, which fit the
+// 15 characters of an interface name.
+func Redirects(executionId uuid.UUID, interfaces []string) []Redirect {
+	id := strings.ReplaceAll(executionId.String(), "-", "")[:8]
+	redirects := make([]Redirect, 0, len(interfaces))
+	for i, iface := range interfaces {
+		redirects = append(redirects, Redirect{Interface: iface, Device: fmt.Sprintf("ifb%s%d", id, i)})
+	}
+	return redirects
+}
+
+// MarkedRedirects returns the redirects of the outgoing packets of the interfaces carrying the mark bits,
+// named like Redirects.
+func MarkedRedirects(executionId uuid.UUID, interfaces []string, mark uint32) []Redirect {
+	redirects := Redirects(executionId, interfaces)
+	for i := range redirects {
+		redirects[i].Mark = mark
+	}
+	return redirects
+}
+
+// Devices returns the ifb devices of the redirects, the interfaces to apply the faults to.
+func Devices(redirects []Redirect) []string {
+	devices := make([]string, 0, len(redirects))
+	for _, r := range redirects {
+		devices = append(devices, r.Device)
+	}
+	return devices
+}
+
+// Setup creates the ifb devices and redirects the traffic to them, running ip and tc with the runner
+// of the network namespace. The redirects already set up are torn down if one fails.
+func Setup(ctx context.Context, r netfault.CommandRunner, redirects []Redirect) error {
+	for i, redirect := range redirects {
+		if err := setup(ctx, r, redirect); err != nil {
+			return errors.Join(fmt.Errorf("failed to redirect the traffic of %s to %s: %w", redirect.Interface, redirect.Device, err), Teardown(ctx, r, redirects[:i]))
+		}
+	}
+	return nil
+}
+
+func setup(ctx context.Context, r netfault.CommandRunner, redirect Redirect) error {
+	if err := r.Run(ctx, []string{"ip", "link", "add", redirect.Device, "type", "ifb"}); err != nil {
+		return err
+	}
+	if err := r.Run(ctx, []string{"ip", "link", "set", "dev", redirect.Device, "up"}); err != nil {
+		return errors.Join(err, removeDevice(ctx, r, redirect))
+	}
+	// fails if the interface has an ingress or clsact qdisc already, which isn't ours to remove
+	if redirect.Mark != 0 {
+		if err := r.Run(ctx, []string{"tc", "qdisc", "add", "dev", redirect.Interface, "clsact"}); err != nil {
+			return errors.Join(err, removeDevice(ctx, r, redirect))
+		}
+		mark := fmt.Sprintf("%#x/%#x", redirect.Mark, redirect.Mark)
+		if err := r.Run(ctx, []string{"tc", "filter", "add", "dev", redirect.Interface, "egress", "protocol", "all", "handle", mark, "fw", "action", "mirred", "egress", "redirect", "dev", redirect.Device}); err != nil {
+			return errors.Join(err, removeRedirect(ctx, r, redirect), removeDevice(ctx, r, redirect))
+		}
+		return nil
+	}
+	if err := r.Run(ctx, []string{"tc", "qdisc", "add", "dev", redirect.Interface, "handle", "ffff:", "ingress"}); err != nil {
+		return errors.Join(err, removeDevice(ctx, r, redirect))
+	}
+	if err := r.Run(ctx, []string{"tc", "filter", "add", "dev", redirect.Interface, "parent", "ffff:", "protocol", "all", "u32", "match", "u32", "0", "0", "action", "mirred", "egress", "redirect", "dev", redirect.Device}); err != nil {
+		return errors.Join(err, removeRedirect(ctx, r, redirect), removeDevice(ctx, r, redirect))
+	}
+	return nil
+}
+
+// Teardown removes the redirects and the ifb devices. Redirects which don't exist (anymore) are no error.
+func Teardown(ctx context.Context, r netfault.CommandRunner, redirects []Redirect) error {
+	var errs []error
+	for _, redirect := range redirects {
+		errs = append(errs, removeRedirect(ctx, r, redirect), removeDevice(ctx, r, redirect))
+	}
+	return errors.Join(errs...)
+}
+
+func removeRedirect(ctx context.Context, r netfault.CommandRunner, redirect Redirect) error {
+	if redirect.Mark != 0 {
+		return ignoreNotExist(r.Run(ctx, []string{"tc", "qdisc", "del", "dev", redirect.Interface, "clsact"}))
+	}
+	return ignoreNotExist(r.Run(ctx, []string{"tc", "qdisc", "del", "dev", redirect.Interface, "handle", "ffff:", "ingress"}))
+}
+
+func removeDevice(ctx context.Context, r netfault.CommandRunner, redirect Redirect) error {
+	return ignoreNotExist(r.Run(ctx, []string{"ip", "link", "del", redirect.Device}))
+}
+
+func ignoreNotExist(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "cannot find device") || strings.Contains(msg, "no such file or directory") || strings.Contains(msg, "invalid handle") {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package ifb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRunner struct {
+	commands []string
+	fail     func(cmd string) error
+}
+
+func (r *fakeRunner) Run(_ context.Context, args []string) error {
+	cmd := strings.Join(args, " ")
+	r.commands = append(r.commands, cmd)
+	return r.fail(cmd)
+}
+
+func TestRedirects(t *testing.T) {
+	redirects := Redirects(uuid.MustParse("1a2b3c4d-0000-0000-0000-000000000000"), []string{"eth0", "eth1"})
+	assert.Equal(t, []Redirect{{Interface: "eth0", Device: "ifb1a2b3c4d0"}, {Interface: "eth1", Device: "ifb1a2b3c4d1"}}, redirects)
+	assert.Equal(t, []string{"ifb1a2b3c4d0", "ifb1a2b3c4d1"}, Devices(redirects))
+
+	marked := MarkedRedirects(uuid.MustParse("1a2b3c4d-0000-0000-0000-000000000000"), []string{"eth0"}, 0x40)
+	assert.Equal(t, []Redirect{{Interface: "eth0", Device: "ifb1a2b3c4d0", Mark: 0x40}}, marked)
+}
+
+func TestSetup(t *testing.T) {
+	r := &fakeRunner{fail: func(string) error { return nil }}
+
+	require.NoError(t, Setup(context.Background(), r, []Redirect{{Interface: "eth0", Device: "ifb0"}}))
+	assert.Equal(t, []string{
+		"ip link add ifb0 type ifb",
+		"ip link set dev ifb0 up",
+		"tc qdisc add dev eth0 handle ffff: ingress",
+		"tc filter add dev eth0 parent ffff: protocol all u32 match u32 0 0 action mirred egress redirect dev ifb0",
+	}, r.commands)
+}
+
+func TestSetupMarked(t *testing.T) {
+	r := &fakeRunner{fail: func(string) error { return nil }}
+
+	require.NoError(t, Setup(context.Background(), r, []Redirect{{Interface: "eth0", Device: "ifb0", Mark: 0x40}}))
+	assert.Equal(t, []string{
+		"ip link add ifb0 type ifb",
+		"ip link set dev ifb0 up",
+		"tc qdisc add dev eth0 clsact",
+		"tc filter add dev eth0 egress protocol all handle 0x40/0x40 fw action mirred egress redirect dev ifb0",
+	}, r.commands)
+
+	r.commands = nil
+	require.NoError(t, Teardown(context.Background(), r, []Redirect{{Interface: "eth0", Device: "ifb0", Mark: 0x40}}))
+	assert.Equal(t, []string{"tc qdisc del dev eth0 clsact", "ip link del ifb0"}, r.commands)
+}
+
+func TestSetupKeepsForeignIngressQdisc(t *testing.T) {
+	r := &fakeRunner{fail: func(cmd string) error {
+		if cmd == "tc qdisc add dev eth1 handle ffff: ingress" {
+			return errors.New("Exclusivity flag on, cannot modify")
+		}
+		return nil
+	}}
+
+	err := Setup(context.Background(), r, []Redirect{{Interface: "eth0", Device: "ifb0"}, {Interface: "eth1", Device: "ifb1"}})
+	assert.ErrorContains(t, err, "failed to redirect the traffic of eth1 to ifb1")
+	assert.Equal(t, []string{
+		"ip link add ifb0 type ifb",
+		"ip link set dev ifb0 up",
+		"tc qdisc add dev eth0 handle ffff: ingress",
+		"tc filter add dev eth0 parent ffff: protocol all u32 match u32 0 0 action mirred egress redirect dev ifb0",
+		"ip link add ifb1 type ifb",
+		"ip link set dev ifb1 up",
+		"tc qdisc add dev eth1 handle ffff: ingress",
+		"ip link del ifb1",
+		"tc qdisc del dev eth0 handle ffff: ingress",
+		"ip link del ifb0",
+	}, r.commands)
+}
+
+func TestTeardownIgnoresMissingRedirects(t *testing.T) {
+	r := &fakeRunner{fail: func(cmd string) error {
+		if strings.HasPrefix(cmd, "ip link del") {
+			return errors.New(`Cannot find device "ifb0"`)
+		}
+		return nil
+	}}
+
+	require.NoError(t, Teardown(context.Background(), r, []Redirect{{Interface: "eth0", Device: "ifb0"}}))
+	assert.Equal(t, []string{"tc qdisc del dev eth0 handle ffff: ingress", "ip link del ifb0"}, r.commands)
+}
+
+func TestAvailable(t *testing.T) {
+	defer func() { sysModuleIfb = "/sys/module/ifb" }()
+
+	sysModuleIfb = filepath.Join(t.TempDir(), "ifb")
+	assert.ErrorContains(t, Available(), "modprobe ifb")
+
+	require.NoError(t, os.Mkdir(sysModuleIfb, 0755))
+	assert.NoError(t, Available())
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const fileSuffix = ".json"
+
+// Entry is the revert information of a single action execution. It is written when the execution
+// starts and removed once it was stopped, so any entry still present after a restart belongs to an
+// execution whose faults were left behind.
+type Entry struct {
+	ExecutionId uuid.UUID       `json:"executionId"`
+	ActionId    string          `json:"actionId"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	State       json.RawMessage `json:"state"`
+}
+
+// Journal keeps one file per active execution in a directory. A Journal without a directory is
+// disabled and all operations are no-ops.
+type Journal struct {
+	dir string
+}
+
+func New(dir string) *Journal {
+	return &Journal{dir: dir}
+}
+
+func (j *Journal) Enabled() bool {
+	return j != nil && j.dir != ""
+}
+
+// Record writes (or replaces) the entry for the execution. The file is written to a temporary
+// location first and renamed afterward, so a crash never leaves a half-written entry behind.
+func (j *Journal) Record(executionId uuid.UUID, actionId string, state any) error {
+	if !j.Enabled() {
+		return nil
+	}
+
+	rawState, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize state: %w", err)
+	}
+
+	data, err := json.Marshal(Entry{
+		ExecutionId: executionId,
+		ActionId:    actionId,
+		CreatedAt:   time.Now(),
+		State:       rawState,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to serialize journal entry: %w", err)
+	}
+
+	if err := os.MkdirAll(j.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(j.dir, "."+executionId.String()+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync journal entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close journal entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), j.path(executionId))
+}
+
+// Remove deletes the entry for the execution. Removing an entry which does not exist is not an error.
+func (j *Journal) Remove(executionId uuid.UUID) error {
+	if !j.Enabled() {
+		return nil
+	}
+
+	if err := os.Remove(j.path(executionId)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Entries returns all entries ordered by creation time. Unreadable entries are skipped and logged.
+func (j *Journal) Entries() ([]Entry, error) {
+	if !j.Enabled() {
+		return nil, nil
+	}
+
+	files, err := os.ReadDir(j.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") || !strings.HasSuffix(f.Name(), fileSuffix) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(j.dir, f.Name()))
+		if err != nil {
+			log.Warn().Err(err).Str("file", f.Name()).Msg("failed to read journal entry")
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			log.Warn().Err(err).Str("file", f.Name()).Msg("failed to parse journal entry")
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, k int) bool {
+		return entries[i].CreatedAt.Before(entries[k].CreatedAt)
+	})
+	return entries, nil
+}
+
+func (j *Journal) path(executionId uuid.UUID) string {
+	return filepath.Join(j.dir, executionId.String()+fileSuffix)
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package journal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exampleState struct {
+	Offset  int
+	Applied bool
+}
+
+func TestJournal_RecordAndRemove(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), "journal"))
+	first, second := uuid.New(), uuid.New()
+
+	require.NoError(t, j.Record(first, "action.first", exampleState{Offset: 1}))
+	require.NoError(t, j.Record(second, "action.second", exampleState{Offset: 2}))
+	require.NoError(t, j.Record(first, "action.first", exampleState{Offset: 1, Applied: true}))
+
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, second, entries[0].ExecutionId)
+	assert.Equal(t, "action.second", entries[0].ActionId)
+	assert.Equal(t, first, entries[1].ExecutionId)
+
+	var state exampleState
+	require.NoError(t, json.Unmarshal(entries[1].State, &state))
+	assert.Equal(t, exampleState{Offset: 1, Applied: true}, state)
+
+	require.NoError(t, j.Remove(first))
+	require.NoError(t, j.Remove(first))
+
+	entries, err = j.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, second, entries[0].ExecutionId)
+}
+
+func TestJournal_SkipsUnreadableEntries(t *testing.T) {
+	dir := t.TempDir()
+	j := New(dir)
+	require.NoError(t, j.Record(uuid.New(), "action", exampleState{}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".partial-write"), []byte("{"), 0600))
+
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestJournal_Disabled(t *testing.T) {
+	j := New("")
+	assert.False(t, j.Enabled())
+	assert.NoError(t, j.Record(uuid.New(), "action", exampleState{}))
+	assert.NoError(t, j.Remove(uuid.New()))
+
+	entries, err := j.Entries()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestJournal_MissingDirectory(t *testing.T) {
+	entries, err := New(filepath.Join(t.TempDir(), "missing")).Entries()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
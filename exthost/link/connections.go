@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package link
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// tcpEstablished is the state of established sockets in /proc/net/tcp
+const tcpEstablished = "01"
+
+// ConnectionPeers returns the remote addresses of the established TCP connections to the local port, as
+// listed in the tcp and tcp6 files of the given /proc/<pid>/net directory. For connections through a proxy
+// this is the address of the proxy.
+func ConnectionPeers(procNet string, port int) ([]net.IP, error) {
+	var ips []net.IP
+	for _, file := range []string{"tcp", "tcp6"} {
+		found, err := readConnectionPeers(filepath.Join(procNet, file), port)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, ip := range found {
+			if !slices.ContainsFunc(ips, ip.Equal) {
+				ips = append(ips, ip)
+			}
+		}
+	}
+	return ips, nil
+}
+
+func readConnectionPeers(file string, port int) ([]net.IP, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var ips []net.IP
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != tcpEstablished {
+			continue
+		}
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		if p, err := strconv.ParseInt(hexPort, 16, 32); err != nil || int(p) != port {
+			continue
+		}
+		hexIp, _, _ := strings.Cut(fields[2], ":")
+		if ip := parseHexIP(hexIp); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips, scanner.Err()
+}
+
+// parseHexIP parses an address of /proc/net/tcp, which is printed as 32-bit words in host byte order.
+func parseHexIP(s string) net.IP {
+	b, err := hex.DecodeString(s)
+	if err != nil || (len(b) != net.IPv4len && len(b) != net.IPv6len) {
+		return nil
+	}
+	ip := make(net.IP, len(b))
+	for i := 0; i < len(b); i += 4 {
+		binary.BigEndian.PutUint32(ip[i:], binary.NativeEndian.Uint32(b[i:]))
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package link
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ExitDownAlready is the exit code of the `link` subcommand if the interface was down already. The
+// interface must not be set up then, as it was meant to be down.
+const ExitDownAlready = 3
+
+// Run implements the `link` subcommand. It sets the interface down, or flaps it, until it is terminated
+// or the duration is over and sets it up again. Returns the exit code.
+func Run(args []string) int {
+	flags := flag.NewFlagSet("link", flag.ContinueOnError)
+	name := flags.String("interface", "", "the interface to set down")
+	interval := flags.Duration("interval", 0, "flap the interface at this interval, keep it down if 0")
+	duration := flags.Duration("duration", 0, "set the interface up again after this long, 0 to wait for termination")
+	restore := flags.Bool("restore", false, "set the interface up and exit")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *name == "" {
+		_, _ = fmt.Fprintln(os.Stderr, "interface is required")
+		return 2
+	}
+
+	terminated := make(chan os.Signal, 1)
+	signal.Notify(terminated, syscall.SIGTERM, syscall.SIGINT)
+
+	if *restore {
+		if err := setUp(*name, true); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to set %s up: %s\n", *name, err)
+			return 1
+		}
+		return 0
+	}
+
+	up, err := isUp(*name)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to read the state of %s: %s\n", *name, err)
+		return 1
+	}
+	if !up {
+		// restoring would bring up an interface which was meant to be down
+		_, _ = fmt.Fprintf(os.Stderr, "%s is down already\n", *name)
+		return ExitDownAlready
+	}
+
+	return hold(*name, *interval, *duration, terminated, setUp)
+}
+
+// hold keeps the interface down until it is terminated or the duration is over, so the interface is set up
+// again even if the extension can't stop the attack, e.g. because the agent lost its connection.
+func hold(name string, interval, duration time.Duration, terminated <-chan os.Signal, setUp func(string, bool) error) int {
+	defer func() {
+		if err := setUp(name, true); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to set %s up: %s\n", name, err)
+		}
+	}()
+
+	if err := setUp(name, false); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to set %s down: %s\n", name, err)
+		return 1
+	}
+	fmt.Printf("%s down\n", name)
+
+	var flap <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		flap = ticker.C
+	}
+	var expired <-chan time.Time
+	if duration > 0 {
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	up := false
+	for {
+		select {
+		case <-terminated:
+			return 0
+		case <-expired:
+			return 0
+		case <-flap:
+			up = !up
+			if err := setUp(name, up); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "failed to flap %s: %s\n", name, err)
+				return 1
+			}
+		}
+	}
+}
+
+func isUp(name string) (bool, error) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	ifr, err := unix.NewIfreq(name)
+	if err != nil {
+		return false, err
+	}
+	if err := unix.IoctlIfreq(fd, unix.SIOCGIFFLAGS, ifr); err != nil {
+		return false, err
+	}
+	return ifr.Uint16()&unix.IFF_UP != 0, nil
+}
+
+func setUp(name string, up bool) error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	ifr, err := unix.NewIfreq(name)
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlIfreq(fd, unix.SIOCGIFFLAGS, ifr); err != nil {
+		return err
+	}
+	flags := ifr.Uint16()
+	if up {
+		flags |= unix.IFF_UP
+	} else {
+		flags &^= unix.IFF_UP
+	}
+	ifr.SetUint16(flags)
+	return unix.IoctlIfreq(fd, unix.SIOCSIFFLAGS, ifr)
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package link sets a network interface of the host administratively down or flaps it. The interface is
+// held down by the `link` subcommand of the extension, which is started directly or as runc sidecar in the
+// host network namespace and sets the interface up again when it is terminated.
+package link
+
+import (
+	"context"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/extension-host/exthost/holder"
+)
+
+type Opts struct {
+	Interface string
+	// Interval flaps the interface, it is down and up for this long in turns. Zero keeps it down.
+	Interval time.Duration
+	// Duration sets the interface up again once it's over, even if the holder isn't terminated. Zero waits
+	// for the termination.
+	Duration time.Duration
+	// Restore only sets the interface up and exits
+	Restore bool
+}
+
+func (o Opts) Args() []string {
+	args := []string{"link", "-interface", o.Interface}
+	if o.Restore {
+		return append(args, "-restore")
+	}
+	if o.Interval > 0 {
+		args = append(args, "-interval", o.Interval.String())
+	}
+	if o.Duration > 0 {
+		args = append(args, "-duration", o.Duration.String())
+	}
+	return args
+}
+
+func NewLinkProcess(opts Opts) (holder.Holder, error) {
+	return holder.NewProcess(opts.Args())
+}
+
+func NewLinkRunc(ctx context.Context, r ociruntime.OciRuntime, sidecar holder.SidecarOpts, opts Opts) (holder.Holder, error) {
+	return holder.NewRunc(ctx, r, sidecar, opts.Args(), "CAP_NET_ADMIN")
+}
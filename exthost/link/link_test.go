@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package link
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const procNetTcp = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1 0000000000000000 100 0 0 10 0
+   1: 0A00000A:1F90 0100000A:C350 01 00000000:00000000 00:00000000 00000000     0        0 2 1 0000000000000000 20 4 30 10 -1
+   2: 0A00000A:1F90 0200000A:C351 01 00000000:00000000 00:00000000 00000000     0        0 3 1 0000000000000000 20 4 30 10 -1
+   3: 0100007F:0016 0100007F:8002 01 00000000:00000000 00:00000000 00000000     0        0 4 1 0000000000000000 20 4 30 10 -1
+`
+
+const procNetTcp6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 000080FE00000000FF0D0B02FE8F3A01:1F90 000080FE00000000FF0D0B02FE8F3A02:C352 01 00000000:00000000 00:00000000 00000000     0        0 5 1 0000000000000000 20 4 30 10 -1
+`
+
+func TestConnectionPeers(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tcp"), []byte(procNetTcp), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tcp6"), []byte(procNetTcp6), 0644))
+
+	ips, err := ConnectionPeers(dir, 8080)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2", "fe80::20b:dff:23a:8ffe"}, toStrings(ips))
+}
+
+func TestConnectionPeersWithoutTcp6(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tcp"), []byte(procNetTcp), 0644))
+
+	ips, err := ConnectionPeers(dir, 22)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.1"}, toStrings(ips))
+}
+
+func TestOptsArgs(t *testing.T) {
+	assert.Equal(t, []string{"link", "-interface", "eth1"}, Opts{Interface: "eth1"}.Args())
+	assert.Equal(t, []string{"link", "-interface", "eth1", "-interval", "5s"}, Opts{Interface: "eth1", Interval: 5 * time.Second}.Args())
+	assert.Equal(t, []string{"link", "-interface", "eth1", "-restore"}, Opts{Interface: "eth1", Interval: 5 * time.Second, Restore: true}.Args())
+	assert.Equal(t, []string{"link", "-interface", "eth1", "-duration", "30s"}, Opts{Interface: "eth1", Duration: 30 * time.Second}.Args())
+}
+
+type fakeLink struct {
+	mu     sync.Mutex
+	states []bool
+	err    error
+}
+
+func (f *fakeLink) setUp(_ string, up bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.states = append(f.states, up)
+	if !up && f.err != nil {
+		return f.err
+	}
+	return nil
+}
+
+func (f *fakeLink) recorded() []bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]bool(nil), f.states...)
+}
+
+func TestHoldRestoresOnTermination(t *testing.T) {
+	link := &fakeLink{}
+	terminated := make(chan os.Signal, 1)
+	terminated <- syscall.SIGTERM
+
+	assert.Equal(t, 0, hold("eth1", 0, 0, terminated, link.setUp))
+	assert.Equal(t, []bool{false, true}, link.recorded())
+}
+
+func TestHoldFlaps(t *testing.T) {
+	link := &fakeLink{}
+	terminated := make(chan os.Signal, 1)
+	go func() {
+		assert.Eventually(t, func() bool { return len(link.recorded()) >= 3 }, time.Second, time.Millisecond)
+		terminated <- syscall.SIGTERM
+	}()
+
+	assert.Equal(t, 0, hold("eth1", 10*time.Millisecond, 0, terminated, link.setUp))
+	states := link.recorded()
+	assert.Equal(t, []bool{false, true, false}, states[:3])
+	assert.True(t, states[len(states)-1])
+}
+
+func TestHoldRestoresAfterDuration(t *testing.T) {
+	link := &fakeLink{}
+
+	assert.Equal(t, 0, hold("eth1", 0, 10*time.Millisecond, make(chan os.Signal), link.setUp))
+	assert.Equal(t, []bool{false, true}, link.recorded())
+}
+
+func TestHoldRestoresOnFailure(t *testing.T) {
+	link := &fakeLink{err: errors.New("operation not permitted")}
+
+	assert.Equal(t, 1, hold("eth1", 0, 0, make(chan os.Signal), link.setUp))
+	assert.Equal(t, []bool{false, true}, link.recorded())
+}
+
+func toStrings(ips []net.IP) []string {
+	var s []string
+	for _, ip := range ips {
+		s = append(s, ip.String())
+	}
+	return s
+}
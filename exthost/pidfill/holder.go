@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package pidfill
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/steadybit/extension-host/exthost/holder"
+)
+
+// usageCheckInterval is the number of tasks spawned between two checks of the usage. It is kept well
+// below any sensible reserve, so the reserve can't be overrun in between.
+const usageCheckInterval = 50
+
+type spawner interface {
+	spawn() error
+	release()
+	count() int
+}
+
+// Run implements the `pid-fill` subcommand. It spawns idle processes or threads, holds them until it is
+// terminated and returns the exit code.
+func Run(args []string) int {
+	flags := flag.NewFlagSet("pid-fill", flag.ContinueOnError)
+	mode := flags.String("mode", string(ModeProcesses), "spawn idle processes or threads")
+	maxUsage := flags.Int("max-usage", 90, "stop spawning at this percentage of the PID limit")
+	reserve := flags.Int("reserve", 0, "number of PIDs always left available")
+	oomScoreAdj := flags.Int("oom-score-adj", 0, "oom_score_adj of the holder and the spawned processes")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := holder.SetOomScoreAdj(*oomScoreAdj); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to set oom_score_adj: %s\n", err)
+	}
+
+	terminated := make(chan os.Signal, 1)
+	signal.Notify(terminated, syscall.SIGTERM, syscall.SIGINT)
+
+	var s spawner
+	switch Mode(*mode) {
+	case ModeProcesses:
+		s = &processSpawner{}
+	case ModeThreads:
+		// the go runtime crashes when exceeding the thread limit, the reserve keeps it from the kernel limit
+		debug.SetMaxThreads(1 << 30)
+		s = &threadSpawner{done: make(chan struct{})}
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "unknown mode '%s'\n", *mode)
+		return 2
+	}
+	defer s.release()
+
+	usage, err := fill(s, *maxUsage, uint64(*reserve), ReadUsage)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to spawn %s: %s\n", *mode, err)
+		return 1
+	}
+	fmt.Printf("holding %d %s, %d of %d PIDs used (%s)\n", s.count(), *mode, usage.Used, usage.Limit, usage.Source)
+
+	<-terminated
+	return 0
+}
+
+// fill spawns tasks until the usage or the reserve is reached. Hitting the limit isn't an error, that's
+// what the attack is about.
+func fill(s spawner, maxUsage int, reserve uint64, readUsage func() (Usage, error)) (Usage, error) {
+	for {
+		usage, err := readUsage()
+		if err != nil {
+			return usage, err
+		}
+		if usage.Percent() >= float64(maxUsage) || usage.Available() <= reserve {
+			return usage, nil
+		}
+		for range min(uint64(usageCheckInterval), usage.Available()-reserve) {
+			if err := s.spawn(); errors.Is(err, syscall.EAGAIN) {
+				_, _ = fmt.Fprintf(os.Stderr, "stopped at %d: %s\n", s.count(), err)
+				return readUsage()
+			} else if err != nil {
+				return usage, err
+			}
+		}
+	}
+}
+
+type processSpawner struct {
+	processes []*exec.Cmd
+}
+
+func (s *processSpawner) spawn() error {
+	cmd := exec.Command("sleep", "infinity")
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	s.processes = append(s.processes, cmd)
+	return nil
+}
+
+func (s *processSpawner) release() {
+	for _, cmd := range s.processes {
+		_ = cmd.Process.Kill()
+	}
+	for _, cmd := range s.processes {
+		_ = cmd.Wait()
+	}
+}
+
+func (s *processSpawner) count() int {
+	return len(s.processes)
+}
+
+// threadSpawner pins idle goroutines to their OS threads, so each one keeps a thread alive.
+type threadSpawner struct {
+	threads int
+	done    chan struct{}
+}
+
+func (s *threadSpawner) spawn() error {
+	started := make(chan struct{})
+	go func() {
+		runtime.LockOSThread()
+		close(started)
+		<-s.done
+	}()
+	<-started
+	s.threads++
+	return nil
+}
+
+func (s *threadSpawner) release() {
+	close(s.done)
+}
+
+func (s *threadSpawner) count() int {
+	return s.threads
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package pidfill exhausts process IDs. The idle processes or threads are spawned by the `pid-fill`
+// subcommand of the extension, which is started directly or as runc sidecar like stress-ng.
+package pidfill
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/extension-host/exthost/holder"
+)
+
+type Mode string
+
+const (
+	ModeProcesses Mode = "processes"
+	ModeThreads   Mode = "threads"
+)
+
+type Opts struct {
+	Mode Mode
+	// MaxUsage stops spawning once this percentage of the PID limit is used
+	MaxUsage int
+	// Reserve is the number of PIDs always left available, so e.g. the kubelet and sshd can still fork
+	Reserve int
+	// OomScoreAdj is applied to the holder and inherited by the spawned processes
+	OomScoreAdj int
+}
+
+func (o Opts) Args() []string {
+	return []string{
+		"pid-fill",
+		"-mode", string(o.Mode),
+		"-max-usage", strconv.Itoa(o.MaxUsage),
+		"-reserve", strconv.Itoa(o.Reserve),
+		"-oom-score-adj", strconv.Itoa(o.OomScoreAdj),
+	}
+}
+
+func NewPidfillProcess(opts Opts) (holder.Holder, error) {
+	return holder.NewProcess(opts.Args())
+}
+
+func NewPidfillRunc(ctx context.Context, r ociruntime.OciRuntime, sidecar holder.SidecarOpts, opts Opts) (holder.Holder, error) {
+	// lowering oom_score_adj below the inherited value
+	return holder.NewRunc(ctx, r, sidecar, opts.Args(), "CAP_SYS_RESOURCE")
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package pidfill
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, file, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+	require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+}
+
+func TestReadUsage(t *testing.T) {
+	procPath = t.TempDir()
+	cgroupRoot = t.TempDir()
+	defer func() {
+		procPath = "/proc"
+		cgroupRoot = "/sys/fs/cgroup"
+	}()
+
+	writeFile(t, filepath.Join(procPath, "sys/kernel/pid_max"), "4194304\n")
+	writeFile(t, filepath.Join(procPath, "sys/kernel/threads-max"), "126000\n")
+	writeFile(t, filepath.Join(procPath, "loadavg"), "0.52 0.58 0.59 3/1200 4711\n")
+	writeFile(t, filepath.Join(procPath, "self/cgroup"), "0::/kubepods.slice/pod1/sidecar\n")
+
+	usage, err := ReadUsage()
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Used: 1200, Limit: 126000, Source: "kernel.threads-max"}, usage)
+
+	writeFile(t, filepath.Join(cgroupRoot, "kubepods.slice/pod1/sidecar/pids.max"), "max\n")
+	writeFile(t, filepath.Join(cgroupRoot, "kubepods.slice/pod1/pids.max"), "1024\n")
+	writeFile(t, filepath.Join(cgroupRoot, "kubepods.slice/pod1/pids.current"), "24\n")
+	usage, err = ReadUsage()
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Used: 24, Limit: 1024, Source: "pids.max of /kubepods.slice/pod1"}, usage)
+	assert.Equal(t, uint64(1000), usage.Available())
+}
+
+type fakeSpawner struct {
+	spawned int
+	limit   int
+}
+
+func (s *fakeSpawner) spawn() error {
+	if s.limit > 0 && s.spawned >= s.limit {
+		return syscall.EAGAIN
+	}
+	s.spawned++
+	return nil
+}
+
+func (s *fakeSpawner) release() {}
+
+func (s *fakeSpawner) count() int {
+	return s.spawned
+}
+
+func TestFill(t *testing.T) {
+	s := &fakeSpawner{}
+	readUsage := func() (Usage, error) { return Usage{Used: uint64(100 + s.spawned), Limit: 1000}, nil }
+
+	usage, err := fill(s, 90, 0, readUsage)
+	require.NoError(t, err)
+	assert.Equal(t, 800, s.spawned)
+	assert.Equal(t, uint64(900), usage.Used)
+
+	s = &fakeSpawner{}
+	_, err = fill(s, 100, 250, readUsage)
+	require.NoError(t, err)
+	assert.Equal(t, 650, s.spawned, "the reserve is left available")
+
+	s = &fakeSpawner{limit: 120}
+	_, err = fill(s, 100, 0, readUsage)
+	require.NoError(t, err)
+	assert.Equal(t, 120, s.spawned)
+}
+
+func TestOptsArgs(t *testing.T) {
+	assert.Equal(t, []string{"pid-fill", "-mode", "threads", "-max-usage", "80", "-reserve", "500", "-oom-score-adj", "-996"},
+		Opts{Mode: ModeThreads, MaxUsage: 80, Reserve: 500, OomScoreAdj: -996}.Args())
+}
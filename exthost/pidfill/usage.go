@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package pidfill
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	procPath   = "/proc"
+	cgroupRoot = "/sys/fs/cgroup"
+)
+
+type Usage struct {
+	Used  uint64
+	Limit uint64
+	// Source names the limit with the least headroom, kernel.pid_max or the pids.max of a cgroup
+	Source string
+}
+
+// Percent is the used share of the limit.
+func (u Usage) Percent() float64 {
+	if u.Limit == 0 {
+		return 0
+	}
+	return float64(u.Used) * 100 / float64(u.Limit)
+}
+
+// Available is the number of PIDs left until the limit is reached.
+func (u Usage) Available() uint64 {
+	if u.Used >= u.Limit {
+		return 0
+	}
+	return u.Limit - u.Used
+}
+
+// ReadUsage returns the PID usage with the least headroom: the system-wide tasks against kernel.pid_max
+// and kernel.threads-max, or the pids.current against pids.max of the calling process' cgroup and its
+// ancestors (cgroup v2 only).
+func ReadUsage() (Usage, error) {
+	usage, err := ReadKernelUsage()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	cgroup, err := ownCgroup()
+	if err != nil {
+		return usage, nil
+	}
+	for ; ; cgroup = path.Dir(cgroup) {
+		if limit, err := readUint(filepath.Join(cgroupRoot, cgroup, "pids.max")); err == nil {
+			if used, err := readUint(filepath.Join(cgroupRoot, cgroup, "pids.current")); err == nil {
+				candidate := Usage{Used: used, Limit: limit, Source: fmt.Sprintf("pids.max of %s", cgroup)}
+				if candidate.Available() < usage.Available() {
+					usage = candidate
+				}
+			}
+		}
+		if cgroup == "/" {
+			break
+		}
+	}
+	return usage, nil
+}
+
+// ReadKernelUsage returns the system-wide tasks against kernel.pid_max or kernel.threads-max.
+func ReadKernelUsage() (Usage, error) {
+	pidMax, err := readUint(filepath.Join(procPath, "sys/kernel/pid_max"))
+	if err != nil {
+		return Usage{}, err
+	}
+	usage := Usage{Limit: pidMax, Source: "kernel.pid_max"}
+	if threadsMax, err := readUint(filepath.Join(procPath, "sys/kernel/threads-max")); err == nil && threadsMax < pidMax {
+		usage = Usage{Limit: threadsMax, Source: "kernel.threads-max"}
+	}
+
+	// the fourth field of loadavg is running/total scheduling entities, i.e. all threads
+	content, err := os.ReadFile(filepath.Join(procPath, "loadavg"))
+	if err != nil {
+		return Usage{}, err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) < 4 {
+		return Usage{}, fmt.Errorf("unexpected content of loadavg: %s", content)
+	}
+	_, total, ok := strings.Cut(fields[3], "/")
+	if !ok {
+		return Usage{}, fmt.Errorf("unexpected content of loadavg: %s", content)
+	}
+	usage.Used, err = strconv.ParseUint(total, 10, 64)
+	return usage, err
+}
+
+func ownCgroup() (string, error) {
+	content, err := os.ReadFile(filepath.Join(procPath, "self/cgroup"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if cgroup, ok := strings.CutPrefix(line, "0::"); ok {
+			return cgroup, nil
+		}
+	}
+	return "", fmt.Errorf("no cgroup v2 hierarchy")
+}
+
+// readUint reads a single number, "max" is an error as it doesn't limit anything.
+func readUint(file string) (uint64, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+}
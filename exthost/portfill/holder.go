@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package portfill
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/steadybit/extension-host/exthost/holder"
+)
+
+const (
+	dialTimeout = 5 * time.Second
+	dialWorkers = 64
+	// maxFailures stops the fill if that many connections in a row couldn't be established
+	maxFailures = 100
+)
+
+type destinations []string
+
+func (d *destinations) String() string {
+	return strings.Join(*d, ",")
+}
+
+func (d *destinations) Set(value string) error {
+	*d = append(*d, value)
+	return nil
+}
+
+// Run implements the `port-fill` subcommand. It opens outbound connections, holds them until it is
+// terminated and returns the exit code.
+func Run(args []string) int {
+	var targets destinations
+	flags := flag.NewFlagSet("port-fill", flag.ContinueOnError)
+	usage := flags.Int("usage", 90, "share of the ephemeral port range to use")
+	flags.Var(&targets, "destination", "host:port to open the connections to, can be repeated")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if len(targets) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "at least one -destination is required")
+		return 2
+	}
+
+	terminated := make(chan os.Signal, 1)
+	signal.Notify(terminated, syscall.SIGTERM, syscall.SIGINT)
+
+	holder.RaiseNofileLimit()
+	current, err := ReadUsage("/proc/self/net")
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to read the port usage: %s\n", err)
+		return 1
+	}
+	count := current.Range*(*usage)/100 - current.Used
+	conns, err := fill(targets, count, (&net.Dialer{Timeout: dialTimeout}).Dial)
+	defer release(conns)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "stopped at %d connections: %s\n", len(conns), err)
+	}
+	fmt.Printf("holding %d connections\n", len(conns))
+
+	<-terminated
+	return 0
+}
+
+// fill opens count connections round-robin to the destinations. Running out of ports isn't an error,
+// that's what the attack is about, but a destination refusing the connections is.
+func fill(targets []string, count int, dial func(network, address string) (net.Conn, error)) ([]net.Conn, error) {
+	var (
+		mu       sync.Mutex
+		conns    []net.Conn
+		failures int
+		lastErr  error
+		wg       sync.WaitGroup
+	)
+	next := make(chan string)
+	stop := make(chan struct{})
+	for range dialWorkers {
+		wg.Go(func() {
+			for target := range next {
+				conn, err := dial("tcp", target)
+				mu.Lock()
+				if err == nil {
+					conns = append(conns, conn)
+					failures = 0
+				} else if errors.Is(err, syscall.EADDRNOTAVAIL) {
+					// the ephemeral range is exhausted
+					lastErr = err
+					failures = maxFailures
+				} else {
+					lastErr = err
+					failures++
+				}
+				if failures >= maxFailures {
+					select {
+					case <-stop:
+					default:
+						close(stop)
+					}
+				}
+				mu.Unlock()
+			}
+		})
+	}
+
+loop:
+	for i := range max(count, 0) {
+		select {
+		case next <- targets[i%len(targets)]:
+		case <-stop:
+			break loop
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	select {
+	case <-stop:
+		if errors.Is(lastErr, syscall.EADDRNOTAVAIL) {
+			return conns, nil
+		}
+		return conns, lastErr
+	default:
+		return conns, nil
+	}
+}
+
+func release(conns []net.Conn) {
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package portfill exhausts the ephemeral port range. The outbound connections are held by the
+// `port-fill` subcommand of the extension, which is started directly or as runc sidecar in the host
+// network namespace.
+package portfill
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/steadybit/action-kit/go/action_kit_commons/ociruntime"
+	"github.com/steadybit/extension-host/exthost/holder"
+)
+
+type Opts struct {
+	// Destinations are the host:port addresses the connections are opened to, round-robin
+	Destinations []string
+	// Usage is the share of ip_local_port_range to use
+	Usage int
+}
+
+func (o Opts) Args() []string {
+	args := []string{"port-fill", "-usage", strconv.Itoa(o.Usage)}
+	for _, destination := range o.Destinations {
+		args = append(args, "-destination", destination)
+	}
+	return args
+}
+
+func NewPortfillProcess(opts Opts) (holder.Holder, error) {
+	return holder.NewProcess(opts.Args())
+}
+
+func NewPortfillRunc(ctx context.Context, r ociruntime.OciRuntime, sidecar holder.SidecarOpts, opts Opts) (holder.Holder, error) {
+	// raising RLIMIT_NOFILE to hold a socket per port
+	return holder.NewRunc(ctx, r, sidecar, opts.Args(), "CAP_SYS_RESOURCE")
+}
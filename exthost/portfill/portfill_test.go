@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package portfill
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const procNetTcp = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:8000 0100007F:0050 01 00000000:00000000 00:00000000 00000000     0        0 2 1 0000000000000000 20 4 30 10 -1
+   2: 0100007F:8001 0100007F:0050 01 00000000:00000000 00:00000000 00000000     0        0 3 1 0000000000000000 20 4 30 10 -1
+   3: 0100007F:0016 0100007F:8002 01 00000000:00000000 00:00000000 00000000     0        0 4 1 0000000000000000 20 4 30 10 -1
+`
+
+const procNetTcp6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000001000000:8001 00000000000000000000000001000000:0050 01 00000000:00000000 00:00000000 00000000     0        0 5 1 0000000000000000 20 4 30 10 -1
+   1: 00000000000000000000000001000000:8003 00000000000000000000000001000000:0050 06 00000000:00000000 00:00000000 00000000     0        0 6 1 0000000000000000 20 4 30 10 -1
+`
+
+func TestReadUsage(t *testing.T) {
+	dir := t.TempDir()
+	procPortRange = filepath.Join(dir, "ip_local_port_range")
+	defer func() { procPortRange = "/proc/sys/net/ipv4/ip_local_port_range" }()
+	require.NoError(t, os.WriteFile(procPortRange, []byte("32768\t32867\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tcp"), []byte(procNetTcp), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tcp6"), []byte(procNetTcp6), 0644))
+
+	usage, err := ReadUsage(dir)
+	require.NoError(t, err)
+	// 0x8000, 0x8001 (tcp and tcp6) and 0x8003, the listener on 80 and the server side of 22 don't count
+	assert.Equal(t, Usage{Used: 3, Range: 100}, usage)
+	assert.Equal(t, float64(3), usage.Percent())
+}
+
+func TestOptsArgs(t *testing.T) {
+	opts := Opts{Destinations: []string{"10.0.0.1:80", "[::1]:443"}, Usage: 80}
+	assert.Equal(t, []string{"port-fill", "-usage", "80", "-destination", "10.0.0.1:80", "-destination", "[::1]:443"}, opts.Args())
+}
+
+func TestFill(t *testing.T) {
+	dialed := map[string]int{}
+	conns, err := fill([]string{"a:1", "b:2"}, 10, serialized(func(_, address string) (net.Conn, error) {
+		dialed[address]++
+		client, _ := net.Pipe()
+		return client, nil
+	}))
+	require.NoError(t, err)
+	assert.Len(t, conns, 10)
+	assert.Equal(t, map[string]int{"a:1": 5, "b:2": 5}, dialed)
+	release(conns)
+}
+
+func TestFillStopsWhenPortsAreExhausted(t *testing.T) {
+	opened := 0
+	dial := func(_, _ string) (net.Conn, error) {
+		if opened >= 5 {
+			return nil, &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.EADDRNOTAVAIL)}
+		}
+		opened++
+		client, _ := net.Pipe()
+		return client, nil
+	}
+
+	conns, err := fill([]string{"a:1", "b:2"}, 1000, serialized(dial))
+	require.NoError(t, err)
+	assert.Len(t, conns, 5)
+	release(conns)
+}
+
+func TestFillFailsWhenDestinationRefuses(t *testing.T) {
+	refused := errors.New("connection refused")
+	conns, err := fill([]string{"a:1"}, 1000, func(_, _ string) (net.Conn, error) {
+		return nil, refused
+	})
+	assert.ErrorIs(t, err, refused)
+	assert.Empty(t, conns)
+}
+
+// serialized guards fake dial functions, which are called by concurrent workers
+func serialized(dial func(network, address string) (net.Conn, error)) func(network, address string) (net.Conn, error) {
+	var mu sync.Mutex
+	return func(network, address string) (net.Conn, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return dial(network, address)
+	}
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package portfill
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var procPortRange = "/proc/sys/net/ipv4/ip_local_port_range"
+
+// tcpListen is the state of listening sockets in /proc/net/tcp, their ports aren't ephemeral
+const tcpListen = "0A"
+
+type Usage struct {
+	// Used is the number of local ports of the ephemeral range bound by TCP sockets
+	Used int
+	// Range is the size of the ephemeral range
+	Range int
+}
+
+func (u Usage) Percent() float64 {
+	if u.Range == 0 {
+		return 0
+	}
+	return float64(u.Used) * 100 / float64(u.Range)
+}
+
+// ReadPortRange reads net.ipv4.ip_local_port_range, which applies to IPv6 as well.
+func ReadPortRange() (int, int, error) {
+	content, err := os.ReadFile(procPortRange)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected content of %s: %s", procPortRange, content)
+	}
+	from, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	to, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
+
+// ReadUsage counts the distinct local ports of the ephemeral range bound by TCP sockets, as listed in
+// the tcp and tcp6 files of the given /proc/<pid>/net directory.
+func ReadUsage(procNet string) (Usage, error) {
+	from, to, err := ReadPortRange()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	ports := map[int]struct{}{}
+	for _, file := range []string{"tcp", "tcp6"} {
+		if err := readLocalPorts(filepath.Join(procNet, file), ports); err != nil && !os.IsNotExist(err) {
+			return Usage{}, err
+		}
+	}
+
+	usage := Usage{Range: to - from + 1}
+	for port := range ports {
+		if port >= from && port <= to {
+			usage.Used++
+		}
+	}
+	return usage, nil
+}
+
+func readLocalPorts(file string, ports map[int]struct{}) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] == tcpListen {
+			continue
+		}
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		if port, err := strconv.ParseInt(hexPort, 16, 32); err == nil {
+			ports[int(port)] = struct{}{}
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package stopprocess
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+var cgroupRoot = "/sys/fs/cgroup"
+
+// FreezeCgroup freezes or thaws all processes of a cgroup v2 hierarchy using cgroup.freeze.
+func FreezeCgroup(cgroup string, frozen bool) error {
+	value := "0"
+	if frozen {
+		value = "1"
+	}
+	return WriteCgroupFile(cgroup, "cgroup.freeze", value)
+}
+
+// CheckCgroup makes sure the cgroup can be resolved against the cgroup root. Processes outside the
+// extension's cgroup namespace have paths like /../../system.slice/nginx.service in /proc/<pid>/cgroup,
+// which would otherwise be joined to a different cgroup.
+func CheckCgroup(cgroup string) error {
+	if !strings.HasPrefix(cgroup, "/") || slices.Contains(strings.Split(cgroup, "/"), "..") {
+		return fmt.Errorf("cgroup %s is outside of the extension's cgroup namespace", cgroup)
+	}
+	return nil
+}
+
+// IsLeafCgroup reports whether the cgroup (or unit name) is a systemd service or scope. Slices group
+// whole sets of services, e.g. all system services or all pods of a node.
+func IsLeafCgroup(cgroup string) bool {
+	name := filepath.Base(cgroup)
+	return strings.HasSuffix(name, ".service") || strings.HasSuffix(name, ".scope")
+}
+
+// IsDelegatedCgroup reports whether systemd delegated the cgroup to its unit (Delegate=yes), so the unit
+// and not systemd manages the hierarchy below it. systemd marks such cgroups with an extended attribute.
+func IsDelegatedCgroup(cgroup string) bool {
+	path, err := cgroupFile(cgroup, "")
+	if err != nil {
+		return false
+	}
+	for _, attribute := range []string{"trusted.delegate", "user.delegate"} {
+		value := make([]byte, 1)
+		if n, err := unix.Getxattr(path, attribute, value); err == nil && string(value[:n]) == "1" {
+			return true
+		}
+	}
+	return false
+}
+
+func cgroupFile(cgroup, file string) (string, error) {
+	if err := CheckCgroup(cgroup); err != nil {
+		return "", err
+	}
+	return filepath.Join(cgroupRoot, cgroup, file), nil
+}
+
+// ReadCgroupFile reads an interface file of a cgroup v2 hierarchy, e.g. cpu.max.
+func ReadCgroupFile(cgroup, file string) (string, error) {
+	path, err := cgroupFile(cgroup, file)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// WriteCgroupFile writes an interface file of a cgroup v2 hierarchy.
+func WriteCgroupFile(cgroup, file, value string) error {
+	path, err := cgroupFile(cgroup, file)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadCgroupStats parses flat keyed files like cpu.stat or memory.events. Values which aren't numbers
+// are skipped.
+func ReadCgroupStats(cgroup, file string) (map[string]uint64, error) {
+	content, err := ReadCgroupFile(cgroup, file)
+	if err != nil {
+		return nil, err
+	}
+	stats := map[string]uint64{}
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64); err == nil {
+			stats[key] = n
+		}
+	}
+	return stats, nil
+}
+
+// CreateCgroup creates a child cgroup. An existing one is reused.
+func CreateCgroup(cgroup string) error {
+	path, err := cgroupFile(cgroup, "")
+	if err != nil {
+		return err
+	}
+	if err := os.Mkdir(path, 0755); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to create cgroup %s: %w", cgroup, err)
+	}
+	return nil
+}
+
+// RemoveCgroup removes a cgroup without processes. A missing one is ignored.
+func RemoveCgroup(cgroup string) error {
+	path, err := cgroupFile(cgroup, "")
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove cgroup %s: %w", cgroup, err)
+	}
+	return nil
+}
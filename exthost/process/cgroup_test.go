@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package stopprocess
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestCgroupFiles(t *testing.T) {
+	cgroupRoot = t.TempDir()
+	defer func() { cgroupRoot = "/sys/fs/cgroup" }()
+	require.NoError(t, os.MkdirAll(filepath.Join(cgroupRoot, "system.slice/orders.service"), 0755))
+
+	require.NoError(t, WriteCgroupFile("/system.slice/orders.service", "cpu.max", "50000 100000"))
+	value, err := ReadCgroupFile("/system.slice/orders.service", "cpu.max")
+	require.NoError(t, err)
+	assert.Equal(t, "50000 100000", value)
+
+	require.NoError(t, WriteCgroupFile("/system.slice/orders.service", "cpu.stat", "usage_usec 5012\nnr_periods 12\nnr_throttled 3\nthrottled_usec 1500\n"))
+	stats, err := ReadCgroupStats("/system.slice/orders.service", "cpu.stat")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"usage_usec": 5012, "nr_periods": 12, "nr_throttled": 3, "throttled_usec": 1500}, stats)
+
+	_, err = ReadCgroupFile("/system.slice/missing.service", "cpu.max")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCgroupOutsideOfNamespace(t *testing.T) {
+	assert.NoError(t, CheckCgroup("/system.slice/orders.service"))
+	assert.EqualError(t, CheckCgroup("/../../system.slice/orders.service"), "cgroup /../../system.slice/orders.service is outside of the extension's cgroup namespace")
+	assert.Error(t, CheckCgroup("system.slice"))
+
+	_, err := ReadCgroupFile("/../../system.slice/orders.service", "cpu.max")
+	assert.Error(t, err)
+	assert.Error(t, WriteCgroupFile("/..", "cgroup.freeze", "1"))
+}
+
+func TestIsLeafCgroup(t *testing.T) {
+	assert.True(t, IsLeafCgroup("/system.slice/orders.service"))
+	assert.True(t, IsLeafCgroup("/kubepods.slice/kubepods-pod1.slice/cri-containerd-abc.scope"))
+	assert.True(t, IsLeafCgroup("nginx.service"))
+	assert.False(t, IsLeafCgroup("/system.slice"))
+	assert.False(t, IsLeafCgroup("/kubepods.slice"))
+	assert.False(t, IsLeafCgroup("/"))
+}
+
+func TestCreateAndRemoveCgroup(t *testing.T) {
+	cgroupRoot = t.TempDir()
+	defer func() { cgroupRoot = "/sys/fs/cgroup" }()
+	require.NoError(t, os.MkdirAll(filepath.Join(cgroupRoot, "system.slice/orders.service"), 0755))
+
+	require.NoError(t, CreateCgroup("/system.slice/orders.service/throttled"))
+	require.NoError(t, CreateCgroup("/system.slice/orders.service/throttled"))
+	assert.DirExists(t, filepath.Join(cgroupRoot, "system.slice/orders.service/throttled"))
+
+	require.NoError(t, RemoveCgroup("/system.slice/orders.service/throttled"))
+	require.NoError(t, RemoveCgroup("/system.slice/orders.service/throttled"))
+	assert.NoDirExists(t, filepath.Join(cgroupRoot, "system.slice/orders.service/throttled"))
+
+	assert.Error(t, CreateCgroup("/../throttled"))
+}
+
+func TestIsDelegatedCgroup(t *testing.T) {
+	cgroupRoot = t.TempDir()
+	defer func() { cgroupRoot = "/sys/fs/cgroup" }()
+	require.NoError(t, os.MkdirAll(filepath.Join(cgroupRoot, "system.slice/containerd.service"), 0755))
+
+	assert.False(t, IsDelegatedCgroup("/system.slice/containerd.service"))
+	assert.False(t, IsDelegatedCgroup("/system.slice/missing.service"))
+	assert.False(t, IsDelegatedCgroup("/../containerd.service"))
+
+	if err := unix.Setxattr(filepath.Join(cgroupRoot, "system.slice/containerd.service"), "user.delegate", []byte("1"), 0); err != nil {
+		t.Skipf("extended attributes are not supported: %v", err)
+	}
+	assert.True(t, IsDelegatedCgroup("/system.slice/containerd.service"))
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package stopprocess
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MatchAuto matches a PID or a substring of the process name
+	MatchAuto = "auto"
+	// MatchName matches the process name, the executable or argv[0] exactly
+	MatchName = "name"
+	// MatchCmdline matches a regular expression against the full command line
+	MatchCmdline = "cmdline"
+	// MatchUser matches the name or uid of the owning user
+	MatchUser = "user"
+	// MatchCgroup matches a systemd unit or a cgroup path including its children
+	MatchCgroup = "cgroup"
+	// MatchParent matches the children of the given PID
+	MatchParent = "parent"
+)
+
+// protectedNames are matched against the executable and the command line to protect the Steadybit agent.
+var protectedNames = []string{"steadybit-agent", "steadybit/agent"}
+
+var selfPid = os.Getpid()
+
+type Selector struct {
+	Mode  string
+	Value string
+
+	pid     int
+	cmdline *regexp.Regexp
+}
+
+// NewSelector validates the value for the given match mode. An empty mode falls back to MatchAuto.
+func NewSelector(mode, value string) (Selector, error) {
+	if mode == "" {
+		mode = MatchAuto
+	}
+	s := Selector{Mode: mode, Value: value}
+	if value == "" {
+		return s, fmt.Errorf("value is required")
+	}
+
+	switch mode {
+	case MatchAuto:
+		if pid, err := strconv.Atoi(value); err == nil && pid > 0 {
+			s.pid = pid
+		}
+	case MatchName, MatchUser, MatchCgroup:
+	case MatchCmdline:
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return s, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		s.cmdline = re
+	case MatchParent:
+		pid, err := strconv.Atoi(value)
+		if err != nil || pid <= 0 {
+			return s, fmt.Errorf("parent must be a PID, got '%s'", value)
+		}
+		s.pid = pid
+	default:
+		return s, fmt.Errorf("unknown match mode '%s'", mode)
+	}
+	return s, nil
+}
+
+// Pid returns the PID if the selector targets a single process by PID.
+func (s Selector) Pid() (int, bool) {
+	return s.pid, s.Mode == MatchAuto && s.pid > 0
+}
+
+func (s Selector) Matches(p Process) bool {
+	switch s.Mode {
+	case MatchAuto:
+		if s.pid > 0 {
+			return p.Pid == s.pid
+		}
+		return strings.Contains(strings.TrimSpace(p.Name), s.Value)
+	case MatchName:
+		return p.Name == s.Value ||
+			(p.Executable != "" && filepath.Base(p.Executable) == s.Value) ||
+			(len(p.Cmdline) > 0 && filepath.Base(p.Cmdline[0]) == s.Value)
+	case MatchCmdline:
+		return len(p.Cmdline) > 0 && s.cmdline.MatchString(strings.Join(p.Cmdline, " "))
+	case MatchUser:
+		return p.User == s.Value || p.Uid == s.Value
+	case MatchCgroup:
+		return p.SystemdUnit == s.Value || p.Cgroup == s.Value || strings.HasPrefix(p.Cgroup, strings.TrimSuffix(s.Value, "/")+"/")
+	case MatchParent:
+		return p.PPid == s.pid
+	}
+	return false
+}
+
+// IsProtected reports whether the process must never be stopped: PID 1, kernel threads, the extension
+// itself and the Steadybit agent.
+func IsProtected(p Process) bool {
+	if p.Pid == 1 || p.Pid == selfPid || p.KernelThread {
+		return true
+	}
+	return slices.ContainsFunc(protectedNames, func(name string) bool {
+		return strings.Contains(p.Executable, name) || slices.ContainsFunc(p.Cmdline, func(arg string) bool {
+			return strings.Contains(arg, name)
+		})
+	})
+}
+
+// FindProcesses returns all unprotected processes matching the selector.
+func FindProcesses(s Selector) ([]Process, error) {
+	processes, err := listProcesses(false)
+	if err != nil {
+		return nil, err
+	}
+	var matches []Process
+	for _, p := range processes {
+		if s.Matches(p) && !IsProtected(p) {
+			matches = append(matches, p)
+		}
+	}
+	return matches, nil
+}
+
+// FindProtectedProcesses returns the protected processes matching the selector.
+func FindProtectedProcesses(s Selector) ([]Process, error) {
+	processes, err := listProcesses(false)
+	if err != nil {
+		return nil, err
+	}
+	var matches []Process
+	for _, p := range processes {
+		if s.Matches(p) && IsProtected(p) {
+			matches = append(matches, p)
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package stopprocess
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	jvm := Process{
+		Pid:         4711,
+		PPid:        812,
+		Name:        "java",
+		Executable:  "/usr/lib/jvm/java-21/bin/java",
+		Cmdline:     []string{"/usr/bin/java", "-jar", "/opt/orders/orders-service.jar"},
+		Uid:         "1001",
+		User:        "orders",
+		Cgroup:      "/system.slice/orders.service",
+		SystemdUnit: "orders.service",
+	}
+	longName := Process{
+		Pid:        4712,
+		PPid:       1,
+		Name:       "inventory-synch",
+		Executable: "/opt/inventory/inventory-synchronizer",
+		Cmdline:    []string{"/opt/inventory/inventory-synchronizer", "--interval=5s"},
+		Uid:        "0",
+		User:       "root",
+		Cgroup:     "/system.slice/inventory.service",
+	}
+
+	tests := []struct {
+		mode, value string
+		matches     []int
+	}{
+		{MatchAuto, "4711", []int{4711}},
+		{MatchAuto, "ja", []int{4711}},
+		{MatchAuto, "inventory", []int{4712}},
+		{MatchName, "java", []int{4711}},
+		{MatchName, "ja", nil},
+		{MatchName, "inventory-synchronizer", []int{4712}},
+		{MatchCmdline, `orders-service\.jar`, []int{4711}},
+		{MatchCmdline, `--interval=\d+s$`, []int{4712}},
+		{MatchUser, "orders", []int{4711}},
+		{MatchUser, "0", []int{4712}},
+		{MatchCgroup, "orders.service", []int{4711}},
+		{MatchCgroup, "/system.slice", []int{4711, 4712}},
+		{MatchCgroup, "/system.slice/orders", nil},
+		{MatchParent, "812", []int{4711}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode+"="+tt.value, func(t *testing.T) {
+			selector, err := NewSelector(tt.mode, tt.value)
+			require.NoError(t, err)
+
+			var matches []int
+			for _, p := range []Process{jvm, longName} {
+				if selector.Matches(p) {
+					matches = append(matches, p.Pid)
+				}
+			}
+			assert.Equal(t, tt.matches, matches)
+		})
+	}
+}
+
+func TestNewSelector(t *testing.T) {
+	selector, err := NewSelector("", "812")
+	require.NoError(t, err)
+	assert.Equal(t, MatchAuto, selector.Mode)
+	pid, ok := selector.Pid()
+	assert.True(t, ok)
+	assert.Equal(t, 812, pid)
+
+	_, err = NewSelector(MatchCmdline, "java(")
+	assert.ErrorContains(t, err, "invalid regular expression")
+	_, err = NewSelector(MatchParent, "nginx")
+	assert.EqualError(t, err, "parent must be a PID, got 'nginx'")
+	_, err = NewSelector("signal", "9")
+	assert.EqualError(t, err, "unknown match mode 'signal'")
+	_, err = NewSelector(MatchName, "")
+	assert.EqualError(t, err, "value is required")
+}
+
+func TestIsProtected(t *testing.T) {
+	assert.True(t, IsProtected(Process{Pid: 1, Name: "systemd"}))
+	assert.True(t, IsProtected(Process{Pid: os.Getpid()}))
+	assert.True(t, IsProtected(Process{Pid: 2, Name: "kthreadd", KernelThread: true}))
+	assert.True(t, IsProtected(Process{Pid: 900, Name: "java", Cmdline: []string{"java", "-jar", "/opt/steadybit/agent/agent.jar"}}))
+	assert.True(t, IsProtected(Process{Pid: 901, Name: "steadybit-agent", Executable: "/usr/bin/steadybit-agent"}))
+	assert.False(t, IsProtected(Process{Pid: 902, Name: "nginx", Executable: "/usr/sbin/nginx"}))
+}
@@ -0,0 +1,309 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package stopprocess
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var procPath = "/proc"
+
+const (
+	// pfKthread marks kernel threads in the flags of /proc/<pid>/stat
+	pfKthread = 0x00200000
+	// clockTicks is USER_HZ, which is 100 on all architectures we support
+	clockTicks = 100
+	// tcpListen is the state of listening sockets in /proc/<pid>/net/tcp
+	tcpListen = "0A"
+)
+
+var containerIdPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+type Process struct {
+	Pid  int
+	PPid int
+	// Name is the comm of the process, truncated by the kernel to 15 characters
+	Name         string
+	Executable   string
+	Cmdline      []string
+	Uid          string
+	User         string
+	Cgroup       string
+	SystemdUnit  string
+	ContainerId  string
+	StartedAt    time.Time
+	KernelThread bool
+	// ListeningPorts are the TCP ports the process listens on, in its own network namespace
+	ListeningPorts []int
+}
+
+// ListProcesses reads all processes from procfs. Processes vanishing while being read are skipped.
+func ListProcesses() ([]Process, error) {
+	return listProcesses(true)
+}
+
+func listProcesses(withPorts bool) ([]Process, error) {
+	entries, err := os.ReadDir(procPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	bootTime, err := readBootTime()
+	if err != nil {
+		return nil, err
+	}
+
+	users := map[string]string{}
+	listeners := map[string]map[string]int{}
+	var processes []Process
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		p, err := readProcess(pid, bootTime)
+		if err != nil {
+			continue
+		}
+
+		if name, ok := users[p.Uid]; ok {
+			p.User = name
+		} else {
+			p.User = lookupUser(p.Uid)
+			users[p.Uid] = p.User
+		}
+
+		if withPorts && !p.KernelThread {
+			p.ListeningPorts = readListeningPorts(pid, listeners)
+		}
+		processes = append(processes, p)
+	}
+	return processes, nil
+}
+
+// ReadProcess reads a single process from procfs. User and ListeningPorts are not resolved, use
+// ListProcesses for that.
+func ReadProcess(pid int) (Process, error) {
+	bootTime, err := readBootTime()
+	if err != nil {
+		return Process{Pid: pid}, err
+	}
+	return readProcess(pid, bootTime)
+}
+
+func readProcess(pid int, bootTime time.Time) (Process, error) {
+	dir := filepath.Join(procPath, strconv.Itoa(pid))
+	p := Process{Pid: pid}
+
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return p, err
+	}
+	sinceBoot, err := parseStat(string(stat), &p)
+	if err != nil {
+		return p, fmt.Errorf("failed to parse stat of %d: %w", pid, err)
+	}
+	p.StartedAt = bootTime.Add(sinceBoot)
+
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		p.Cmdline = strings.FieldsFunc(string(cmdline), func(r rune) bool { return r == 0 })
+	}
+	p.Executable, _ = os.Readlink(filepath.Join(dir, "exe"))
+	p.Uid = readUid(filepath.Join(dir, "status"))
+
+	if cgroup, err := os.ReadFile(filepath.Join(dir, "cgroup")); err == nil {
+		p.Cgroup = parseCgroup(string(cgroup))
+		p.SystemdUnit = systemdUnitOf(p.Cgroup)
+		p.ContainerId = containerIdPattern.FindString(p.Cgroup)
+	}
+	return p, nil
+}
+
+// parseStat extracts ppid, comm and flags from /proc/<pid>/stat and returns the start time relative
+// to the boot. The comm is enclosed in parentheses and may contain spaces and parentheses itself.
+func parseStat(stat string, p *Process) (time.Duration, error) {
+	start := strings.IndexByte(stat, '(')
+	end := strings.LastIndexByte(stat, ')')
+	if start < 0 || end < start {
+		return 0, fmt.Errorf("unexpected format")
+	}
+	p.Name = stat[start+1 : end]
+
+	// fields after the comm start with field 3 (state)
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("unexpected number of fields")
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, err
+	}
+	flags, err := strconv.ParseUint(fields[6], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	startTicks, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	p.PPid = ppid
+	p.KernelThread = flags&pfKthread != 0
+	return time.Duration(startTicks) * time.Second / clockTicks, nil
+}
+
+func readUid(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "Uid:"); ok {
+			if fields := strings.Fields(value); len(fields) > 0 {
+				return fields[0]
+			}
+		}
+	}
+	return ""
+}
+
+// parseCgroup returns the cgroup v2 path, or the path of the systemd hierarchy on cgroup v1 hosts.
+func parseCgroup(content string) string {
+	var fallback string
+	for _, line := range strings.Split(content, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		switch {
+		case parts[0] == "0" && parts[1] == "":
+			return parts[2]
+		case parts[1] == "name=systemd":
+			fallback = parts[2]
+		case fallback == "":
+			fallback = parts[2]
+		}
+	}
+	return fallback
+}
+
+func systemdUnitOf(cgroup string) string {
+	segments := strings.Split(cgroup, "/")
+	for _, segment := range slices.Backward(segments) {
+		if strings.HasSuffix(segment, ".service") || strings.HasSuffix(segment, ".scope") {
+			return segment
+		}
+	}
+	return ""
+}
+
+func readBootTime() (time.Time, error) {
+	f, err := os.Open(filepath.Join(procPath, "stat"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read boot time: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "btime "); ok {
+			btime, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("failed to parse boot time: %w", err)
+			}
+			return time.Unix(btime, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to read boot time: btime missing")
+}
+
+func lookupUser(uid string) string {
+	if uid == "" {
+		return ""
+	}
+	if u, err := user.LookupId(uid); err == nil {
+		return u.Username
+	}
+	return uid
+}
+
+// readListeningPorts resolves the listening TCP sockets of the process. The listening sockets are
+// read once per network namespace and cached in listeners (namespace -> socket inode -> port).
+func readListeningPorts(pid int, listeners map[string]map[string]int) []int {
+	dir := filepath.Join(procPath, strconv.Itoa(pid))
+	netns, err := os.Readlink(filepath.Join(dir, "ns", "net"))
+	if err != nil {
+		return nil
+	}
+	sockets, ok := listeners[netns]
+	if !ok {
+		sockets = map[string]int{}
+		for _, file := range []string{"tcp", "tcp6"} {
+			readListeningSockets(filepath.Join(dir, "net", file), sockets)
+		}
+		listeners[netns] = sockets
+	}
+	if len(sockets) == 0 {
+		return nil
+	}
+
+	fds, err := os.ReadDir(filepath.Join(dir, "fd"))
+	if err != nil {
+		return nil
+	}
+	var ports []int
+	for _, fd := range fds {
+		link, err := os.Readlink(filepath.Join(dir, "fd", fd.Name()))
+		if err != nil {
+			continue
+		}
+		inode, ok := strings.CutPrefix(link, "socket:[")
+		if !ok {
+			continue
+		}
+		if port, ok := sockets[strings.TrimSuffix(inode, "]")]; ok && !slices.Contains(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+	slices.Sort(ports)
+	return ports
+}
+
+func readListeningSockets(path string, sockets map[string]int) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpListen {
+			continue
+		}
+		_, portHex, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		port, err := strconv.ParseUint(portHex, 16, 16)
+		if err != nil {
+			continue
+		}
+		sockets[fields[9]] = int(port)
+	}
+}
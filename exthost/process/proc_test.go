@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package stopprocess
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProcFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func fakeProcess(t *testing.T, dir, stat, cmdline, cgroup string) {
+	writeProcFile(t, filepath.Join(dir, "stat"), stat)
+	writeProcFile(t, filepath.Join(dir, "cmdline"), cmdline)
+	writeProcFile(t, filepath.Join(dir, "cgroup"), cgroup)
+	writeProcFile(t, filepath.Join(dir, "status"), "Name:\tx\nUid:\t0\t0\t0\t0\nGid:\t0\t0\t0\t0\n")
+}
+
+func TestListProcesses(t *testing.T) {
+	procPath = t.TempDir()
+	defer func() { procPath = "/proc" }()
+
+	writeProcFile(t, filepath.Join(procPath, "stat"), "cpu  1 2 3\nbtime 1700000000\nprocesses 42\n")
+
+	const containerId = "4f3a6bd2d1e0a9c8b7f6e5d4c3b2a1908f7e6d5c4b3a2918e7d6c5b4a3928171"
+	web := filepath.Join(procPath, "812")
+	fakeProcess(t, web,
+		"812 (nginx: master) S 1 812 812 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 12345 0 0",
+		"nginx: master process\x00-g\x00daemon off;\x00",
+		"0::/system.slice/docker-"+containerId+".scope\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(web, "ns"), 0755))
+	require.NoError(t, os.Symlink("net:[4026531840]", filepath.Join(web, "ns", "net")))
+	require.NoError(t, os.MkdirAll(filepath.Join(web, "fd"), 0755))
+	require.NoError(t, os.Symlink("socket:[31337]", filepath.Join(web, "fd", "6")))
+	require.NoError(t, os.Symlink("/dev/null", filepath.Join(web, "fd", "0")))
+	writeProcFile(t, filepath.Join(web, "net", "tcp"),
+		"  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"+
+			"   0: 00000000:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 31337 1 0000000000000000 100 0 0 10 0\n"+
+			"   1: 0100007F:1F90 0100007F:A1B2 01 00000000:00000000 00:00000000 00000000     0        0 31338 1 0000000000000000 20 4 30 10 -1\n")
+
+	fakeProcess(t, filepath.Join(procPath, "2"),
+		"2 (kthreadd) S 0 0 0 0 -1 2129984 0 0 0 0 0 0 0 0 20 0 1 0 2 0 0",
+		"",
+		"0::/\n")
+
+	writeProcFile(t, filepath.Join(procPath, "self"), "")
+
+	processes, err := ListProcesses()
+	require.NoError(t, err)
+	require.Len(t, processes, 2)
+
+	kthreadd := processes[0]
+	assert.Equal(t, 2, kthreadd.Pid)
+	assert.True(t, kthreadd.KernelThread)
+	assert.Empty(t, kthreadd.Cmdline)
+
+	nginx := processes[1]
+	assert.Equal(t, 812, nginx.Pid)
+	assert.Equal(t, 1, nginx.PPid)
+	assert.Equal(t, "nginx: master", nginx.Name)
+	assert.False(t, nginx.KernelThread)
+	assert.Equal(t, []string{"nginx: master process", "-g", "daemon off;"}, nginx.Cmdline)
+	assert.Equal(t, "0", nginx.Uid)
+	assert.Equal(t, "root", nginx.User)
+	assert.Equal(t, "/system.slice/docker-"+containerId+".scope", nginx.Cgroup)
+	assert.Equal(t, "docker-"+containerId+".scope", nginx.SystemdUnit)
+	assert.Equal(t, containerId, nginx.ContainerId)
+	assert.Equal(t, time.Unix(1700000000, 0).Add(123450*time.Millisecond), nginx.StartedAt)
+	assert.Equal(t, []int{80}, nginx.ListeningPorts)
+}
+
+func TestParseCgroup(t *testing.T) {
+	assert.Equal(t, "/system.slice/cron.service", parseCgroup("0::/system.slice/cron.service\n"))
+	assert.Equal(t, "/system.slice/cron.service", parseCgroup("12:cpu,cpuacct:/system.slice\n1:name=systemd:/system.slice/cron.service\n"))
+	assert.Equal(t, "/user.slice", parseCgroup("4:memory:/user.slice\n"))
+	assert.Equal(t, "", parseCgroup(""))
+}
+
+func TestSystemdUnitOf(t *testing.T) {
+	assert.Equal(t, "cron.service", systemdUnitOf("/system.slice/cron.service"))
+	assert.Equal(t, "session-3.scope", systemdUnitOf("/user.slice/user-1000.slice/session-3.scope"))
+	assert.Equal(t, "", systemdUnitOf("/"))
+}
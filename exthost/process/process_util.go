@@ -10,8 +10,6 @@ import (
 	"github.com/mitchellh/go-ps"
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/action-kit/go/action_kit_commons/utils"
-	"github.com/steadybit/extension-kit/extutil"
-	"strings"
 	"syscall"
 )
 
@@ -63,22 +61,21 @@ func stopProcessUnix(pid int, force bool) error {
 	return err
 }
 
+// FindProcessIds returns the PIDs of the unprotected processes matching a PID or a substring of the
+// process name.
 func FindProcessIds(processOrPid string) []int {
-	pid := extutil.ToInt(processOrPid)
-	if pid > 0 {
-		return []int{pid}
+	selector, err := NewSelector(MatchAuto, processOrPid)
+	if err != nil {
+		return nil
 	}
-
-	var pids []int
-	processes, err := ps.Processes()
+	processes, err := FindProcesses(selector)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to list processes")
 		return nil
 	}
-	for _, process := range processes {
-		if strings.Contains(strings.TrimSpace(process.Executable()), processOrPid) {
-			pids = append(pids, process.Pid())
-		}
+	pids := make([]int, 0, len(processes))
+	for _, p := range processes {
+		pids = append(pids, p.Pid)
 	}
 	return pids
 }
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package stopprocess
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	RlimitNofile = "nofile"
+	RlimitNproc  = "nproc"
+	RlimitAs     = "as"
+)
+
+// Rlimits are the resource limits which can be changed by name.
+var Rlimits = map[string]int{
+	RlimitNofile: unix.RLIMIT_NOFILE,
+	RlimitNproc:  unix.RLIMIT_NPROC,
+	RlimitAs:     unix.RLIMIT_AS,
+}
+
+// RlimitInfinity is the value of an unlimited resource.
+const RlimitInfinity = unix.RLIM_INFINITY
+
+type Rlimit struct {
+	Soft uint64
+	Hard uint64
+}
+
+// GetRlimit reads a resource limit of another process using prlimit(2).
+func GetRlimit(pid int, resource string) (Rlimit, error) {
+	r, ok := Rlimits[resource]
+	if !ok {
+		return Rlimit{}, fmt.Errorf("unknown resource limit '%s'", resource)
+	}
+	var limit unix.Rlimit
+	if err := unix.Prlimit(pid, r, nil, &limit); err != nil {
+		return Rlimit{}, fmt.Errorf("failed to read %s limit of %d: %w", resource, pid, err)
+	}
+	return Rlimit{Soft: limit.Cur, Hard: limit.Max}, nil
+}
+
+// SetRlimit changes a resource limit of another process using prlimit(2). Raising the hard limit
+// requires CAP_SYS_RESOURCE.
+func SetRlimit(pid int, resource string, limit Rlimit) error {
+	r, ok := Rlimits[resource]
+	if !ok {
+		return fmt.Errorf("unknown resource limit '%s'", resource)
+	}
+	if err := unix.Prlimit(pid, r, &unix.Rlimit{Cur: limit.Soft, Max: limit.Hard}, nil); err != nil {
+		return fmt.Errorf("failed to set %s limit of %d: %w", resource, pid, err)
+	}
+	return nil
+}
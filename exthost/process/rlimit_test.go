@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package stopprocess
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRlimit(t *testing.T) {
+	original, err := GetRlimit(os.Getpid(), RlimitNofile)
+	require.NoError(t, err)
+	defer func() { _ = SetRlimit(os.Getpid(), RlimitNofile, original) }()
+
+	require.NoError(t, SetRlimit(os.Getpid(), RlimitNofile, Rlimit{Soft: 64, Hard: original.Hard}))
+	limit, err := GetRlimit(os.Getpid(), RlimitNofile)
+	require.NoError(t, err)
+	assert.Equal(t, Rlimit{Soft: 64, Hard: original.Hard}, limit)
+
+	_, err = GetRlimit(os.Getpid(), "core")
+	assert.EqualError(t, err, "unknown resource limit 'core'")
+}
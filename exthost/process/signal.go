@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package stopprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_commons/utils"
+	"golang.org/x/sys/unix"
+)
+
+// SendSignal sends the signal to the process, falling back to kill as root if the extension lacks the
+// permission to signal it.
+func SendSignal(pid int, signal syscall.Signal) error {
+	err := syscall.Kill(pid, signal)
+	if err == nil || errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	log.Debug().Err(err).Int("pid", pid).Str("signal", signal.String()).Msg("Failed to send signal via syscall")
+	if err := utils.RootCommandContext(context.Background(), "kill", "-s", strconv.Itoa(int(signal)), strconv.Itoa(pid)).Run(); err != nil {
+		return fmt.Errorf("failed to send %s to %d via exec: %w", signal, pid, err)
+	}
+	return nil
+}
+
+// IsSameProcess reports whether the PID still belongs to the process started at the given time and
+// wasn't reused in the meantime.
+func IsSameProcess(pid int, startedAt time.Time) bool {
+	p, err := ReadProcess(pid)
+	return err == nil && p.StartedAt.Equal(startedAt)
+}
+
+// suspendingSignals are refused with a hint to Freeze Processes, which resumes the processes afterwards.
+var suspendingSignals = []syscall.Signal{syscall.SIGSTOP, syscall.SIGTSTP, syscall.SIGCONT, syscall.SIGTTIN, syscall.SIGTTOU}
+
+// sigRtMin and sigRtMax are the range of the realtime signals on Linux.
+const (
+	sigRtMin = 34
+	sigRtMax = 64
+)
+
+// ParseSignal resolves a signal by name (with or without the SIG prefix, SIGRTMIN+n and SIGRTMAX-n for
+// realtime signals) or number. Signals suspending or resuming processes are refused, as they would be
+// neither journaled nor reverted, and so is SIGKILL, which Stop Processes covers.
+func ParseSignal(value string) (syscall.Signal, error) {
+	signal, ok := parseSignal(value)
+	if !ok {
+		return 0, fmt.Errorf("unknown signal '%s'", value)
+	}
+	if slices.Contains(suspendingSignals, signal) {
+		return 0, fmt.Errorf("signal '%s' suspends or resumes processes, use Freeze Processes instead", value)
+	}
+	if signal == syscall.SIGKILL {
+		return 0, fmt.Errorf("signal '%s' kills processes, use Stop Processes instead", value)
+	}
+	return signal, nil
+}
+
+func parseSignal(value string) (syscall.Signal, bool) {
+	var signal syscall.Signal
+	if number, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		signal = syscall.Signal(number)
+	} else {
+		name := strings.ToUpper(strings.TrimSpace(value))
+		if !strings.HasPrefix(name, "SIG") {
+			name = "SIG" + name
+		}
+		signal = unix.SignalNum(name)
+		if offset, ok := strings.CutPrefix(name, "SIGRTMIN"); ok {
+			signal = realtimeSignal(sigRtMin, offset)
+		} else if offset, ok := strings.CutPrefix(name, "SIGRTMAX"); ok {
+			signal = realtimeSignal(sigRtMax, offset)
+		}
+	}
+	return signal, signal > 0 && signal <= sigRtMax
+}
+
+// realtimeSignal resolves the offset of SIGRTMIN+n or SIGRTMAX-n, returning 0 if it is invalid.
+func realtimeSignal(base int, offset string) syscall.Signal {
+	if offset == "" {
+		return syscall.Signal(base)
+	}
+	n, err := strconv.Atoi(offset)
+	if err != nil || (base == sigRtMin && n < 0) || (base == sigRtMax && n > 0) || base+n < sigRtMin || base+n > sigRtMax {
+		return 0
+	}
+	return syscall.Signal(base + n)
+}
+
+// SignalName returns the name of the signal, e.g. SIGHUP or SIGRTMIN+2.
+func SignalName(signal syscall.Signal) string {
+	if name := unix.SignalName(signal); name != "" {
+		return name
+	}
+	if signal >= sigRtMin && signal <= sigRtMax {
+		return fmt.Sprintf("SIGRTMIN+%d", signal-sigRtMin)
+	}
+	return fmt.Sprintf("signal %d", signal)
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package stopprocess
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSignal(t *testing.T) {
+	for _, value := range []string{"SIGHUP", "hup", "HUP", "1"} {
+		signal, err := ParseSignal(value)
+		require.NoError(t, err)
+		assert.Equal(t, syscall.SIGHUP, signal)
+	}
+	signal, err := ParseSignal("SIGUSR2")
+	require.NoError(t, err)
+	assert.Equal(t, syscall.SIGUSR2, signal)
+
+	_, err = ParseSignal("SIGFOO")
+	assert.EqualError(t, err, "unknown signal 'SIGFOO'")
+	_, err = ParseSignal("0")
+	assert.Error(t, err)
+	_, err = ParseSignal("65")
+	assert.Error(t, err)
+}
+
+func TestParseSignal_AcceptsAnySignal(t *testing.T) {
+	for value, expected := range map[string]syscall.Signal{
+		"SIGALRM":    syscall.SIGALRM,
+		"pipe":       syscall.SIGPIPE,
+		"SIGSEGV":    syscall.SIGSEGV,
+		"WINCH":      syscall.SIGWINCH,
+		"SIGABRT":    syscall.SIGABRT,
+		"34":         34,
+		"SIGRTMIN":   34,
+		"RTMIN+3":    37,
+		"SIGRTMAX-1": 63,
+		"64":         64,
+	} {
+		signal, err := ParseSignal(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, signal, value)
+	}
+
+	for _, value := range []string{"SIGRTMIN-1", "SIGRTMAX+1", "SIGRTMIN+31"} {
+		_, err := ParseSignal(value)
+		assert.EqualError(t, err, "unknown signal '"+value+"'")
+	}
+}
+
+func TestParseSignal_RefusesSuspendingSignalsAndKill(t *testing.T) {
+	for _, value := range []string{"SIGSTOP", "tstp", "CONT", "19", "20", "18"} {
+		_, err := ParseSignal(value)
+		assert.EqualError(t, err, "signal '"+value+"' suspends or resumes processes, use Freeze Processes instead")
+	}
+	for _, value := range []string{"SIGKILL", "9"} {
+		_, err := ParseSignal(value)
+		assert.EqualError(t, err, "signal '"+value+"' kills processes, use Stop Processes instead")
+	}
+}
+
+func TestSignalName(t *testing.T) {
+	assert.Equal(t, "SIGHUP", SignalName(syscall.SIGHUP))
+	assert.Equal(t, "SIGRTMIN+2", SignalName(36))
+}
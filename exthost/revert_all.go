@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-kit/exthttp"
+)
+
+const RevertAllPath = "/revert-all"
+
+// RevertAll is the handler of the emergency revert endpoint. It reverts all active executions and responds
+// with a RevertReport per execution.
+func RevertAll(w http.ResponseWriter, r *http.Request, _ []byte) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAuthorizedForRevertAll(r, config.Config.RevertAllToken) {
+		log.Warn().Str("remoteAddr", r.RemoteAddr).Msg("rejected unauthorized revert-all request")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log.Warn().Str("remoteAddr", r.RemoteAddr).Msg("reverting all active executions on request")
+	exthttp.WriteBody(w, RevertAllExecutions(r.Context()))
+}
+
+func isAuthorizedForRevertAll(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthost
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAuthorizedForRevertAll(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		want          bool
+	}{
+		{name: "matching token", token: "secret", authorization: "Bearer secret", want: true},
+		{name: "wrong token", token: "secret", authorization: "Bearer other", want: false},
+		{name: "missing header", token: "secret", authorization: "", want: false},
+		{name: "not a bearer token", token: "secret", authorization: "Basic secret", want: false},
+		{name: "no token configured", token: "", authorization: "Bearer ", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", RevertAllPath, nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			assert.Equal(t, tt.want, isAuthorizedForRevertAll(r, tt.token))
+		})
+	}
+}
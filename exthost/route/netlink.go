@@ -0,0 +1,354 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package route
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// rtmsgLen is the size of struct rtmsg, see include/uapi/linux/rtnetlink.h
+	rtmsgLen = 12
+	// rtnexthopLen is the size of struct rtnexthop
+	rtnexthopLen = 8
+	// keptFlags are the flags of routes and next hops the kernel accepts back, the state reported in
+	// dumps, e.g. linkdown, is refused
+	keptFlags = unix.RTNH_F_ONLINK
+)
+
+type attrs []byte
+
+func (a *attrs) add(typ uint16, value []byte) {
+	header := make([]byte, unix.SizeofNlAttr)
+	binary.NativeEndian.PutUint16(header[0:], uint16(unix.SizeofNlAttr+len(value)))
+	binary.NativeEndian.PutUint16(header[2:], typ)
+	*a = append(*a, header...)
+	*a = append(*a, value...)
+	for len(*a)%unix.NLA_ALIGNTO != 0 {
+		*a = append(*a, 0)
+	}
+}
+
+func parseAttrs(b []byte, f func(typ uint16, value []byte, raw []byte)) {
+	for len(b) >= unix.SizeofNlAttr {
+		length := int(binary.NativeEndian.Uint16(b[0:]))
+		if length < unix.SizeofNlAttr || length > len(b) {
+			break
+		}
+		aligned := min((length+unix.NLA_ALIGNTO-1)&^(unix.NLA_ALIGNTO-1), len(b))
+		f(binary.NativeEndian.Uint16(b[2:])&^(unix.NLA_F_NESTED|unix.NLA_F_NET_BYTEORDER), b[unix.SizeofNlAttr:length], b[:aligned])
+		b = b[aligned:]
+	}
+}
+
+func message(typ uint16, flags uint16, seq uint32, body []byte) []byte {
+	b := make([]byte, unix.NLMSG_HDRLEN, unix.NLMSG_HDRLEN+len(body))
+	binary.NativeEndian.PutUint16(b[4:], typ)
+	binary.NativeEndian.PutUint16(b[6:], flags)
+	binary.NativeEndian.PutUint32(b[8:], seq)
+	b = append(b, body...)
+	binary.NativeEndian.PutUint32(b[0:], uint32(len(b)))
+	return b
+}
+
+func rtmsg(family uint8, dstLen int, scope uint8, typ uint8) []byte {
+	b := make([]byte, rtmsgLen)
+	b[0] = family
+	b[1] = uint8(dstLen)
+	b[4] = unix.RT_TABLE_MAIN
+	b[5] = unix.RTPROT_STATIC
+	b[6] = scope
+	b[7] = typ
+	return b
+}
+
+// body returns the route as dumped by the kernel, or builds it from the fields for the routes added.
+func (r Route) body() []byte {
+	if len(r.Raw) > 0 {
+		return r.Raw
+	}
+	scope := uint8(unix.RT_SCOPE_UNIVERSE)
+	if r.Type == unix.RTN_UNICAST && r.Gateway == nil {
+		scope = unix.RT_SCOPE_LINK
+	}
+	ip := r.Dst.IP.To16()
+	if r.Family == unix.AF_INET {
+		ip = r.Dst.IP.To4()
+	}
+
+	body := attrs(rtmsg(r.Family, prefixLen(r.Dst), scope, r.Type))
+	if prefixLen(r.Dst) > 0 {
+		body.add(unix.RTA_DST, ip)
+	}
+	if r.Gateway != nil {
+		gateway := r.Gateway.To16()
+		if r.Family == unix.AF_INET {
+			gateway = r.Gateway.To4()
+		}
+		body.add(unix.RTA_GATEWAY, gateway)
+	}
+	if r.Oif != 0 {
+		body.add(unix.RTA_OIF, binary.NativeEndian.AppendUint32(nil, uint32(r.Oif)))
+	}
+	if r.Priority != 0 {
+		body.add(unix.RTA_PRIORITY, binary.NativeEndian.AppendUint32(nil, r.Priority))
+	}
+	return body
+}
+
+func newRouteMessage(seq uint32, r Route) []byte {
+	return message(unix.RTM_NEWROUTE, unix.NLM_F_REQUEST|unix.NLM_F_ACK|unix.NLM_F_CREATE|unix.NLM_F_EXCL, seq, r.body())
+}
+
+func delRouteMessage(seq uint32, r Route) []byte {
+	return message(unix.RTM_DELROUTE, unix.NLM_F_REQUEST|unix.NLM_F_ACK, seq, r.body())
+}
+
+func getRouteMessage(seq uint32, ip net.IP) []byte {
+	f := family(ip)
+	addr := ip.To16()
+	if f == unix.AF_INET {
+		addr = ip.To4()
+	}
+	body := attrs(rtmsg(f, len(addr)*8, 0, 0))
+	body[4], body[5] = 0, 0
+	body.add(unix.RTA_DST, addr)
+	return message(unix.RTM_GETROUTE, unix.NLM_F_REQUEST, seq, body)
+}
+
+func getLinkMessage(seq uint32, index int) []byte {
+	body := make([]byte, unix.SizeofIfInfomsg)
+	binary.NativeEndian.PutUint32(body[4:], uint32(index))
+	return message(unix.RTM_GETLINK, unix.NLM_F_REQUEST, seq, body)
+}
+
+func dumpMessage(seq uint32) []byte {
+	return message(unix.RTM_GETROUTE, unix.NLM_F_REQUEST|unix.NLM_F_DUMP, seq, make([]byte, rtmsgLen))
+}
+
+// parseRoute parses the payload of a route message. The cache info is dump only and left out of Raw.
+func parseRoute(data []byte) (Route, uint32, bool) {
+	if len(data) < rtmsgLen {
+		return Route{}, 0, false
+	}
+	r := Route{Family: data[0], Type: data[7]}
+	if r.Family != unix.AF_INET && r.Family != unix.AF_INET6 {
+		return Route{}, 0, false
+	}
+	table := uint32(data[4])
+	r.Dst = net.IPNet{IP: zeroIP(r.Family), Mask: net.CIDRMask(int(data[1]), len(zeroIP(r.Family))*8)}
+
+	raw := append([]byte(nil), data[:rtmsgLen]...)
+	binary.NativeEndian.PutUint32(raw[8:], binary.NativeEndian.Uint32(raw[8:])&keptFlags)
+	parseAttrs(data[rtmsgLen:], func(typ uint16, value []byte, attr []byte) {
+		switch typ {
+		case unix.RTA_MULTIPATH:
+			raw = append(raw, clearNexthopFlags(attr)...)
+			return
+		case unix.RTA_TABLE:
+			if len(value) == 4 {
+				table = binary.NativeEndian.Uint32(value)
+			}
+		case unix.RTA_DST:
+			r.Dst.IP = append(net.IP(nil), value...)
+		case unix.RTA_GATEWAY:
+			r.Gateway = append(net.IP(nil), value...)
+		case unix.RTA_OIF:
+			if len(value) == 4 {
+				r.Oif = int(binary.NativeEndian.Uint32(value))
+			}
+		case unix.RTA_PRIORITY:
+			if len(value) == 4 {
+				r.Priority = binary.NativeEndian.Uint32(value)
+			}
+		case unix.RTA_CACHEINFO:
+			return
+		}
+		raw = append(raw, attr...)
+	})
+	r.Raw = raw
+	return r, table, true
+}
+
+// clearNexthopFlags returns a copy of the multipath attribute with the next hop flags cleared.
+func clearNexthopFlags(attr []byte) []byte {
+	attr = append([]byte(nil), attr...)
+	for b := attr[unix.SizeofNlAttr:]; len(b) >= rtnexthopLen; {
+		length := int(binary.NativeEndian.Uint16(b[0:]))
+		if length < rtnexthopLen || length > len(b) {
+			break
+		}
+		b[2] &= keptFlags
+		b = b[min((length+unix.RTNH_ALIGNTO-1)&^(unix.RTNH_ALIGNTO-1), len(b)):]
+	}
+	return attr
+}
+
+type conn struct {
+	fd  int
+	seq uint32
+	buf []byte
+}
+
+// dial opens an rtnetlink socket in the given network namespace. The socket stays in the namespace it was
+// created in, so only the creation happens on a thread switched to the namespace.
+func dial(netns string) (*conn, error) {
+	if netns == "" {
+		return dialCurrent()
+	}
+
+	target, err := os.Open(netns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open network namespace: %w", err)
+	}
+	defer func() { _ = target.Close() }()
+
+	runtime.LockOSThread()
+	original, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()))
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("failed to open network namespace: %w", err)
+	}
+	defer func() { _ = original.Close() }()
+
+	if err := unix.Setns(int(target.Fd()), unix.CLONE_NEWNET); err != nil {
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("failed to enter network namespace: %w", err)
+	}
+	c, dialErr := dialCurrent()
+	if err := unix.Setns(int(original.Fd()), unix.CLONE_NEWNET); err != nil {
+		// the thread is left locked, so it's terminated instead of being reused in the wrong namespace
+		if c != nil {
+			_ = c.close()
+		}
+		return nil, fmt.Errorf("failed to leave network namespace: %w", err)
+	}
+	runtime.UnlockOSThread()
+	return c, dialErr
+}
+
+func dialCurrent() (*conn, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rtnetlink socket: %w", err)
+	}
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("failed to bind rtnetlink socket: %w", err)
+	}
+	return &conn{fd: fd, buf: make([]byte, 1<<16)}, nil
+}
+
+func (c *conn) close() error {
+	return unix.Close(c.fd)
+}
+
+func (c *conn) nextSeq() uint32 {
+	c.seq++
+	return c.seq
+}
+
+func (c *conn) receive() ([]syscall.NetlinkMessage, error) {
+	n, _, err := unix.Recvfrom(c.fd, c.buf, 0)
+	if err != nil {
+		return nil, err
+	}
+	return syscall.ParseNetlinkMessage(c.buf[:n])
+}
+
+func parseError(msg syscall.NetlinkMessage) error {
+	if len(msg.Data) < 4 {
+		return errors.New("unexpected rtnetlink error")
+	}
+	if errno := -int32(binary.NativeEndian.Uint32(msg.Data)); errno != 0 {
+		return syscall.Errno(errno)
+	}
+	return nil
+}
+
+// request sends the message and passes the responses to f until it's done or acknowledged.
+func (c *conn) request(m []byte, f func(data []byte)) error {
+	seq := binary.NativeEndian.Uint32(m[8:])
+	if err := unix.Sendto(c.fd, m, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return err
+	}
+	for {
+		msgs, err := c.receive()
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			if msg.Header.Seq != seq {
+				continue
+			}
+			switch msg.Header.Type {
+			case unix.NLMSG_DONE:
+				return nil
+			case unix.NLMSG_ERROR:
+				return parseError(msg)
+			default:
+				f(msg.Data)
+				if msg.Header.Flags&unix.NLM_F_MULTI == 0 {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func (c *conn) execute(m []byte) error {
+	return c.request(m, func([]byte) {})
+}
+
+// list returns the routes of the main table.
+func (c *conn) list() ([]Route, error) {
+	var routes []Route
+	err := c.request(dumpMessage(c.nextSeq()), func(data []byte) {
+		if r, table, ok := parseRoute(data); ok && table == unix.RT_TABLE_MAIN {
+			routes = append(routes, r)
+		}
+	})
+	return routes, err
+}
+
+// get looks up the route the ip is reached on.
+func (c *conn) get(ip net.IP) (Route, error) {
+	var route Route
+	var found bool
+	err := c.request(getRouteMessage(c.nextSeq(), ip), func(data []byte) {
+		route, _, found = parseRoute(data)
+	})
+	if err == nil && !found {
+		err = fmt.Errorf("no route to %s", ip)
+	}
+	return route, err
+}
+
+// linkName looks up the name of the interface with the index.
+func (c *conn) linkName(index int) (string, error) {
+	var name string
+	err := c.request(getLinkMessage(c.nextSeq(), index), func(data []byte) {
+		if len(data) < unix.SizeofIfInfomsg {
+			return
+		}
+		parseAttrs(data[unix.SizeofIfInfomsg:], func(typ uint16, value []byte, _ []byte) {
+			if typ == unix.IFLA_IFNAME {
+				name = strings.TrimRight(string(value), "\x00")
+			}
+		})
+	})
+	if err == nil && name == "" {
+		err = fmt.Errorf("no interface with index %d", index)
+	}
+	return name, err
+}
@@ -0,0 +1,339 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package route installs blackhole, unreachable or prohibit routes, or removes or replaces the default
+// routes of a network namespace via rtnetlink. The routes replaced are snapshotted, so they can be restored
+// unaltered.
+package route
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+type Mode string
+
+const (
+	ModeBlackhole      Mode = "blackhole"
+	ModeUnreachable    Mode = "unreachable"
+	ModeProhibit       Mode = "prohibit"
+	ModeRemoveDefault  Mode = "remove-default"
+	ModeReplaceDefault Mode = "replace-default"
+)
+
+// routeTypes are the route types installed for the CIDRs of the modes
+var routeTypes = map[Mode]uint8{
+	ModeBlackhole:   unix.RTN_BLACKHOLE,
+	ModeUnreachable: unix.RTN_UNREACHABLE,
+	ModeProhibit:    unix.RTN_PROHIBIT,
+}
+
+type Opts struct {
+	Mode Mode
+	// Cidrs get a route of the type of the mode, only used for blackhole, unreachable and prohibit
+	Cidrs []net.IPNet
+	// Gateway replaces the default route of its family, only used for replace-default
+	Gateway net.IP
+}
+
+func (o Opts) Validate() error {
+	switch o.Mode {
+	case ModeBlackhole, ModeUnreachable, ModeProhibit:
+		if len(o.Cidrs) == 0 {
+			return errors.New("at least one CIDR is required")
+		}
+	case ModeRemoveDefault:
+	case ModeReplaceDefault:
+		if o.Gateway == nil {
+			return errors.New("a gateway is required")
+		}
+	default:
+		return fmt.Errorf("unknown mode '%s'", o.Mode)
+	}
+	return nil
+}
+
+// Route is a route of the main table. Raw holds the route as dumped by the kernel, so it is restored
+// unaltered, including e.g. multiple next hops or metrics.
+type Route struct {
+	Family   uint8
+	Type     uint8
+	Dst      net.IPNet
+	Gateway  net.IP `json:",omitempty"`
+	Oif      int    `json:",omitempty"`
+	Priority uint32 `json:",omitempty"`
+	Raw      []byte `json:",omitempty"`
+}
+
+func (r Route) String() string {
+	var sb strings.Builder
+	switch r.Type {
+	case unix.RTN_BLACKHOLE:
+		sb.WriteString("blackhole ")
+	case unix.RTN_UNREACHABLE:
+		sb.WriteString("unreachable ")
+	case unix.RTN_PROHIBIT:
+		sb.WriteString("prohibit ")
+	}
+	if ones, _ := r.Dst.Mask.Size(); ones == 0 {
+		sb.WriteString("default")
+	} else {
+		sb.WriteString(r.Dst.String())
+	}
+	if r.Gateway != nil {
+		sb.WriteString(" via ")
+		sb.WriteString(r.Gateway.String())
+	}
+	if r.Oif != 0 {
+		if iface, err := net.InterfaceByIndex(r.Oif); err == nil {
+			fmt.Fprintf(&sb, " dev %s", iface.Name)
+		} else {
+			fmt.Fprintf(&sb, " dev #%d", r.Oif)
+		}
+	}
+	if r.Priority != 0 {
+		fmt.Fprintf(&sb, " metric %d", r.Priority)
+	}
+	return sb.String()
+}
+
+func (r Route) isDefault() bool {
+	ones, _ := r.Dst.Mask.Size()
+	return ones == 0
+}
+
+// Snapshot holds the changes to the routing table. Pinned routes keep the restricted endpoints reachable
+// on their current path, they are added before and removed after the others.
+type Snapshot struct {
+	Pinned  []Route `json:",omitempty"`
+	Removed []Route `json:",omitempty"`
+	Added   []Route `json:",omitempty"`
+}
+
+func (s Snapshot) IsEmpty() bool {
+	return len(s.Pinned) == 0 && len(s.Removed) == 0 && len(s.Added) == 0
+}
+
+// Plan snapshots the routes of the network namespace changed by the opts. The restricted networks are
+// pinned to the path they are routed on now.
+func Plan(netns string, opts Opts, restricted []net.IPNet) (Snapshot, error) {
+	c, err := dial(netns)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer func() { _ = c.close() }()
+
+	routes, err := c.list()
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to list routes: %w", err)
+	}
+
+	var paths []Route
+	for _, network := range restricted {
+		if ones, _ := network.Mask.Size(); ones == 0 {
+			continue
+		}
+		path, err := c.get(network.IP)
+		if err != nil || path.Type != unix.RTN_UNICAST {
+			// unreachable already or local
+			continue
+		}
+		paths = append(paths, Route{Family: path.Family, Type: unix.RTN_UNICAST, Dst: network, Gateway: path.Gateway, Oif: path.Oif})
+	}
+
+	return plan(routes, opts, paths)
+}
+
+func plan(routes []Route, opts Opts, paths []Route) (Snapshot, error) {
+	var snapshot Snapshot
+	var pins []Route
+
+	switch opts.Mode {
+	case ModeBlackhole, ModeUnreachable, ModeProhibit:
+		for i, cidr := range opts.Cidrs {
+			if slices.ContainsFunc(opts.Cidrs[:i], func(n net.IPNet) bool { return sameNet(n, cidr) }) {
+				continue
+			}
+			for _, path := range paths {
+				if !overlaps(cidr, path.Dst) {
+					continue
+				}
+				if prefixLen(path.Dst) <= prefixLen(cidr) {
+					return Snapshot{}, fmt.Errorf("%s would affect the restricted endpoint %s", cidr.String(), path.Dst.String())
+				}
+				pins = appendRoute(pins, path)
+			}
+			for _, r := range routes {
+				if sameNet(r.Dst, cidr) {
+					snapshot.Removed = append(snapshot.Removed, r)
+				}
+			}
+			snapshot.Added = append(snapshot.Added, Route{Family: family(cidr.IP), Type: routeTypes[opts.Mode], Dst: cidr})
+		}
+
+	case ModeRemoveDefault, ModeReplaceDefault:
+		var gatewayFamily uint8
+		if opts.Mode == ModeReplaceDefault {
+			gatewayFamily = family(opts.Gateway)
+		}
+		for _, r := range routes {
+			if r.isDefault() && (gatewayFamily == 0 || r.Family == gatewayFamily) {
+				snapshot.Removed = append(snapshot.Removed, r)
+			}
+		}
+		for _, path := range paths {
+			if gatewayFamily == 0 || path.Family == gatewayFamily {
+				pins = appendRoute(pins, path)
+			}
+		}
+		if opts.Mode == ModeReplaceDefault {
+			snapshot.Added = append(snapshot.Added, Route{
+				Family:  gatewayFamily,
+				Type:    unix.RTN_UNICAST,
+				Dst:     net.IPNet{IP: zeroIP(gatewayFamily), Mask: net.CIDRMask(0, len(zeroIP(gatewayFamily))*8)},
+				Gateway: opts.Gateway,
+			})
+		}
+	}
+
+	// networks already routed explicitly don't need to be pinned
+	for _, pin := range pins {
+		if !containsNet(routes, pin.Dst) {
+			snapshot.Pinned = append(snapshot.Pinned, pin)
+		}
+	}
+	return snapshot, nil
+}
+
+// Apply changes the routing table as planned. If it fails, the snapshot must be restored.
+func Apply(netns string, snapshot Snapshot) error {
+	c, err := dial(netns)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.close() }()
+
+	for _, r := range snapshot.Pinned {
+		if err := c.execute(newRouteMessage(c.nextSeq(), r)); err != nil {
+			return fmt.Errorf("failed to pin %s: %w", r, err)
+		}
+	}
+	for _, r := range snapshot.Removed {
+		if err := c.execute(delRouteMessage(c.nextSeq(), r)); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return fmt.Errorf("failed to remove %s: %w", r, err)
+		}
+	}
+	for _, r := range snapshot.Added {
+		if err := c.execute(newRouteMessage(c.nextSeq(), r)); err != nil {
+			return fmt.Errorf("failed to add %s: %w", r, err)
+		}
+	}
+	return nil
+}
+
+// Restore reverts the changes of the snapshot. Changes which weren't applied are skipped, so it may be
+// called for a partially applied snapshot or more than once.
+func Restore(netns string, snapshot Snapshot) error {
+	c, err := dial(netns)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.close() }()
+
+	var errs []error
+	for _, r := range snapshot.Added {
+		if err := c.execute(delRouteMessage(c.nextSeq(), r)); err != nil && !errors.Is(err, syscall.ESRCH) {
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", r, err))
+		}
+	}
+	for _, r := range snapshot.Removed {
+		if err := c.execute(newRouteMessage(c.nextSeq(), r)); err != nil && !errors.Is(err, syscall.EEXIST) {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", r, err))
+		}
+	}
+	for _, r := range snapshot.Pinned {
+		if err := c.execute(delRouteMessage(c.nextSeq(), r)); err != nil && !errors.Is(err, syscall.ESRCH) {
+			errs = append(errs, fmt.Errorf("failed to unpin %s: %w", r, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Interfaces returns the names of the interfaces the traffic to the destinations is routed on in the network
+// namespace. Destinations without a route are skipped.
+func Interfaces(netns string, destinations []net.IP) ([]string, error) {
+	c, err := dial(netns)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = c.close() }()
+
+	var names []string
+	for _, ip := range destinations {
+		path, err := c.get(ip)
+		if err != nil || path.Oif == 0 {
+			continue
+		}
+		name, err := c.linkName(path.Oif)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up interface %d: %w", path.Oif, err)
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func family(ip net.IP) uint8 {
+	if ip.To4() != nil {
+		return unix.AF_INET
+	}
+	return unix.AF_INET6
+}
+
+func zeroIP(family uint8) net.IP {
+	if family == unix.AF_INET {
+		return net.IPv4zero.To4()
+	}
+	return net.IPv6zero
+}
+
+func prefixLen(n net.IPNet) int {
+	ones, _ := n.Mask.Size()
+	return ones
+}
+
+func overlaps(a, b net.IPNet) bool {
+	if family(a.IP) != family(b.IP) {
+		return false
+	}
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+func sameNet(a, b net.IPNet) bool {
+	return family(a.IP) == family(b.IP) && a.IP.Equal(b.IP) && bytes.Equal(a.Mask, b.Mask)
+}
+
+func containsNet(routes []Route, n net.IPNet) bool {
+	for _, r := range routes {
+		if sameNet(r.Dst, n) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendRoute(routes []Route, r Route) []Route {
+	if containsNet(routes, r.Dst) {
+		return routes
+	}
+	return append(routes, r)
+}
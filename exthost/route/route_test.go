@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package route
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func cidr(s string) net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return *n
+}
+
+var (
+	defaultV4 = Route{Family: unix.AF_INET, Type: unix.RTN_UNICAST, Dst: cidr("0.0.0.0/0"), Gateway: net.ParseIP("10.0.0.1").To4(), Oif: 2, Priority: 100, Raw: []byte{1}}
+	defaultV6 = Route{Family: unix.AF_INET6, Type: unix.RTN_UNICAST, Dst: cidr("::/0"), Gateway: net.ParseIP("fe80::1"), Oif: 2, Raw: []byte{2}}
+	subnet    = Route{Family: unix.AF_INET, Type: unix.RTN_UNICAST, Dst: cidr("10.0.0.0/24"), Oif: 2, Raw: []byte{3}}
+	platform  = Route{Family: unix.AF_INET, Type: unix.RTN_UNICAST, Dst: cidr("34.1.2.3/32"), Gateway: net.ParseIP("10.0.0.1").To4(), Oif: 2}
+)
+
+func TestPlanBlackhole(t *testing.T) {
+	snapshot, err := plan([]Route{defaultV4, subnet}, Opts{Mode: ModeBlackhole, Cidrs: []net.IPNet{cidr("10.0.0.0/24"), cidr("192.168.0.0/16"), cidr("10.0.0.0/24")}}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, snapshot.Pinned)
+	assert.Equal(t, []Route{subnet}, snapshot.Removed)
+	require.Len(t, snapshot.Added, 2)
+	assert.Equal(t, "blackhole 10.0.0.0/24", snapshot.Added[0].String())
+	assert.Equal(t, "blackhole 192.168.0.0/16", snapshot.Added[1].String())
+}
+
+func TestPlanPinsRestrictedEndpoints(t *testing.T) {
+	snapshot, err := plan([]Route{defaultV4}, Opts{Mode: ModeUnreachable, Cidrs: []net.IPNet{cidr("34.0.0.0/8")}}, []Route{platform})
+	require.NoError(t, err)
+	assert.Equal(t, []Route{platform}, snapshot.Pinned)
+	assert.Equal(t, "unreachable 34.0.0.0/8", snapshot.Added[0].String())
+}
+
+func TestPlanRefusesToAffectRestrictedEndpoints(t *testing.T) {
+	_, err := plan([]Route{defaultV4}, Opts{Mode: ModeProhibit, Cidrs: []net.IPNet{cidr("34.1.2.3/32")}}, []Route{platform})
+	assert.ErrorContains(t, err, "restricted endpoint 34.1.2.3/32")
+}
+
+func TestPlanRemoveDefault(t *testing.T) {
+	snapshot, err := plan([]Route{defaultV4, defaultV6, subnet}, Opts{Mode: ModeRemoveDefault}, []Route{platform})
+	require.NoError(t, err)
+	assert.Equal(t, []Route{defaultV4, defaultV6}, snapshot.Removed)
+	assert.Equal(t, []Route{platform}, snapshot.Pinned)
+	assert.Empty(t, snapshot.Added)
+}
+
+func TestPlanReplaceDefaultOfGatewayFamily(t *testing.T) {
+	snapshot, err := plan([]Route{defaultV4, defaultV6, subnet}, Opts{Mode: ModeReplaceDefault, Gateway: net.ParseIP("10.0.0.254")}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Route{defaultV4}, snapshot.Removed)
+	require.Len(t, snapshot.Added, 1)
+	assert.Equal(t, "default via 10.0.0.254", snapshot.Added[0].String())
+}
+
+func TestPlanSkipsPinsOfExplicitRoutes(t *testing.T) {
+	explicit := platform
+	explicit.Raw = []byte{4}
+	snapshot, err := plan([]Route{defaultV4, explicit}, Opts{Mode: ModeRemoveDefault}, []Route{platform})
+	require.NoError(t, err)
+	assert.Empty(t, snapshot.Pinned)
+}
+
+func TestOptsValidate(t *testing.T) {
+	assert.NoError(t, Opts{Mode: ModeBlackhole, Cidrs: []net.IPNet{cidr("10.0.0.0/8")}}.Validate())
+	assert.Error(t, Opts{Mode: ModeBlackhole}.Validate())
+	assert.NoError(t, Opts{Mode: ModeRemoveDefault}.Validate())
+	assert.Error(t, Opts{Mode: ModeReplaceDefault}.Validate())
+	assert.Error(t, Opts{Mode: "drop"}.Validate())
+}
+
+func TestParseRouteKeepsRawWithoutCacheInfo(t *testing.T) {
+	body := attrs(rtmsg(unix.AF_INET, 24, unix.RT_SCOPE_LINK, unix.RTN_UNICAST))
+	body.add(unix.RTA_TABLE, []byte{unix.RT_TABLE_MAIN, 0, 0, 0})
+	body.add(unix.RTA_DST, []byte{10, 0, 0, 0})
+	body.add(unix.RTA_OIF, []byte{2, 0, 0, 0})
+	withCacheInfo := append(attrs(nil), body...)
+	withCacheInfo.add(unix.RTA_CACHEINFO, make([]byte, 32))
+
+	r, table, ok := parseRoute(withCacheInfo)
+	require.True(t, ok)
+	assert.Equal(t, uint32(unix.RT_TABLE_MAIN), table)
+	assert.Equal(t, "10.0.0.0/24", r.Dst.String())
+	assert.Equal(t, 2, r.Oif)
+	assert.Equal(t, []byte(body), r.Raw)
+}
+
+func TestRouteBody(t *testing.T) {
+	r := Route{Family: unix.AF_INET, Type: unix.RTN_BLACKHOLE, Dst: cidr("192.168.0.0/16")}
+	parsed, table, ok := parseRoute(r.body())
+	require.True(t, ok)
+	assert.Equal(t, uint32(unix.RT_TABLE_MAIN), table)
+	assert.Equal(t, uint8(unix.RTN_BLACKHOLE), parsed.Type)
+	assert.Equal(t, "192.168.0.0/16", parsed.Dst.String())
+}
+
+func TestSnapshotSurvivesJson(t *testing.T) {
+	snapshot := Snapshot{Pinned: []Route{platform}, Removed: []Route{defaultV4}}
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+
+	var decoded Snapshot
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, defaultV4.Raw, decoded.Removed[0].Raw)
+	assert.True(t, sameNet(platform.Dst, decoded.Pinned[0].Dst))
+	assert.Equal(t, platform.String(), decoded.Pinned[0].String())
+}
+
+func TestParseRouteClearsLinkdown(t *testing.T) {
+	body := attrs(rtmsg(unix.AF_INET, 12, unix.RT_SCOPE_UNIVERSE, unix.RTN_UNICAST))
+	binary.NativeEndian.PutUint32(body[8:], unix.RTNH_F_LINKDOWN|unix.RTNH_F_DEAD)
+	body.add(unix.RTA_DST, []byte{172, 16, 0, 0})
+	nexthop := make([]byte, rtnexthopLen)
+	binary.NativeEndian.PutUint16(nexthop[0:], rtnexthopLen)
+	nexthop[2] = unix.RTNH_F_LINKDOWN | unix.RTNH_F_ONLINK
+	body.add(unix.RTA_MULTIPATH, append(nexthop, nexthop...))
+
+	r, _, ok := parseRoute(body)
+	require.True(t, ok)
+	assert.Equal(t, uint32(0), binary.NativeEndian.Uint32(r.Raw[8:]))
+	multipath := r.Raw[len(r.Raw)-2*rtnexthopLen:]
+	assert.Equal(t, uint8(unix.RTNH_F_ONLINK), multipath[2])
+	assert.Equal(t, uint8(unix.RTNH_F_ONLINK), multipath[rtnexthopLen+2])
+	assert.Equal(t, uint8(unix.RTNH_F_LINKDOWN|unix.RTNH_F_DEAD), body[8], "the message must not be altered")
+}
+
+func TestInterfacesOfLoopback(t *testing.T) {
+	names, err := Interfaces("", []net.IP{net.ParseIP("127.0.0.1")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lo"}, names)
+}
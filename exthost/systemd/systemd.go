@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+// Package systemd reads and controls systemd units on the host through systemctl.
+package systemd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/steadybit/action-kit/go/action_kit_commons/utils"
+)
+
+// showChunkSize limits the number of units passed to a single `systemctl show`.
+const showChunkSize = 100
+
+var unitProperties = []string{"Id", "Description", "LoadState", "ActiveState", "SubState", "MainPID", "ControlGroup", "Restart", "NRestarts", "UnitFileState"}
+
+type Unit struct {
+	Name          string
+	Description   string
+	LoadState     string
+	ActiveState   string
+	SubState      string
+	MainPID       int
+	ControlGroup  string
+	Restart       string
+	NRestarts     int
+	UnitFileState string
+}
+
+var systemctl = func(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := utils.RootCommandContext(ctx, "systemctl", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return out, fmt.Errorf("systemctl %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+		}
+		return out, fmt.Errorf("systemctl %s: %w", strings.Join(args, " "), err)
+	}
+	return out, nil
+}
+
+// ListServices returns all service units currently loaded by systemd.
+func ListServices(ctx context.Context) ([]Unit, error) {
+	out, err := systemctl(ctx, "list-units", "--type=service", "--all", "--plain", "--no-legend", "--no-pager")
+	if err != nil {
+		return nil, err
+	}
+
+	names := parseUnitList(out)
+	var units []Unit
+	for start := 0; start < len(names); start += showChunkSize {
+		chunk, err := Show(ctx, names[start:min(start+showChunkSize, len(names))]...)
+		if err != nil {
+			return nil, err
+		}
+		for _, unit := range chunk {
+			if unit.LoadState == "loaded" {
+				units = append(units, unit)
+			}
+		}
+	}
+	return units, nil
+}
+
+// Show returns the properties of the given units.
+func Show(ctx context.Context, names ...string) ([]Unit, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	args := append([]string{"show", "--no-pager", "--property=" + strings.Join(unitProperties, ",")}, names...)
+	out, err := systemctl(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseShow(out), nil
+}
+
+// parseUnitList extracts the unit names from `systemctl list-units --plain --no-legend`.
+func parseUnitList(out []byte) []string {
+	var names []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "●"))
+		if len(fields) > 0 && strings.HasSuffix(fields[0], ".service") {
+			names = append(names, fields[0])
+		}
+	}
+	return names
+}
+
+// parseShow parses the key=value output of `systemctl show`, units are separated by empty lines.
+func parseShow(out []byte) []Unit {
+	var units []Unit
+	var current *Unit
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if current == nil {
+			units = append(units, Unit{})
+			current = &units[len(units)-1]
+		}
+		switch key {
+		case "Id":
+			current.Name = value
+		case "Description":
+			current.Description = value
+		case "LoadState":
+			current.LoadState = value
+		case "ActiveState":
+			current.ActiveState = value
+		case "SubState":
+			current.SubState = value
+		case "MainPID":
+			current.MainPID, _ = strconv.Atoi(value)
+		case "ControlGroup":
+			current.ControlGroup = value
+		case "Restart":
+			current.Restart = value
+		case "NRestarts":
+			current.NRestarts, _ = strconv.Atoi(value)
+		case "UnitFileState":
+			current.UnitFileState = value
+		}
+	}
+	return units
+}
+
+// IsActive reports whether the unit is running or on its way to it.
+func (u Unit) IsActive() bool {
+	return u.ActiveState == "active" || u.ActiveState == "activating" || u.ActiveState == "reloading"
+}
+
+// Systemctl controls units on the host.
+type Systemctl struct{}
+
+// ShowUnit returns the current properties of a single unit.
+func (Systemctl) ShowUnit(ctx context.Context, name string) (Unit, error) {
+	units, err := Show(ctx, name)
+	if err != nil {
+		return Unit{}, err
+	}
+	if len(units) != 1 {
+		return Unit{}, fmt.Errorf("unexpected output of systemctl show for %s", name)
+	}
+	return units[0], nil
+}
+
+func (Systemctl) Start(ctx context.Context, name string) error {
+	_, err := systemctl(ctx, "start", name)
+	return err
+}
+
+func (Systemctl) Stop(ctx context.Context, name string) error {
+	_, err := systemctl(ctx, "stop", name)
+	return err
+}
+
+func (Systemctl) Restart(ctx context.Context, name string) error {
+	_, err := systemctl(ctx, "restart", name)
+	return err
+}
+
+// KillMainProcess sends SIGKILL to the main process only. Unlike Stop this is an unexpected exit for
+// systemd, so the unit's Restart= policy applies.
+func (Systemctl) KillMainProcess(ctx context.Context, name string) error {
+	_, err := systemctl(ctx, "kill", "--kill-whom=main", "--signal=SIGKILL", name)
+	return err
+}
+
+// Freeze freezes all processes of the unit using the cgroup freezer. Requires systemd 246+.
+func (Systemctl) Freeze(ctx context.Context, name string) error {
+	_, err := systemctl(ctx, "freeze", name)
+	return err
+}
+
+func (Systemctl) Thaw(ctx context.Context, name string) error {
+	_, err := systemctl(ctx, "thaw", name)
+	return err
+}
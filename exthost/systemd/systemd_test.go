@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const listUnitsOutput = `  cron.service        loaded    active   running Regular background program processing daemon
+● nginx.service       loaded    failed   failed  A high performance web server
+  missing.service     not-found inactive dead    missing.service
+`
+
+const showOutput = `Id=cron.service
+Description=Regular background program processing daemon
+LoadState=loaded
+ActiveState=active
+SubState=running
+MainPID=612
+ControlGroup=/system.slice/cron.service
+Restart=on-failure
+NRestarts=2
+UnitFileState=enabled
+
+Id=nginx.service
+Description=A high performance web server
+LoadState=loaded
+ActiveState=failed
+SubState=failed
+MainPID=0
+ControlGroup=
+Restart=no
+NRestarts=0
+UnitFileState=enabled
+
+Id=missing.service
+Description=missing.service
+LoadState=not-found
+ActiveState=inactive
+SubState=dead
+MainPID=0
+ControlGroup=
+Restart=no
+NRestarts=0
+UnitFileState=
+`
+
+func stubSystemctl(t *testing.T, outputs map[string]string) {
+	previous := systemctl
+	t.Cleanup(func() { systemctl = previous })
+	systemctl = func(_ context.Context, args ...string) ([]byte, error) {
+		out, ok := outputs[args[0]]
+		if !ok {
+			return nil, fmt.Errorf("unexpected call: systemctl %s", strings.Join(args, " "))
+		}
+		return []byte(out), nil
+	}
+}
+
+func TestListServices(t *testing.T) {
+	stubSystemctl(t, map[string]string{"list-units": listUnitsOutput, "show": showOutput})
+
+	units, err := ListServices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Unit{
+		{
+			Name:          "cron.service",
+			Description:   "Regular background program processing daemon",
+			LoadState:     "loaded",
+			ActiveState:   "active",
+			SubState:      "running",
+			MainPID:       612,
+			ControlGroup:  "/system.slice/cron.service",
+			Restart:       "on-failure",
+			NRestarts:     2,
+			UnitFileState: "enabled",
+		},
+		{
+			Name:          "nginx.service",
+			Description:   "A high performance web server",
+			LoadState:     "loaded",
+			ActiveState:   "failed",
+			SubState:      "failed",
+			Restart:       "no",
+			UnitFileState: "enabled",
+		},
+	}, units)
+}
+
+func TestParseUnitList(t *testing.T) {
+	assert.Equal(t, []string{"cron.service", "nginx.service", "missing.service"}, parseUnitList([]byte(listUnitsOutput)))
+	assert.Empty(t, parseUnitList(nil))
+}
+
+func TestSystemctl_ShowUnit(t *testing.T) {
+	stubSystemctl(t, map[string]string{"show": showOutput[:strings.Index(showOutput, "\n\n")+1]})
+
+	unit, err := Systemctl{}.ShowUnit(context.Background(), "cron.service")
+	require.NoError(t, err)
+	assert.Equal(t, "cron.service", unit.Name)
+	assert.True(t, unit.IsActive())
+	assert.Equal(t, 2, unit.NRestarts)
+}
+
+func TestSystemctl_Commands(t *testing.T) {
+	var calls []string
+	previous := systemctl
+	t.Cleanup(func() { systemctl = previous })
+	systemctl = func(_ context.Context, args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		return nil, nil
+	}
+
+	ctx := context.Background()
+	s := Systemctl{}
+	require.NoError(t, s.Stop(ctx, "a.service"))
+	require.NoError(t, s.Start(ctx, "a.service"))
+	require.NoError(t, s.Restart(ctx, "a.service"))
+	require.NoError(t, s.KillMainProcess(ctx, "a.service"))
+	require.NoError(t, s.Freeze(ctx, "a.service"))
+	require.NoError(t, s.Thaw(ctx, "a.service"))
+	assert.Equal(t, []string{
+		"stop a.service",
+		"start a.service",
+		"restart a.service",
+		"kill --kill-whom=main --signal=SIGKILL a.service",
+		"freeze a.service",
+		"thaw a.service",
+	}, calls)
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package timetravel
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Reference pairs the wall clock with CLOCK_BOOTTIME, which isn't affected by setting the time.
+type Reference struct {
+	Wall time.Time
+	Boot time.Duration
+}
+
+func NewReference() (Reference, error) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_BOOTTIME, &ts); err != nil {
+		return Reference{}, err
+	}
+	// strip the monotonic reading, so Shift compares wall clocks
+	return Reference{Wall: time.Now().Round(0), Boot: time.Duration(ts.Nano())}, nil
+}
+
+// Shift returns how far the wall clock was moved since the reference was taken. It is 0 if the host was
+// rebooted meanwhile, as the clock is set anew on boot.
+func (r Reference) Shift() (time.Duration, error) {
+	now, err := NewReference()
+	if err != nil {
+		return 0, err
+	}
+	if now.Boot < r.Boot {
+		return 0, nil
+	}
+	return now.Wall.Sub(r.Wall) - (now.Boot - r.Boot), nil
+}
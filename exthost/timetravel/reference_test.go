@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package timetravel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceShift(t *testing.T) {
+	r, err := NewReference()
+	require.NoError(t, err)
+
+	shift, err := r.Shift()
+	require.NoError(t, err)
+	assert.InDelta(t, 0, shift.Seconds(), 1)
+
+	r.Wall = r.Wall.Add(-time.Hour)
+	shift, err = r.Shift()
+	require.NoError(t, err)
+	assert.InDelta(t, time.Hour.Seconds(), shift.Seconds(), 1)
+
+	r.Boot += time.Hour
+	shift, err = r.Shift()
+	require.NoError(t, err)
+	assert.Zero(t, shift)
+}
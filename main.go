@@ -4,6 +4,9 @@
 package main
 
 import (
+	"context"
+	"os"
+
 	_ "github.com/KimMachineGun/automemlimit" // By default, it sets `GOMEMLIMIT` to 90% of cgroup's memory limit.
 	"github.com/rs/zerolog"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
@@ -14,6 +17,11 @@ import (
 	"github.com/steadybit/discovery-kit/go/discovery_kit_sdk"
 	"github.com/steadybit/extension-host/config"
 	"github.com/steadybit/extension-host/exthost"
+	"github.com/steadybit/extension-host/exthost/conntrack"
+	"github.com/steadybit/extension-host/exthost/fdfill"
+	"github.com/steadybit/extension-host/exthost/link"
+	"github.com/steadybit/extension-host/exthost/pidfill"
+	"github.com/steadybit/extension-host/exthost/portfill"
 	"github.com/steadybit/extension-kit/extbuild"
 	"github.com/steadybit/extension-kit/exthealth"
 	"github.com/steadybit/extension-kit/exthttp"
@@ -32,6 +40,31 @@ func main() {
 	//  - to set the log level to debug, set the environment variable STEADYBIT_LOG_LEVEL="debug"
 	extlogging.InitZeroLog()
 
+	// `extension-host <subcommand>` runs a subcommand instead of the extension, most of them are the holders of attacks.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "revert-all":
+			// asks the running extension to revert all active attacks, see README.
+			config.ParseConfiguration()
+			os.Exit(revertAll(os.Args[2:]))
+		case "fd-fill":
+			// holds the file handles of the exhaust file descriptors attack.
+			os.Exit(fdfill.Run(os.Args[2:]))
+		case "pid-fill":
+			// holds the processes or threads of the exhaust process IDs attack.
+			os.Exit(pidfill.Run(os.Args[2:]))
+		case "port-fill":
+			// holds the connections of the exhaust ephemeral ports attack.
+			os.Exit(portfill.Run(os.Args[2:]))
+		case "conntrack":
+			// holds the synthetic entries or flushes the entries of the conntrack attack.
+			os.Exit(conntrack.Run(os.Args[2:]))
+		case "link":
+			// holds the network interface of the interface down attack down or flaps it.
+			os.Exit(link.Run(os.Args[2:]))
+		}
+	}
+
 	extruntime.AdjustOOMScoreAdj()
 
 	// Build information is set at compile-time. This line writes the build information to the log.
@@ -49,6 +82,10 @@ func main() {
 	// cloud-tuned roots survive the attack.
 	netfault.SetStrictRootQdisc(config.Config.NetworkStrictRootQdisc)
 
+	// Logs which attacks are usable on this host; the discovery publishes the result as host.capability.*
+	// attributes, which the actions require in their target type.
+	exthost.ProbeCapabilities()
+
 	//This will start /health/liveness and /health/readiness endpoints on port 8081 for use with kubernetes
 	//The port can be configured using the STEADYBIT_EXTENSION_HEALTH_PORT environment variable
 	exthealth.SetReady(false)
@@ -62,25 +99,48 @@ func main() {
 	// for your extension. You might want to change these because the names do not fit, or because
 	// you do not have a need for all of them.
 	discovery_kit_sdk.Register(exthost.NewHostDiscovery())
+	discovery_kit_sdk.Register(exthost.NewSystemdServiceDiscovery())
+	discovery_kit_sdk.Register(exthost.NewProcessDiscovery())
 	action_kit_sdk.RegisterAction(exthost.NewStressCpuAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewCpuSpeedAction())
 	action_kit_sdk.RegisterAction(exthost.NewStressMemoryAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewStressIoAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewTimetravelAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewStopProcessAction())
+	action_kit_sdk.RegisterAction(exthost.NewStopProcessTargetAction())
+	action_kit_sdk.RegisterAction(exthost.NewFreezeProcessAction())
+	action_kit_sdk.RegisterAction(exthost.NewSignalProcessAction())
+	action_kit_sdk.RegisterAction(exthost.NewThrottleProcessAction())
+	action_kit_sdk.RegisterAction(exthost.NewRlimitProcessAction())
 	action_kit_sdk.RegisterAction(exthost.NewShutdownAction())
+	action_kit_sdk.RegisterAction(exthost.NewSystemdUnitAction())
 	action_kit_sdk.RegisterAction(exthost.NewNetworkBlackholeContainerAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewNetworkTcpResetAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewNetworkLimitBandwidthContainerAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewNetworkCorruptPackagesContainerAction(r))
+	action_kit_sdk.RegisterAction(exthost.NewNetworkDuplicatePackagesContainerAction(r))
+	action_kit_sdk.RegisterAction(exthost.NewNetworkReorderPackagesContainerAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewNetworkDelayContainerAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewNetworkDNSErrorInjectionAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewNetworkBlockDnsContainerAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewNetworkPackageLossContainerAction(r))
+	action_kit_sdk.RegisterAction(exthost.NewNetworkExhaustPortsAction(r))
+	action_kit_sdk.RegisterAction(exthost.NewNetworkConntrackAction(r))
+	action_kit_sdk.RegisterAction(exthost.NewNetworkLinkDownAction(r))
+	action_kit_sdk.RegisterAction(exthost.NewNetworkRouteAction())
 	action_kit_sdk.RegisterAction(exthost.NewFillDiskHostAction(r))
 	action_kit_sdk.RegisterAction(exthost.NewFillMemoryHostAction(r))
+	action_kit_sdk.RegisterAction(exthost.NewFillFdAction(r))
+	action_kit_sdk.RegisterAction(exthost.NewFillPidsAction(r))
 
 	exthttp.RegisterRevisionedHandler("/", getExtensionList)
+	if config.Config.RevertAllToken != "" {
+		exthttp.RegisterHttpHandler(exthost.RevertAllPath, exthost.RevertAll)
+	}
+
+	// Attacks which were still active when a previous run crashed left their faults behind (tc/iptables
+	// rules, shifted clock, lowered cpu frequencies, fill files). Revert them before reporting ready.
+	exthost.RevertJournaledExecutions(context.Background())
 
 	//This will install a signal handler, that will stop active actions when receiving a SIGURS1, SIGTERM or SIGINT
 	extsignals.ActivateSignalHandlers()
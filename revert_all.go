@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/steadybit/extension-host/config"
+	"github.com/steadybit/extension-host/exthost"
+	"github.com/steadybit/extension-kit/exthttp"
+)
+
+// revertAll implements the `revert-all` subcommand. It asks the running extension to revert all active
+// executions and prints the report. Returns the exit code.
+func revertAll(args []string) int {
+	var spec exthttp.ListenSpecification
+	if err := envconfig.Process("steadybit_extension", &spec); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to parse the listen configuration: %s\n", err)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("revert-all", flag.ContinueOnError)
+	url := flags.String("url", "", "revert-all endpoint of the running extension, defaults to the address the extension listens on")
+	token := flags.String("token", config.Config.RevertAllToken, "bearer token, defaults to STEADYBIT_EXTENSION_REVERT_ALL_TOKEN")
+	timeout := flags.Duration("timeout", 2*time.Minute, "timeout for reverting all executions")
+	clientCert := flags.String("cert", "", "client certificate, if the extension requires one (STEADYBIT_EXTENSION_TLS_CLIENT_CAS)")
+	clientKey := flags.String("key", "", "key of the client certificate")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *token == "" {
+		_, _ = fmt.Fprintln(os.Stderr, "no token given, set STEADYBIT_EXTENSION_REVERT_ALL_TOKEN or use -token")
+		return 2
+	}
+
+	endpoint, client, err := revertAllClient(spec, *clientCert, *clientKey)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to prepare the request: %s\n", err)
+		return 2
+	}
+	if *url != "" {
+		endpoint = *url
+	}
+
+	reports, err := requestRevertAll(client, endpoint, *token, *timeout)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to revert all executions: %s\n", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(reports)
+
+	for _, report := range reports {
+		if !report.Reverted {
+			return 1
+		}
+	}
+	return 0
+}
+
+// revertAllClient returns the endpoint and a client to reach the extension the way it listens: on the
+// unix socket, or on the port with or without TLS.
+func revertAllClient(spec exthttp.ListenSpecification, clientCert, clientKey string) (string, *http.Client, error) {
+	if spec.UnixSocket != "" {
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", spec.UnixSocket)
+			},
+		}
+		return "http://localhost" + exthost.RevertAllPath, &http.Client{Transport: transport}, nil
+	}
+
+	port := int(config.Config.Port)
+	if spec.Port != 0 {
+		port = spec.Port
+	}
+	if spec.TlsServerCert == "" && spec.TlsServerKey == "" && len(spec.TlsClientCas) == 0 {
+		return fmt.Sprintf("http://127.0.0.1:%d%s", port, exthost.RevertAllPath), http.DefaultClient, nil
+	}
+
+	tlsConfig, err := revertAllTlsConfig(spec.TlsServerCert, clientCert, clientKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("https://127.0.0.1:%d%s", port, exthost.RevertAllPath), &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}, nil
+}
+
+// revertAllTlsConfig trusts exactly the configured server certificate, as it is usually not issued for the
+// loopback address.
+func revertAllTlsConfig(serverCert, clientCert, clientKey string) (*tls.Config, error) {
+	content, err := os.ReadFile(serverCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the server certificate: %w", err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no certificate found in %s", serverCert)
+	}
+	expected := block.Bytes
+
+	tlsConfig := &tls.Config{
+		// the peer is verified against the configured certificate below
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], expected) {
+				return errors.New("the extension presented an unexpected certificate")
+			}
+			return nil
+		},
+	}
+	if clientCert != "" {
+		cert, err := tls.LoadX509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+func requestRevertAll(client *http.Client, url, token string, timeout time.Duration) ([]exthost.RevertReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("unexpected status %s: %s", res.Status, body)
+	}
+
+	var reports []exthost.RevertReport
+	if err := json.NewDecoder(res.Body).Decode(&reports); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+	return reports, nil
+}